@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/ghodss/yaml"
 
 	"github.com/lrweck/todo/internal/rest"
@@ -23,27 +24,30 @@ func main() {
 		log.Fatalln("path is required")
 	}
 
-	swagger := rest.NewOpenAPI3()
+	generate(output, "openapi3", rest.NewOpenAPI3())
+	generate(output, "openapi3.v2", rest.NewOpenAPI3V2())
 
-	// openapi3.json
+	fmt.Println("all generated")
+}
+
+func generate(output, name string, swagger openapi3.T) {
+	// json
 	data, err := json.Marshal(&swagger)
 	if err != nil {
 		log.Fatalf("Couldn't marshal json: %s", err)
 	}
 
-	if err := os.WriteFile(path.Join(output, "openapi3.json"), data, 0600); err != nil {
+	if err := os.WriteFile(path.Join(output, name+".json"), data, 0600); err != nil {
 		log.Fatalf("Couldn't write json: %s", err)
 	}
 
-	// openapi3.yaml
+	// yaml
 	data, err = yaml.Marshal(&swagger)
 	if err != nil {
 		log.Fatalf("Couldn't marshal json: %s", err)
 	}
 
-	if err := os.WriteFile(path.Join(output, "openapi3.yaml"), data, 0600); err != nil {
+	if err := os.WriteFile(path.Join(output, name+".yaml"), data, 0600); err != nil {
 		log.Fatalf("Couldn't write json: %s", err)
 	}
-
-	fmt.Println("all generated")
 }
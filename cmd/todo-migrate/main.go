@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/db/migrations"
+	"github.com/lrweck/todo/internal/envvar"
+	"github.com/lrweck/todo/internal/repository/postgresql"
+)
+
+const usage = `Usage: todo-migrate [flags] <command>
+
+Commands:
+  up          Apply all the pending migrations
+  down [N]    Revert the last N migrations, defaults to 1; use "all" for reverting all of them
+  goto V      Migrate up or down to version V
+  version     Print the current version
+  force V     Set version V without migrating and clear the dirty flag, use -1 for no migration
+
+Flags:
+`
+
+// Config defines the configuration read from the environment.
+type Config struct {
+	DatabaseURL url.URL `env:"DATABASE_URL" required:"true"`
+}
+
+func main() {
+	var env string
+
+	flag.StringVar(&env, "env", "", "Environment variables filename")
+	printConfig := envvar.PrintConfigFlag(flag.CommandLine)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if env != "" {
+		if err := envvar.Load(env); err != nil {
+			log.Fatalf("Couldn't load env file: %s", err)
+		}
+	}
+
+	var cfg Config
+
+	// XXX: No secrets provider, "<KEY>_FILE" is supported though.
+	if err := envvar.New(envvar.NewChain()).Decode(&cfg); err != nil {
+		log.Fatalf("Couldn't read configuration: %s", err)
+	}
+
+	if *printConfig {
+		if err := envvar.Print(os.Stdout, &cfg); err != nil {
+			log.Fatalf("Couldn't print configuration: %s", err)
+		}
+
+		return
+	}
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Couldn't create logger: %s", err)
+	}
+
+	defer logger.Sync() //nolint:errcheck
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	migrator := postgresql.NewMigrator(logger, cfg.DatabaseURL.String(), migrations.FS)
+
+	if err := run(ctx, migrator, flag.Arg(0), flag.Arg(1)); err != nil {
+		logger.Fatal("Couldn't migrate", zap.String("command", flag.Arg(0)), zap.Error(err))
+	}
+}
+
+func run(ctx context.Context, migrator *postgresql.Migrator, cmd, arg string) error {
+	switch cmd {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+
+		switch arg {
+		case "":
+		case "all":
+			steps = 0
+		default:
+			n, err := strconv.Atoi(arg)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid number of migrations %q", arg)
+			}
+
+			steps = n
+		}
+
+		return migrator.Down(ctx, steps)
+	case "goto":
+		version, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", arg)
+		}
+
+		return migrator.Goto(ctx, uint(version))
+	case "force":
+		version, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", arg)
+		}
+
+		return migrator.Force(ctx, version)
+	case "version":
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+
+		latest, err := migrator.Latest()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("version: %d, dirty: %t, latest: %d\n", version, dirty, latest)
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown command %q", cmd)
+}
@@ -0,0 +1,11 @@
+CREATE TABLE idempotency_keys (
+  key         VARCHAR(255) PRIMARY KEY,
+  fingerprint VARCHAR NOT NULL,
+  status_code INTEGER,
+  headers     JSONB,
+  body        BYTEA,
+  expires_at  TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+  owner       UUID NOT NULL
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
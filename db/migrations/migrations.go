@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL files used for migrating the PostgreSQL database.
+package migrations
+
+import "embed"
+
+// FS contains the migrations, named "<version>_<title>.<up|down>.sql".
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,15 @@
+package internal
+
+import (
+	"time"
+)
+
+// Credentials are short-lived credentials, like the ones created by the Vault database secrets engine, valid
+// until their lease expires.
+type Credentials struct {
+	Username      string
+	Password      string
+	LeaseID       string
+	LeaseDuration time.Duration
+	Renewable     bool
+}
@@ -0,0 +1,76 @@
+package envvar
+
+import (
+	"sync"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// NamedProvider is a Provider included in a Chain, the name is used for reporting where values come from.
+type NamedProvider struct {
+	Name     string
+	Provider Provider
+}
+
+// Chain is a Provider trying several providers in order, the first one that has the value wins.
+type Chain struct {
+	providers []NamedProvider
+
+	mu      sync.Mutex
+	sources map[string]string
+}
+
+// NewChain instantiates the Chain, providers are tried in the received order.
+func NewChain(providers ...NamedProvider) *Chain {
+	return &Chain{
+		providers: providers,
+		sources:   make(map[string]string),
+	}
+}
+
+// Get returns the value from the first provider that has it, providers that fail with ErrCodeNotFound are
+// skipped; any other error is returned right away to avoid falling back to a different value silently.
+func (c *Chain) Get(v string) (string, error) {
+	for _, p := range c.providers {
+		val, err := p.Provider.Get(v)
+		if err != nil {
+			if internal.Code(err) == internal.ErrCodeNotFound {
+				continue
+			}
+
+			return "", internal.WrapErrorf(err, internal.Code(err), "%s.Get", p.Name)
+		}
+
+		c.mu.Lock()
+		c.sources[v] = p.Name
+		c.mu.Unlock()
+
+		return val, nil
+	}
+
+	return "", internal.NewErrorf(internal.ErrCodeNotFound, "%s not found in any provider", v)
+}
+
+// Source returns the name of the provider that returned the value the last time it was requested.
+func (c *Chain) Source(v string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name, ok := c.sources[v]
+
+	return name, ok
+}
+
+// Sources returns the name of the provider of every value requested so far.
+func (c *Chain) Sources() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := make(map[string]string, len(c.sources))
+
+	for k, v := range c.sources {
+		res[k] = v
+	}
+
+	return res
+}
@@ -0,0 +1,139 @@
+package envvar_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/envvar"
+	"github.com/lrweck/todo/internal/envvar/envvartesting"
+)
+
+func TestChain_Get(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "database_password"), []byte("from file\n"), 0o600); err != nil {
+		t.Fatalf("couldn't write file: %s", err)
+	}
+
+	type output struct {
+		val     string
+		source  string
+		withErr bool
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(*envvartesting.FakeProvider)
+		input  string
+		output output
+	}{
+		{
+			"OK: first provider",
+			func(p *envvartesting.FakeProvider) {
+				p.GetReturns("from vault", nil)
+			},
+			"database_password",
+			output{
+				val:    "from vault",
+				source: "vault",
+			},
+		},
+		{
+			"OK: fallback",
+			func(p *envvartesting.FakeProvider) {
+				p.GetReturns("", internal.NewErrorf(internal.ErrCodeNotFound, "not found"))
+			},
+			"database_password",
+			output{
+				val:    "from file",
+				source: "file",
+			},
+		},
+		{
+			"ERR: not found",
+			func(p *envvartesting.FakeProvider) {
+				p.GetReturns("", internal.NewErrorf(internal.ErrCodeNotFound, "not found"))
+			},
+			"missing",
+			output{
+				withErr: true,
+			},
+		},
+		{
+			"ERR: provider failed",
+			func(p *envvartesting.FakeProvider) {
+				p.GetReturns("", errors.New("sealed"))
+			},
+			"database_password",
+			output{
+				withErr: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := envvartesting.FakeProvider{}
+			tt.setup(&provider)
+
+			chain := envvar.NewChain(
+				envvar.NamedProvider{Name: "vault", Provider: &provider},
+				envvar.NamedProvider{Name: "file", Provider: envvar.NewFileProvider(dir)},
+			)
+
+			actual, err := chain.Get(tt.input)
+			if (err != nil) != tt.output.withErr {
+				t.Fatalf("expected error %t, got %s", tt.output.withErr, err)
+			}
+
+			if actual != tt.output.val {
+				t.Fatalf("expected %s, got %s", tt.output.val, actual)
+			}
+
+			source, _ := chain.Source(tt.input)
+			if source != tt.output.source {
+				t.Fatalf("expected source %s, got %s", tt.output.source, source)
+			}
+
+			if !tt.output.withErr {
+				expected := map[string]string{tt.input: tt.output.source}
+
+				if !cmp.Equal(expected, chain.Sources()) {
+					t.Fatalf("expected sources don't match: %s", cmp.Diff(expected, chain.Sources()))
+				}
+			}
+		})
+	}
+}
+
+func TestFileProvider_Get(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "secret"), []byte("value\n"), 0o600); err != nil {
+		t.Fatalf("couldn't write file: %s", err)
+	}
+
+	provider := envvar.NewFileProvider(filepath.Join(dir, "nested"))
+
+	if _, err := provider.Get("../secret"); internal.Code(err) != internal.ErrCodeNotFound {
+		t.Fatalf("expected files outside the directory to be not found, got %v", err)
+	}
+
+	actual, err := envvar.NewFileProvider(dir).Get("secret")
+	if err != nil || actual != "value" {
+		t.Fatalf("expected value, got %s (%v)", actual, err)
+	}
+}
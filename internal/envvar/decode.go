@@ -0,0 +1,248 @@
+package envvar
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lrweck/todo/internal"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// FieldError describes a missing or invalid configuration value.
+type FieldError struct {
+	Key string
+	Err error
+}
+
+// Error returns the key and the reason.
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Err)
+}
+
+// DecodeError aggregates all the missing or invalid values found by Decode.
+type DecodeError struct {
+	Fields []FieldError
+}
+
+// Error returns all the reasons.
+func (e *DecodeError) Error() string {
+	res := make([]string, len(e.Fields))
+
+	for i, f := range e.Fields {
+		res[i] = f.Error()
+	}
+
+	return strings.Join(res, "; ")
+}
+
+// Decode populates the struct pointed by dst using the environment variables indicated by the tags of its fields:
+//
+//	env:"KEY"        name of the environment variable, read using Get so "<KEY>_SECURE" is honored
+//	default:"value"  used when the environment variable is empty
+//	required:"true"  the value can't be empty
+//	secure:"true"    the value is a secret, Print redacts it
+//	prefix:"NAME_"   used on nested structs, prepended to the keys of their fields
+//
+// Supported types are strings, booleans, numbers, time.Duration, url.URL, types implementing
+// encoding.TextUnmarshaler, pointers to them and slices of them using comma-separated values. All the missing or
+// invalid values are returned together in a *DecodeError.
+func (c *Configuration) Decode(dst interface{}) error {
+	_, err := c.DecodeWithSources(dst)
+
+	return err
+}
+
+// DecodeWithSources decodes like Decode does, it also returns where each value comes from, indexed by key. See
+// Lookup for the possible sources.
+func (c *Configuration) DecodeWithSources(dst interface{}) (map[string]string, error) {
+	val := reflect.ValueOf(dst)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return nil, internal.NewErrorf(internal.ErrCodeInvalidArgument, "expected pointer to struct, got %T", dst)
+	}
+
+	var derr DecodeError
+
+	sources := make(map[string]string)
+
+	walk(val.Elem(), "", func(f field) {
+		raw, source, err := c.Lookup(f.key)
+		if err != nil {
+			derr.Fields = append(derr.Fields, FieldError{Key: f.key, Err: err})
+
+			return
+		}
+
+		if raw == "" && f.def != "" {
+			raw, source = f.def, SourceDefault
+		}
+
+		sources[f.key] = source
+
+		if raw == "" {
+			if f.required {
+				derr.Fields = append(derr.Fields, FieldError{Key: f.key, Err: fmt.Errorf("missing value")})
+			}
+
+			return
+		}
+
+		if err := setValue(f.value, raw); err != nil {
+			derr.Fields = append(derr.Fields, FieldError{Key: f.key, Err: err})
+		}
+	})
+
+	if len(derr.Fields) > 0 {
+		return nil, internal.WrapErrorf(&derr, internal.ErrCodeInvalidArgument, "invalid configuration")
+	}
+
+	return sources, nil
+}
+
+// field is a struct field tagged with "env".
+type field struct {
+	key      string
+	def      string
+	required bool
+	secure   bool
+	value    reflect.Value
+}
+
+// walk calls fn for each field tagged with "env", nested structs are walked recursively.
+func walk(val reflect.Value, prefix string, fn func(field)) {
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+
+		key, ok := sf.Tag.Lookup("env")
+		if !ok {
+			if sf.Type.Kind() == reflect.Struct && !isScalar(sf.Type) {
+				walk(val.Field(i), prefix+sf.Tag.Get("prefix"), fn)
+			}
+
+			continue
+		}
+
+		fn(field{
+			key:      prefix + key,
+			def:      sf.Tag.Get("default"),
+			required: sf.Tag.Get("required") == "true",
+			secure:   sf.Tag.Get("secure") == "true",
+			value:    val.Field(i),
+		})
+	}
+}
+
+// isScalar indicates whether the struct type is decoded from a single value.
+func isScalar(typ reflect.Type) bool {
+	return typ == urlType || reflect.PtrTo(typ).Implements(textUnmarshalerType)
+}
+
+func setValue(val reflect.Value, raw string) error {
+	if val.Kind() == reflect.Ptr {
+		ptr := reflect.New(val.Type().Elem())
+
+		if err := setValue(ptr.Elem(), raw); err != nil {
+			return err
+		}
+
+		val.Set(ptr)
+
+		return nil
+	}
+
+	if u, ok := val.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+
+	switch {
+	case val.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+
+		val.SetInt(int64(d))
+
+		return nil
+	case val.Type() == urlType:
+		u, err := url.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid url %q", raw)
+		}
+
+		val.Set(reflect.ValueOf(*u))
+
+		return nil
+	}
+
+	switch val.Kind() { //nolint:exhaustive
+	case reflect.String:
+		val.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+
+		val.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, val.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+
+		val.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, val.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+
+		val.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, val.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+
+		val.SetFloat(n)
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		res := reflect.MakeSlice(val.Type(), 0, len(parts))
+
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			elem := reflect.New(val.Type().Elem()).Elem()
+
+			if err := setValue(elem, part); err != nil {
+				return err
+			}
+
+			res = reflect.Append(res, elem)
+		}
+
+		val.Set(res)
+	default:
+		return fmt.Errorf("unsupported type %s", val.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+// Package encrypted implements a Provider reading values from files encrypted with age, see
+// https://age-encryption.org. This allows committing secrets to the repository.
+package encrypted
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/joho/godotenv"
+
+	"github.com/lrweck/todo/internal"
+)
+
+const (
+	binaryHeader = "age-encryption.org/"
+	armorHeader  = "-----BEGIN AGE ENCRYPTED FILE-----"
+	valuePrefix  = "ENC["
+	valueSuffix  = "]"
+)
+
+// Provider returns the values of a decrypted env file. Two layouts are supported:
+//
+//   - The whole file is encrypted, using the binary or the armored format.
+//   - Only the values are encrypted, like SOPS does, so changes to the keys are visible in diffs; encrypted values
+//     are wrapped as "ENC[<base64 ciphertext>]", see EncryptValue.
+type Provider struct {
+	values map[string]string
+}
+
+// New decrypts the file using the identities, all the values are decrypted upfront.
+func New(filename string, identities ...age.Identity) (*Provider, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "os.ReadFile")
+	}
+
+	if isEncrypted(content) {
+		if content, err = decrypt(content, identities); err != nil {
+			return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "decrypt")
+		}
+	}
+
+	values, err := godotenv.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "godotenv.Parse")
+	}
+
+	for k, v := range values {
+		if !strings.HasPrefix(v, valuePrefix) || !strings.HasSuffix(v, valueSuffix) {
+			continue
+		}
+
+		ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(v, valuePrefix), valueSuffix))
+		if err != nil {
+			return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid encrypted value %s", k)
+		}
+
+		val, err := decrypt(ciphertext, identities)
+		if err != nil {
+			return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "decrypting %s", k)
+		}
+
+		values[k] = string(val)
+	}
+
+	return &Provider{
+		values: values,
+	}, nil
+}
+
+// Get returns the decrypted value of the key.
+func (p *Provider) Get(v string) (string, error) {
+	val, ok := p.values[v]
+	if !ok {
+		return "", internal.NewErrorf(internal.ErrCodeNotFound, "key not found")
+	}
+
+	return val, nil
+}
+
+// ParseIdentityFile reads the identities from a file, like the ones created with "age-keygen".
+func ParseIdentityFile(filename string) ([]age.Identity, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "os.Open")
+	}
+
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "age.ParseIdentities")
+	}
+
+	return identities, nil
+}
+
+// EncryptValue encrypts the value for the recipients, the result is meant to be used as value in env files.
+func EncryptValue(value string, recipients ...age.Recipient) (string, error) {
+	var buf bytes.Buffer
+
+	w, err := age.Encrypt(&buf, recipients...)
+	if err != nil {
+		return "", internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "age.Encrypt")
+	}
+
+	if _, err := io.WriteString(w, value); err != nil {
+		return "", internal.WrapErrorf(err, internal.ErrCodeUnknown, "io.WriteString")
+	}
+
+	if err := w.Close(); err != nil {
+		return "", internal.WrapErrorf(err, internal.ErrCodeUnknown, "Close")
+	}
+
+	return valuePrefix + base64.StdEncoding.EncodeToString(buf.Bytes()) + valueSuffix, nil
+}
+
+func isEncrypted(content []byte) bool {
+	return bytes.HasPrefix(content, []byte(binaryHeader)) ||
+		bytes.HasPrefix(bytes.TrimSpace(content), []byte(armorHeader))
+}
+
+func decrypt(ciphertext []byte, identities []age.Identity) ([]byte, error) {
+	var src io.Reader = bytes.NewReader(ciphertext)
+
+	if bytes.HasPrefix(bytes.TrimSpace(ciphertext), []byte(armorHeader)) {
+		src = armor.NewReader(bytes.NewReader(bytes.TrimSpace(ciphertext)))
+	}
+
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "age.Decrypt")
+	}
+
+	res, err := io.ReadAll(r)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "io.ReadAll")
+	}
+
+	return res, nil
+}
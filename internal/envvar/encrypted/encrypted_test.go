@@ -0,0 +1,213 @@
+package encrypted_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/envvar/encrypted"
+)
+
+func TestProvider_Get(t *testing.T) {
+	t.Parallel()
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("couldn't generate identity: %s", err)
+	}
+
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("couldn't generate identity: %s", err)
+	}
+
+	password, err := encrypted.EncryptValue("s3cr3t", identity.Recipient())
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	env := "DATABASE_USERNAME=user\nDATABASE_PASSWORD=" + password + "\n"
+
+	type output struct {
+		val         string
+		withErr     bool
+		withInitErr bool
+	}
+
+	tests := []struct {
+		name       string
+		content    func(t *testing.T) []byte
+		identities []age.Identity
+		input      string
+		output     output
+	}{
+		{
+			"OK: encrypted values",
+			func(t *testing.T) []byte {
+				t.Helper()
+
+				return []byte(env)
+			},
+			[]age.Identity{identity},
+			"DATABASE_PASSWORD",
+			output{
+				val: "s3cr3t",
+			},
+		},
+		{
+			"OK: plain value",
+			func(t *testing.T) []byte {
+				t.Helper()
+
+				return []byte(env)
+			},
+			[]age.Identity{identity},
+			"DATABASE_USERNAME",
+			output{
+				val: "user",
+			},
+		},
+		{
+			"OK: encrypted file",
+			func(t *testing.T) []byte {
+				t.Helper()
+
+				return encrypt(t, false, "DATABASE_PASSWORD=password\n", identity.Recipient())
+			},
+			[]age.Identity{identity},
+			"DATABASE_PASSWORD",
+			output{
+				val: "password",
+			},
+		},
+		{
+			"OK: armored file",
+			func(t *testing.T) []byte {
+				t.Helper()
+
+				return encrypt(t, true, "DATABASE_PASSWORD=armored\n", identity.Recipient())
+			},
+			[]age.Identity{identity},
+			"DATABASE_PASSWORD",
+			output{
+				val: "armored",
+			},
+		},
+		{
+			"ERR: not found",
+			func(t *testing.T) []byte {
+				t.Helper()
+
+				return []byte(env)
+			},
+			[]age.Identity{identity},
+			"DATABASE_HOST",
+			output{
+				withErr: true,
+			},
+		},
+		{
+			"ERR: wrong identity",
+			func(t *testing.T) []byte {
+				t.Helper()
+
+				return []byte(env)
+			},
+			[]age.Identity{other},
+			"DATABASE_PASSWORD",
+			output{
+				withInitErr: true,
+			},
+		},
+		{
+			"ERR: invalid encrypted value",
+			func(t *testing.T) []byte {
+				t.Helper()
+
+				return []byte("DATABASE_PASSWORD=ENC[invalid]\n")
+			},
+			[]age.Identity{identity},
+			"DATABASE_PASSWORD",
+			output{
+				withInitErr: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filename := filepath.Join(t.TempDir(), "env")
+
+			if err := os.WriteFile(filename, tt.content(t), 0o600); err != nil {
+				t.Fatalf("couldn't write file: %s", err)
+			}
+
+			provider, err := encrypted.New(filename, tt.identities...)
+			if (err != nil) != tt.output.withInitErr {
+				t.Fatalf("expected error %t, got %s", tt.output.withInitErr, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			actual, err := provider.Get(tt.input)
+			if (err != nil) != tt.output.withErr {
+				t.Fatalf("expected error %t, got %s", tt.output.withErr, err)
+			}
+
+			if err != nil && internal.Code(err) != internal.ErrCodeNotFound {
+				t.Fatalf("expected not found error, got %s", internal.Code(err))
+			}
+
+			if actual != tt.output.val {
+				t.Fatalf("expected %s, got %s", tt.output.val, actual)
+			}
+		})
+	}
+}
+
+func encrypt(t *testing.T, armored bool, plaintext string, recipients ...age.Recipient) []byte {
+	t.Helper()
+
+	var (
+		buf bytes.Buffer
+		dst io.Writer = &buf
+	)
+
+	var aw io.WriteCloser
+
+	if armored {
+		aw = armor.NewWriter(&buf)
+		dst = aw
+	}
+
+	w, err := age.Encrypt(dst, recipients...)
+	if err != nil {
+		t.Fatalf("couldn't encrypt: %s", err)
+	}
+
+	_, _ = io.WriteString(w, plaintext)
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("couldn't close: %s", err)
+	}
+
+	if aw != nil {
+		if err := aw.Close(); err != nil {
+			t.Fatalf("couldn't close armor: %s", err)
+		}
+	}
+
+	return buf.Bytes()
+}
@@ -18,9 +18,20 @@ type Provider interface {
 	Get(key string) (string, error)
 }
 
+// Sources of the values, returned by Lookup.
+const (
+	SourceUnset   = "unset"
+	SourceDefault = "default"
+	SourceEnv     = "env"
+	SourceFile    = "file"
+	SourceSecure  = "secure"
+)
+
 // Configuration ...
 type Configuration struct {
 	provider Provider
+	values   map[string]string
+	source   string
 }
 
 // Load read the env filename and load it into ENV for this process.
@@ -39,20 +50,70 @@ func New(provider Provider) *Configuration {
 	}
 }
 
-// Get returns the value from environment variable `<key>`. When an environment variable `<key>_SECURE` exists
-// the provider is used for getting the value.
+// WithValues returns a copy of the Configuration looking up the keys in values before the environment
+// variables, "source" names where the values come from, like a file.
+func (c *Configuration) WithValues(source string, values map[string]string) *Configuration {
+	return &Configuration{
+		provider: c.provider,
+		values:   values,
+		source:   source,
+	}
+}
+
+// Get returns the value from environment variable `<key>`. When an environment variable `<key>_FILE` exists
+// the value is read from the file it indicates, like Docker and Kubernetes secrets. When an environment variable
+// `<key>_SECURE` exists the provider is used for getting the value, it takes precedence over the other two.
 func (c *Configuration) Get(key string) (string, error) {
-	res := os.Getenv(key)
-	valSecret := os.Getenv(fmt.Sprintf("%s_SECURE", key))
+	res, _, err := c.Lookup(key)
+
+	return res, err
+}
+
+// Lookup returns the value like Get does, together with where it comes from. When the provider reports
+// sources, like Chain, its source is included as "secure:<name>".
+func (c *Configuration) Lookup(key string) (string, string, error) {
+	res, source := c.getenv(key), SourceEnv
+	if res == "" {
+		source = SourceUnset
+	}
+
+	if _, ok := c.values[key]; ok {
+		source = c.source
+	}
+
+	if filename := c.getenv(fmt.Sprintf("%s_FILE", key)); filename != "" {
+		val, err := readFile(filename)
+		if err != nil {
+			return "", "", internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "readFile")
+		}
+
+		res, source = val, SourceFile
+	}
+
+	valSecret := c.getenv(fmt.Sprintf("%s_SECURE", key))
 
 	if valSecret != "" {
 		valSecretRes, err := c.provider.Get(valSecret)
 		if err != nil {
-			return "", internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "provider.Get")
+			return "", "", internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "provider.Get")
 		}
 
-		res = valSecretRes
+		res, source = valSecretRes, SourceSecure
+
+		if sp, ok := c.provider.(interface{ Source(v string) (string, bool) }); ok {
+			if name, ok := sp.Source(valSecret); ok {
+				source = SourceSecure + ":" + name
+			}
+		}
+	}
+
+	return res, source, nil
+}
+
+func (c *Configuration) getenv(key string) string {
+	if val, ok := c.values[key]; ok {
+		return val
 	}
 
-	return res, nil
+	return os.Getenv(key)
 }
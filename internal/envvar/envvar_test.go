@@ -58,6 +58,41 @@ func TestConfiguration_Get(t *testing.T) {
 			},
 			"/secret/value",
 		},
+		{
+			"OK: file",
+			func(_ *envvartesting.FakeProvider) func() {
+				f, _ := os.CreateTemp("", "envvar")
+				_, _ = f.WriteString("file value\n")
+				f.Close()
+
+				os.Setenv("ENVVAR_OK2_FILE", f.Name())
+
+				return func() {
+					os.Remove(f.Name())
+					os.Setenv("ENVVAR_OK2_FILE", "")
+				}
+			},
+			"ENVVAR_OK2",
+			output{
+				val: "file value",
+			},
+			"",
+		},
+		{
+			"ERR: file not found",
+			func(_ *envvartesting.FakeProvider) func() {
+				os.Setenv("ENVVAR_ERR1_FILE", "/missing/file")
+
+				return func() {
+					os.Setenv("ENVVAR_ERR1_FILE", "")
+				}
+			},
+			"ENVVAR_ERR1",
+			output{
+				withErr: true,
+			},
+			"",
+		},
 		{
 			"ERR: provider failed",
 			func(p *envvartesting.FakeProvider) func() {
@@ -0,0 +1,50 @@
+package envvar
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// FileProvider reads the values from files in a directory, one file per value, like the secrets mounted by
+// Docker in "/run/secrets" or by Kubernetes volumes.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider instantiates the FileProvider reading files in dir.
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{
+		dir: dir,
+	}
+}
+
+// Get returns the content of the file named v, relative to the directory.
+func (p *FileProvider) Get(v string) (string, error) {
+	// XXX: Clean removes "..", so the file is always in the directory.
+	val, err := readFile(filepath.Join(p.dir, filepath.Clean("/"+v)))
+	if err != nil {
+		return "", internal.WrapErrorf(err, internal.Code(err), "readFile")
+	}
+
+	return val, nil
+}
+
+// readFile returns the content of the file without the trailing newline most editors add.
+func readFile(filename string) (string, error) {
+	val, err := os.ReadFile(filename)
+	if err != nil {
+		code := internal.ErrCodeUnknown
+		if errors.Is(err, fs.ErrNotExist) {
+			code = internal.ErrCodeNotFound
+		}
+
+		return "", internal.WrapErrorf(err, code, "os.ReadFile")
+	}
+
+	return strings.TrimRight(string(val), "\r\n"), nil
+}
@@ -0,0 +1,111 @@
+package envvar
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/lrweck/todo/internal"
+)
+
+const redacted = "[REDACTED]"
+
+// PrintConfigFlag defines the "print-config" flag, commands supporting it print their configuration using Print
+// and exit when it's set.
+func PrintConfigFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("print-config", false, "Print the configuration, with secrets redacted, and exit")
+}
+
+// Print writes the values of the struct populated by Decode, one "KEY=value" per line, values of fields tagged
+// with `secure:"true"` are redacted as well as URL passwords.
+func Print(w io.Writer, cfg interface{}) error {
+	var err error
+
+	if verr := visit(cfg, func(key, val string) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, "%s=%s\n", key, val)
+		}
+	}); verr != nil {
+		return verr
+	}
+
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "fmt.Fprintf")
+	}
+
+	return nil
+}
+
+// Values returns the values of the struct populated by Decode indexed by key, redacted like Print does.
+func Values(cfg interface{}) (map[string]string, error) {
+	res := make(map[string]string)
+
+	if err := visit(cfg, func(key, val string) { res[key] = val }); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// visit calls fn with the formatted and redacted value of each field.
+func visit(cfg interface{}, fn func(key, val string)) error {
+	val := reflect.ValueOf(cfg)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return internal.NewErrorf(internal.ErrCodeInvalidArgument, "expected struct, got %T", cfg)
+	}
+
+	walk(val, "", func(f field) {
+		res := formatValue(f.value)
+		if f.secure && res != "" {
+			res = redacted
+		}
+
+		fn(f.key, res)
+	})
+
+	return nil
+}
+
+func formatValue(val reflect.Value) string {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return ""
+		}
+
+		return formatValue(val.Elem())
+	}
+
+	if val.Type() == urlType {
+		u := val.Interface().(url.URL) //nolint:forcetypeassert
+
+		return u.Redacted()
+	}
+
+	if val.CanAddr() {
+		if m, ok := val.Addr().Interface().(encoding.TextMarshaler); ok {
+			res, _ := m.MarshalText()
+
+			return string(res)
+		}
+	}
+
+	if val.Kind() == reflect.Slice {
+		res := make([]string, val.Len())
+
+		for i := 0; i < val.Len(); i++ {
+			res[i] = formatValue(val.Index(i))
+		}
+
+		return strings.Join(res, ",")
+	}
+
+	return fmt.Sprint(val.Interface())
+}
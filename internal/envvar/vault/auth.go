@@ -0,0 +1,115 @@
+package vault
+
+import (
+	"os"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+
+	"github.com/lrweck/todo/internal"
+)
+
+const defaultKubernetesJWTPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// AuthMethod logs in to Vault, the returned secret includes the token as well as its lease used for renewing it.
+type AuthMethod interface {
+	Login(client *api.Client) (*api.SecretAuth, error)
+}
+
+// TokenAuth authenticates using a token created out of band.
+type TokenAuth struct {
+	Token string
+}
+
+// Login looks up the token for determining whether it can be renewed.
+func (a TokenAuth) Login(client *api.Client) (*api.SecretAuth, error) {
+	client.SetToken(a.Token)
+
+	secret, err := client.Auth().Token().LookupSelf()
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnauthenticated, "Token.LookupSelf")
+	}
+
+	renewable, err := secret.TokenIsRenewable()
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "secret.TokenIsRenewable")
+	}
+
+	ttl, err := secret.TokenTTL()
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "secret.TokenTTL")
+	}
+
+	return &api.SecretAuth{
+		ClientToken:   a.Token,
+		Renewable:     renewable,
+		LeaseDuration: int(ttl.Seconds()),
+	}, nil
+}
+
+// AppRoleAuth authenticates using the AppRole auth method.
+type AppRoleAuth struct {
+	// Mount is the path where the auth method is enabled, defaults to "approle".
+	Mount    string
+	RoleID   string
+	SecretID string
+}
+
+// Login ...
+func (a AppRoleAuth) Login(client *api.Client) (*api.SecretAuth, error) {
+	return login(client, mountOrDefault(a.Mount, "approle"), map[string]interface{}{
+		"role_id":   a.RoleID,
+		"secret_id": a.SecretID,
+	})
+}
+
+// KubernetesAuth authenticates using the Kubernetes auth method, with the token of the Pod's service account.
+type KubernetesAuth struct {
+	// Mount is the path where the auth method is enabled, defaults to "kubernetes".
+	Mount string
+	Role  string
+	// JWTPath is the file containing the service account token, defaults to the one mounted by Kubernetes.
+	JWTPath string
+}
+
+// Login reads the service account token every time because Kubernetes rotates it.
+func (a KubernetesAuth) Login(client *api.Client) (*api.SecretAuth, error) {
+	path := a.JWTPath
+	if path == "" {
+		path = defaultKubernetesJWTPath
+	}
+
+	jwt, err := os.ReadFile(path)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnauthenticated, "os.ReadFile")
+	}
+
+	return login(client, mountOrDefault(a.Mount, "kubernetes"), map[string]interface{}{
+		"role": a.Role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+}
+
+func login(client *api.Client, mount string, data map[string]interface{}) (*api.SecretAuth, error) {
+	// XXX: Logging in with an expired token fails, the login endpoints don't need one anyway.
+	client.ClearToken()
+
+	secret, err := client.Logical().Write("auth/"+mount+"/login", data)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnauthenticated, "Logical.Write")
+	}
+
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, internal.NewErrorf(internal.ErrCodeUnauthenticated, "login did not return a token")
+	}
+
+	return secret.Auth, nil
+}
+
+func mountOrDefault(mount, def string) string {
+	if mount == "" {
+		return def
+	}
+
+	return strings.Trim(mount, "/")
+}
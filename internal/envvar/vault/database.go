@@ -0,0 +1,82 @@
+package vault
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// DatabaseCredentials creates short-lived credentials using the database secrets engine.
+type DatabaseCredentials struct {
+	client *api.Client
+	mount  string
+	role   string
+}
+
+// DatabaseCredentials returns the DatabaseCredentials of the role, using the engine mounted at "mount", defaults
+// to "database". Requests are authenticated with the Provider's token.
+func (p *Provider) DatabaseCredentials(mount, role string) *DatabaseCredentials {
+	return &DatabaseCredentials{
+		client: p.client,
+		mount:  mountOrDefault(mount, "database"),
+		role:   role,
+	}
+}
+
+// Fetch creates new credentials.
+func (d *DatabaseCredentials) Fetch() (internal.Credentials, error) {
+	secret, err := d.client.Logical().Read(fmt.Sprintf("%s/creds/%s", d.mount, d.role))
+	if err != nil {
+		return internal.Credentials{}, internal.WrapErrorf(err, internal.ErrCodeUnavailable, "reading")
+	}
+
+	if secret == nil {
+		return internal.Credentials{}, internal.NewErrorf(internal.ErrCodeNotFound, "role not found")
+	}
+
+	username, _ := secret.Data["username"].(string)
+	password, _ := secret.Data["password"].(string)
+
+	if username == "" || password == "" {
+		return internal.Credentials{}, internal.NewErrorf(internal.ErrCodeUnknown, "invalid data in secret")
+	}
+
+	return internal.Credentials{
+		Username:      username,
+		Password:      password,
+		LeaseID:       secret.LeaseID,
+		LeaseDuration: time.Duration(secret.LeaseDuration) * time.Second,
+		Renewable:     secret.Renewable,
+	}, nil
+}
+
+// Renew extends the lease by increment, the returned credentials include the new lease duration which is
+// shorter than requested when the lease reaches its maximum TTL.
+func (d *DatabaseCredentials) Renew(creds internal.Credentials, increment time.Duration) (internal.Credentials, error) {
+	secret, err := d.client.Sys().Renew(creds.LeaseID, int(increment.Seconds()))
+	if err != nil {
+		return internal.Credentials{}, internal.WrapErrorf(err, internal.ErrCodeUnavailable, "Sys.Renew")
+	}
+
+	creds.LeaseDuration = time.Duration(secret.LeaseDuration) * time.Second
+	creds.Renewable = secret.Renewable
+
+	return creds, nil
+}
+
+// Revoke revokes the lease, Vault drops the database user.
+func (d *DatabaseCredentials) Revoke(creds internal.Credentials) error {
+	if strings.TrimSpace(creds.LeaseID) == "" {
+		return nil
+	}
+
+	if err := d.client.Sys().Revoke(creds.LeaseID); err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeUnavailable, "Sys.Revoke")
+	}
+
+	return nil
+}
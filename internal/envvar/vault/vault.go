@@ -1,25 +1,79 @@
 package vault
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/vault/api"
+	"go.uber.org/zap"
 
 	"github.com/lrweck/todo/internal"
 )
 
-// Provider ...
+// Provider retrieves secrets from the Vault KV engine, secrets are cached until their lease expires or until
+// the refresh interval passes, whichever happens first.
 type Provider struct {
-	path    string
-	client  *api.Logical
-	results map[string]map[string]string
+	client   *api.Client
+	path     string
+	version  int
+	auth     AuthMethod
+	interval time.Duration
+	logger   *zap.Logger
+
 	mu      sync.Mutex
+	token   *api.SecretAuth
+	results map[string]result
+	subs    map[string]map[*Subscription]struct{}
+}
+
+type result struct {
+	values  map[string]string
+	expires time.Time
+}
+
+// Option defines the options used by Provider.
+type Option func(*Provider)
+
+// WithAuth indicates the auth method used for logging in, defaults to the token in "VAULT_TOKEN".
+func WithAuth(auth AuthMethod) Option {
+	return func(p *Provider) {
+		p.auth = auth
+	}
+}
+
+// WithToken logs in using the token, it's the same as using WithAuth with TokenAuth.
+func WithToken(token string) Option {
+	return WithAuth(TokenAuth{Token: token})
 }
 
-// New ...
-func New(token, addr, path string) (*Provider, error) {
+// WithKVVersion indicates the version of the KV engine mounted at the path, either 1 or 2, defaults to 2.
+func WithKVVersion(version int) Option {
+	return func(p *Provider) {
+		p.version = version
+	}
+}
+
+// WithRefreshInterval indicates how long secrets are cached when their lease is longer or when they don't
+// have one, like KV v2 secrets, defaults to 5 minutes.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(p *Provider) {
+		p.interval = d
+	}
+}
+
+// WithLogger defines the logger used when running in the background.
+func WithLogger(logger *zap.Logger) Option {
+	return func(p *Provider) {
+		p.logger = logger
+	}
+}
+
+// New instantiates the Provider and logs in, "path" is the path where the KV engine is mounted. The token in
+// "VAULT_TOKEN" is used unless WithToken or WithAuth indicate otherwise.
+func New(addr, path string, opts ...Option) (*Provider, error) {
 	config := &api.Config{
 		Address: addr,
 	}
@@ -29,72 +83,326 @@ func New(token, addr, path string) (*Provider, error) {
 		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "api.NewClient")
 	}
 
-	client.SetToken(token)
+	p := &Provider{
+		client:   client,
+		path:     path,
+		version:  2,
+		auth:     TokenAuth{Token: client.Token()}, // api.NewClient reads VAULT_TOKEN
+		interval: 5 * time.Minute,
+		logger:   zap.NewNop(),
+		results:  make(map[string]result),
+		subs:     make(map[string]map[*Subscription]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.version != 1 && p.version != 2 {
+		return nil, internal.NewErrorf(internal.ErrCodeInvalidArgument, "invalid KV version %d", p.version)
+	}
+
+	if err := p.login(); err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnauthenticated, "login")
+	}
 
-	return &Provider{
-		path:    path,
-		client:  client.Logical(),
-		results: make(map[string]map[string]string),
-	}, nil
+	return p, nil
 }
 
 // Get retrieves a value from vault using the KV engine. The actual key selected is determined by the value
 // separated by the colon. For example "database:password" will retrieve the key "password" from the path
 // "database".
 func (p *Provider) Get(v string) (string, error) {
-	// <path>/data/<path-secret>:key
-	split := strings.Split(v, ":")
-	if len(split) == 1 {
-		return "", internal.NewErrorf(internal.ErrCodeUnknown, "missing key value")
+	pathSecret, key, err := parseKey(v)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	res, ok := p.results[pathSecret]
+	p.mu.Unlock()
+
+	if !ok || time.Now().After(res.expires) {
+		fresh, err := p.refresh(pathSecret)
+		if err != nil {
+			if !ok {
+				return "", err
+			}
+
+			// XXX: Using the stale value is better than failing when Vault is temporarily unavailable.
+			p.logger.Warn("refreshing secret, using cached value", zap.String("path", pathSecret), zap.Error(err))
+		} else {
+			res = fresh
+		}
+	}
+
+	val, ok := res.values[key]
+	if !ok {
+		return "", internal.NewErrorf(internal.ErrCodeNotFound, "key not found in retrieved data")
 	}
 
-	pathSecret := split[0]
-	key := split[1]
+	return val, nil
+}
+
+// Run renews the token, logging in again when it expires, and refreshes the cached secrets before they expire;
+// it blocks until the context is canceled.
+func (p *Provider) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		watcher, err := p.watchToken()
+		if err != nil {
+			// XXX: Without a watcher the token would expire unnoticed, logging in again after waiting for the
+			// next tick gets a new one to watch.
+			p.logger.Error("watching token, logging in again", zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.RefreshExpiring()
+			}
+		} else {
+			var done <-chan error
+			if watcher != nil {
+				done = watcher.DoneCh()
+			}
+
+			refreshed := p.refreshUntil(ctx, ticker, done)
+
+			if watcher != nil {
+				watcher.Stop()
+			}
+
+			if !refreshed {
+				return
+			}
+		}
+
+		for err := p.login(); err != nil; err = p.login() {
+			p.logger.Error("logging in", zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// refreshUntil refreshes the cached secrets after every tick until the token expires, indicated by done, it
+// returns false when the context is canceled instead.
+func (p *Provider) refreshUntil(ctx context.Context, ticker *time.Ticker, done <-chan error) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case err := <-done:
+			p.logger.Info("token expired, logging in again", zap.Error(err))
+
+			return true
+		case <-ticker.C:
+			p.RefreshExpiring()
+		}
+	}
+}
+
+// RefreshExpiring refreshes the cached secrets expiring before the next refresh interval.
+func (p *Provider) RefreshExpiring() {
+	deadline := time.Now().Add(p.interval)
+
+	var paths []string
 
 	p.mu.Lock()
-	defer p.mu.Unlock()
 
-	res, ok := p.results[pathSecret]
-	if ok {
-		val, ok := res[key]
-		if !ok {
-			return "", internal.NewErrorf(internal.ErrCodeUnknown, "key not found in cached data")
+	for path, res := range p.results {
+		if res.expires.Before(deadline) {
+			paths = append(paths, path)
 		}
+	}
+
+	p.mu.Unlock()
 
-		return val, nil
+	for _, path := range paths {
+		if _, err := p.refresh(path); err != nil {
+			p.logger.Warn("refreshing secret", zap.String("path", path), zap.Error(err))
+		}
 	}
+}
 
-	secret, err := p.client.Read(fmt.Sprintf("%s/data/%s", p.path, pathSecret))
+// Subscribe returns a Subscription receiving the new values of the key, using the same format as Get, every time
+// they change.
+func (p *Provider) Subscribe(v string) (*Subscription, error) {
+	pathSecret, key, err := parseKey(v)
 	if err != nil {
-		return "", internal.WrapErrorf(err, internal.ErrCodeUnknown, "reading")
+		return nil, err
+	}
+
+	sub := &Subscription{
+		p:    p,
+		path: pathSecret,
+		key:  key,
+		ch:   make(chan string, 1),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.subs[pathSecret] == nil {
+		p.subs[pathSecret] = make(map[*Subscription]struct{})
+	}
+
+	p.subs[pathSecret][sub] = struct{}{}
+
+	return sub, nil
+}
+
+func (p *Provider) login() error {
+	token, err := p.auth.Login(p.client)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeUnauthenticated, "auth.Login")
+	}
+
+	p.client.SetToken(token.ClientToken)
+
+	p.mu.Lock()
+	p.token = token
+	p.mu.Unlock()
+
+	return nil
+}
+
+// watchToken starts renewing the token until it reaches its maximum TTL, tokens that can't be renewed are
+// watched until they expire; nil is returned when the token never expires, like root tokens.
+func (p *Provider) watchToken() (*api.LifetimeWatcher, error) {
+	p.mu.Lock()
+	token := p.token
+	p.mu.Unlock()
+
+	if token.LeaseDuration == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	watcher, err := p.client.NewLifetimeWatcher(&api.LifetimeWatcherInput{
+		Secret: &api.Secret{Auth: token},
+	})
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "client.NewLifetimeWatcher")
+	}
+
+	go watcher.Start()
+
+	return watcher, nil
+}
+
+// refresh reads the secret, caches it and notifies the subscribers of the keys that changed.
+func (p *Provider) refresh(pathSecret string) (result, error) {
+	secret, err := p.client.Logical().Read(p.secretPath(pathSecret))
+	if err != nil {
+		return result{}, internal.WrapErrorf(err, internal.ErrCodeUnavailable, "reading")
 	}
 
 	if secret == nil {
-		return "", internal.NewErrorf(internal.ErrCodeUnknown, "secret not found")
+		return result{}, internal.NewErrorf(internal.ErrCodeNotFound, "secret not found")
 	}
 
-	data, ok := secret.Data["data"].(map[string]interface{})
-	if !ok {
-		return "", internal.NewErrorf(internal.ErrCodeUnknown, "invalid data in secret")
+	data := secret.Data
+
+	if p.version == 2 {
+		if data, _ = secret.Data["data"].(map[string]interface{}); data == nil {
+			return result{}, internal.NewErrorf(internal.ErrCodeUnknown, "invalid data in secret")
+		}
+	}
+
+	res := result{
+		values:  make(map[string]string),
+		expires: time.Now().Add(p.interval),
 	}
 
-	secrets := make(map[string]string)
+	// KV v1 secrets include a lease when they define a "ttl" key.
+	if lease := time.Duration(secret.LeaseDuration) * time.Second; lease > 0 && lease < p.interval {
+		res.expires = time.Now().Add(lease)
+	}
 
 	for k, v := range data {
 		val, ok := v.(string)
 		if !ok {
-			return "", internal.NewErrorf(internal.ErrCodeUnknown, "secret value in data is not string")
+			if p.version == 1 && k == "ttl" {
+				continue
+			}
+
+			return result{}, internal.NewErrorf(internal.ErrCodeUnknown, "secret value in data is not string")
 		}
 
-		secrets[k] = val
+		res.values[k] = val
 	}
 
-	val, ok := secrets[key]
-	if !ok {
-		return "", internal.NewErrorf(internal.ErrCodeUnknown, "key not found in retrieved data")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prev, cached := p.results[pathSecret]
+	p.results[pathSecret] = res
+
+	if cached {
+		for sub := range p.subs[pathSecret] {
+			if val, ok := res.values[sub.key]; ok && val != prev.values[sub.key] {
+				sub.send(val)
+			}
+		}
 	}
 
-	p.results[pathSecret] = secrets
+	return res, nil
+}
+
+func (p *Provider) secretPath(pathSecret string) string {
+	if p.version == 1 {
+		return fmt.Sprintf("%s/%s", p.path, pathSecret)
+	}
 
-	return val, nil
+	// <path>/data/<path-secret>
+	return fmt.Sprintf("%s/data/%s", p.path, pathSecret)
+}
+
+// Subscription receives the new values of a secret's key.
+type Subscription struct {
+	p    *Provider
+	path string
+	key  string
+	ch   chan string
+}
+
+// Values returns the channel receiving the values, only the latest one is kept when they are not received fast
+// enough.
+func (s *Subscription) Values() <-chan string {
+	return s.ch
+}
+
+// Close stops receiving values.
+func (s *Subscription) Close() {
+	s.p.mu.Lock()
+	defer s.p.mu.Unlock()
+
+	delete(s.p.subs[s.path], s)
+}
+
+// send replaces the pending value, if any; it's called with the Provider's lock held.
+func (s *Subscription) send(val string) {
+	select {
+	case <-s.ch:
+	default:
+	}
+
+	s.ch <- val
+}
+
+func parseKey(v string) (string, string, error) {
+	// <path-secret>:key
+	split := strings.Split(v, ":")
+	if len(split) == 1 {
+		return "", "", internal.NewErrorf(internal.ErrCodeUnknown, "missing key value")
+	}
+
+	return split[0], split[1], nil
 }
@@ -110,7 +110,7 @@ func TestProvider_Get(t *testing.T) {
 	// Provider is not local to the subtest because we want to test the local caching logic
 
 	client := newVault(t)
-	provider, err := vault.New(client.Token, client.Address, "/secret")
+	provider, err := vault.New(client.Address, "/secret", vault.WithToken(client.Token))
 
 	if err != nil {
 		t.Fatalf("expected no error, got %s", err)
@@ -140,6 +140,169 @@ func TestProvider_Get(t *testing.T) {
 	}
 }
 
+// XXX: The following tests are not parallel because each Vault server binds the same port on the host.
+
+func TestProvider_KVv1(t *testing.T) {
+	client := newVault(t)
+
+	if err := client.Client.Sys().Mount("kv1", &api.MountInput{
+		Type:    "kv",
+		Options: map[string]string{"version": "1"},
+	}); err != nil {
+		t.Fatalf("couldn't mount: %s", err)
+	}
+
+	if _, err := client.Client.Logical().Write("kv1/database", map[string]interface{}{
+		"password": "secret",
+	}); err != nil {
+		t.Fatalf("couldn't write: %s", err)
+	}
+
+	provider, err := vault.New(client.Address, "kv1",
+		vault.WithAuth(vault.TokenAuth{Token: client.Token}),
+		vault.WithKVVersion(1))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	actual, err := provider.Get("database:password")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if actual != "secret" {
+		t.Fatalf("expected secret, got %s", actual)
+	}
+}
+
+func TestProvider_AppRole(t *testing.T) {
+	client := newVault(t)
+
+	if err := client.Client.Sys().EnableAuthWithOptions("approle", &api.EnableAuthOptions{Type: "approle"}); err != nil {
+		t.Fatalf("couldn't enable auth: %s", err)
+	}
+
+	if err := client.Client.Sys().PutPolicy("read-secrets", `path "secret/data/*" { capabilities = ["read"] }`); err != nil {
+		t.Fatalf("couldn't put policy: %s", err)
+	}
+
+	if _, err := client.Client.Logical().Write("auth/approle/role/todo", map[string]interface{}{
+		"token_policies": "read-secrets",
+		"token_ttl":      "1h",
+	}); err != nil {
+		t.Fatalf("couldn't write role: %s", err)
+	}
+
+	roleID, err := client.Client.Logical().Read("auth/approle/role/todo/role-id")
+	if err != nil {
+		t.Fatalf("couldn't read role id: %s", err)
+	}
+
+	secretID, err := client.Client.Logical().Write("auth/approle/role/todo/secret-id", nil)
+	if err != nil {
+		t.Fatalf("couldn't write secret id: %s", err)
+	}
+
+	if _, err := client.Client.Logical().Write("/secret/data/approle", map[string]interface{}{
+		"data": map[string]interface{}{
+			"password": "secret",
+		},
+	}); err != nil {
+		t.Fatalf("couldn't write: %s", err)
+	}
+
+	provider, err := vault.New(client.Address, "/secret",
+		vault.WithAuth(vault.AppRoleAuth{
+			RoleID:   roleID.Data["role_id"].(string),     //nolint:forcetypeassert
+			SecretID: secretID.Data["secret_id"].(string), //nolint:forcetypeassert
+		}))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	actual, err := provider.Get("approle:password")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if actual != "secret" {
+		t.Fatalf("expected secret, got %s", actual)
+	}
+
+	//-
+
+	if _, err := vault.New(client.Address, "/secret",
+		vault.WithAuth(vault.AppRoleAuth{RoleID: "invalid", SecretID: "invalid"})); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestProvider_Subscribe(t *testing.T) {
+	client := newVault(t)
+
+	write := func(val string) {
+		if _, err := client.Client.Logical().Write("/secret/data/rotated", map[string]interface{}{
+			"data": map[string]interface{}{
+				"password": val,
+			},
+		}); err != nil {
+			t.Fatalf("couldn't write: %s", err)
+		}
+	}
+
+	write("one")
+
+	provider, err := vault.New(client.Address, "/secret",
+		vault.WithAuth(vault.TokenAuth{Token: client.Token}),
+		vault.WithRefreshInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if actual, err := provider.Get("rotated:password"); err != nil || actual != "one" {
+		t.Fatalf("expected one, got %s (%v)", actual, err)
+	}
+
+	sub, err := provider.Subscribe("rotated:password")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	defer sub.Close()
+
+	write("two")
+
+	provider.RefreshExpiring()
+
+	select {
+	case actual := <-sub.Values():
+		if actual != "two" {
+			t.Fatalf("expected two, got %s", actual)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected new value")
+	}
+
+	if actual, err := provider.Get("rotated:password"); err != nil || actual != "two" {
+		t.Fatalf("expected two, got %s (%v)", actual, err)
+	}
+}
+
+func TestKubernetesAuth_Login(t *testing.T) {
+	t.Parallel()
+
+	// XXX: Logging in requires a Kubernetes API server for reviewing the token, only the local failure is tested.
+
+	client, err := api.NewClient(&api.Config{Address: "http://127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if _, err := (vault.KubernetesAuth{Role: "todo", JWTPath: "missing"}).Login(client); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
 func newVault(tb testing.TB) *vaultClient {
 	tb.Helper()
 
@@ -1,6 +1,11 @@
 package internal
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 type Error struct {
 	orig error
@@ -14,8 +19,90 @@ const (
 	ErrCodeUnknown ErrorCode = iota
 	ErrCodeNotFound
 	ErrCodeInvalidArgument
+	ErrCodeConflict
+	ErrCodeAlreadyExists
+	ErrCodeUnavailable
+	ErrCodeDeadlineExceeded
+	ErrCodeCanceled
+	ErrCodeUnauthenticated
+	ErrCodePermissionDenied
+	ErrCodeResourceExhausted
+	ErrCodeFailedPrecondition
 )
 
+// String returns the stable name of the code, clients can use it for determining the kind of error.
+func (c ErrorCode) String() string {
+	switch c {
+	case ErrCodeNotFound:
+		return "not_found"
+	case ErrCodeInvalidArgument:
+		return "invalid_argument"
+	case ErrCodeConflict:
+		return "conflict"
+	case ErrCodeAlreadyExists:
+		return "already_exists"
+	case ErrCodeUnavailable:
+		return "unavailable"
+	case ErrCodeDeadlineExceeded:
+		return "deadline_exceeded"
+	case ErrCodeCanceled:
+		return "canceled"
+	case ErrCodeUnauthenticated:
+		return "unauthenticated"
+	case ErrCodePermissionDenied:
+		return "permission_denied"
+	case ErrCodeResourceExhausted:
+		return "resource_exhausted"
+	case ErrCodeFailedPrecondition:
+		return "failed_precondition"
+	case ErrCodeUnknown:
+	}
+
+	return "unknown"
+}
+
+// Retryable indicates whether errors with this code are caused by a temporary condition, and therefore
+// retrying the same call may succeed.
+func (c ErrorCode) Retryable() bool {
+	switch c {
+	case ErrCodeConflict, ErrCodeUnavailable, ErrCodeDeadlineExceeded, ErrCodeResourceExhausted:
+		return true
+	case ErrCodeUnknown, ErrCodeNotFound, ErrCodeInvalidArgument, ErrCodeAlreadyExists, ErrCodeCanceled,
+		ErrCodeUnauthenticated, ErrCodePermissionDenied, ErrCodeFailedPrecondition:
+	}
+
+	return false
+}
+
+// HTTPStatus returns the HTTP status code representing this code.
+func (c ErrorCode) HTTPStatus() int {
+	switch c {
+	case ErrCodeNotFound:
+		return http.StatusNotFound
+	case ErrCodeInvalidArgument:
+		return http.StatusBadRequest
+	case ErrCodeConflict, ErrCodeAlreadyExists:
+		return http.StatusConflict
+	case ErrCodeUnavailable:
+		return http.StatusServiceUnavailable
+	case ErrCodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case ErrCodeCanceled:
+		return 499 // XXX: Client Closed Request, non-standard.
+	case ErrCodeUnauthenticated:
+		return http.StatusUnauthorized
+	case ErrCodePermissionDenied:
+		return http.StatusForbidden
+	case ErrCodeResourceExhausted:
+		return http.StatusTooManyRequests
+	case ErrCodeFailedPrecondition:
+		return http.StatusUnprocessableEntity
+	case ErrCodeUnknown:
+	}
+
+	return http.StatusInternalServerError
+}
+
 func WrapErrorf(orig error, code ErrorCode, format string, args ...interface{}) error {
 	return &Error{
 		orig: orig,
@@ -47,3 +134,44 @@ func (e *Error) Unwrap() error {
 func (e *Error) Code() ErrorCode {
 	return e.code
 }
+
+// Retryable indicates whether retrying the call that returned this error may succeed.
+func (e *Error) Retryable() bool {
+	return e.code.Retryable()
+}
+
+// Code returns the code of the received error. Errors not created by this package are classified when possible,
+// like context errors; ErrCodeUnknown is returned otherwise.
+func Code(err error) ErrorCode {
+	if err == nil {
+		return ErrCodeUnknown
+	}
+
+	var ierr *Error
+	if errors.As(err, &ierr) {
+		return ierr.Code()
+	}
+
+	if code, ok := ContextCode(err); ok {
+		return code
+	}
+
+	return ErrCodeUnknown
+}
+
+// ContextCode classifies the errors returned by canceled contexts or with expired deadlines.
+func ContextCode(err error) (ErrorCode, bool) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrCodeCanceled, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrCodeDeadlineExceeded, true
+	}
+
+	return ErrCodeUnknown, false
+}
+
+// Retryable indicates whether retrying the call that returned the error may succeed.
+func Retryable(err error) bool {
+	return Code(err).Retryable()
+}
@@ -0,0 +1,108 @@
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lrweck/todo/internal"
+)
+
+func TestErrorCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code      internal.ErrorCode
+		name      string
+		retryable bool
+		status    int
+	}{
+		{internal.ErrCodeUnknown, "unknown", false, http.StatusInternalServerError},
+		{internal.ErrCodeNotFound, "not_found", false, http.StatusNotFound},
+		{internal.ErrCodeInvalidArgument, "invalid_argument", false, http.StatusBadRequest},
+		{internal.ErrCodeConflict, "conflict", true, http.StatusConflict},
+		{internal.ErrCodeAlreadyExists, "already_exists", false, http.StatusConflict},
+		{internal.ErrCodeUnavailable, "unavailable", true, http.StatusServiceUnavailable},
+		{internal.ErrCodeDeadlineExceeded, "deadline_exceeded", true, http.StatusGatewayTimeout},
+		{internal.ErrCodeCanceled, "canceled", false, 499},
+		{internal.ErrCodeUnauthenticated, "unauthenticated", false, http.StatusUnauthorized},
+		{internal.ErrCodePermissionDenied, "permission_denied", false, http.StatusForbidden},
+		{internal.ErrCodeResourceExhausted, "resource_exhausted", true, http.StatusTooManyRequests},
+		{internal.ErrCodeFailedPrecondition, "failed_precondition", false, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if actual := tt.code.String(); actual != tt.name {
+				t.Fatalf("expected name %s, actual %s", tt.name, actual)
+			}
+
+			if actual := tt.code.Retryable(); actual != tt.retryable {
+				t.Fatalf("expected retryable %t, actual %t", tt.retryable, actual)
+			}
+
+			if actual := tt.code.HTTPStatus(); actual != tt.status {
+				t.Fatalf("expected status %d, actual %d", tt.status, actual)
+			}
+		})
+	}
+}
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    error
+		expected internal.ErrorCode
+	}{
+		{
+			"nil",
+			nil,
+			internal.ErrCodeUnknown,
+		},
+		{
+			"unclassified",
+			errors.New("oops"),
+			internal.ErrCodeUnknown,
+		},
+		{
+			"outermost code wins",
+			internal.WrapErrorf(internal.NewErrorf(internal.ErrCodeNotFound, "find"), internal.ErrCodeUnavailable, "repo"),
+			internal.ErrCodeUnavailable,
+		},
+		{
+			"wrapped with fmt",
+			fmt.Errorf("find: %w", internal.NewErrorf(internal.ErrCodeNotFound, "not found")),
+			internal.ErrCodeNotFound,
+		},
+		{
+			"context canceled",
+			fmt.Errorf("query: %w", context.Canceled),
+			internal.ErrCodeCanceled,
+		},
+		{
+			"context deadline exceeded",
+			context.DeadlineExceeded,
+			internal.ErrCodeDeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if actual := internal.Code(tt.input); actual != tt.expected {
+				t.Fatalf("expected %s, actual %s", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package internal
+
+// TaskEventType indicates the change that happened to a Task.
+type TaskEventType string
+
+const (
+	TaskEventCreated  TaskEventType = "created"
+	TaskEventDeleted  TaskEventType = "deleted"
+	TaskEventRestored TaskEventType = "restored"
+	TaskEventUpdated  TaskEventType = "updated"
+)
+
+// TaskEvent is a change that happened to a Task, deleted events only include the ID of the Task.
+type TaskEvent struct {
+	ID   string
+	Type TaskEventType
+	Task Task
+}
@@ -0,0 +1,145 @@
+// Package events fans out the changes made to Tasks to the clients streaming them.
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// Hub sends the Task events to its subscribers, the most recent events are kept for replaying them to clients
+// resuming their streams after reconnecting.
+//
+// Event IDs are only meaningful to the Hub that assigned them, they are prefixed with an epoch identifying the
+// Hub so resuming a stream started by a different process, or before restarting, is detected.
+type Hub struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	size    int
+	history []internal.TaskEvent
+	subs    map[*Subscription]struct{}
+}
+
+// NewHub instantiates the Hub, size indicates the number of events kept for replaying.
+func NewHub(size int) *Hub {
+	return &Hub{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:    size,
+		history: make([]internal.TaskEvent, 0, size),
+		subs:    make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns an ID to the event and sends it to the subscribers. Subscribers not keeping up, those with
+// their buffer full, are dropped instead of blocking the rest.
+func (h *Hub) Publish(evt internal.TaskEvent) internal.TaskEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	evt.ID = h.id(h.seq)
+
+	if h.size > 0 {
+		if len(h.history) == h.size {
+			copy(h.history, h.history[1:])
+			h.history = h.history[:h.size-1]
+		}
+
+		h.history = append(h.history, evt)
+	}
+
+	for sub := range h.subs {
+		select {
+		case sub.events <- evt:
+		default:
+			h.drop(sub)
+		}
+	}
+
+	return evt
+}
+
+// Subscribe returns a new subscription receiving the events published from now on, buffer indicates how many
+// events can be pending before dropping it.
+//
+// lastID is the ID of the last event received by the client, if any. The events published after it are
+// returned for replaying them, complete is false when some of those events are not available anymore and the
+// client must synchronize its state some other way.
+func (h *Hub) Subscribe(lastID string, buffer int) (_ *Subscription, replay []internal.TaskEvent, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription{
+		hub:    h,
+		events: make(chan internal.TaskEvent, buffer),
+	}
+
+	h.subs[sub] = struct{}{}
+
+	if lastID == "" {
+		return sub, nil, true
+	}
+
+	replay, complete = h.since(lastID)
+
+	return sub, replay, complete
+}
+
+// since returns the events published after the received ID.
+func (h *Hub) since(lastID string) ([]internal.TaskEvent, bool) {
+	i := strings.LastIndex(lastID, "-")
+	if i < 0 || lastID[:i] != h.epoch {
+		return nil, false
+	}
+
+	seq, err := strconv.ParseUint(lastID[i+1:], 10, 64)
+	if err != nil || seq > h.seq {
+		return nil, false
+	}
+
+	pending := int(h.seq - seq)
+	if pending > len(h.history) {
+		return append([]internal.TaskEvent(nil), h.history...), false
+	}
+
+	return append([]internal.TaskEvent(nil), h.history[len(h.history)-pending:]...), true
+}
+
+func (h *Hub) id(seq uint64) string {
+	return fmt.Sprintf("%s-%d", h.epoch, seq)
+}
+
+// drop removes the subscription, it must be called holding the lock.
+func (h *Hub) drop(sub *Subscription) {
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+
+	delete(h.subs, sub)
+	close(sub.events)
+}
+
+// Subscription receives the events published to the Hub.
+type Subscription struct {
+	hub    *Hub
+	events chan internal.TaskEvent
+}
+
+// Events returns the channel receiving the events, it's closed when the subscription is dropped for not
+// keeping up or after calling Close.
+func (s *Subscription) Events() <-chan internal.TaskEvent {
+	return s.events
+}
+
+// Close stops receiving events.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.drop(s)
+}
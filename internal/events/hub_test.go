@@ -0,0 +1,114 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/events"
+)
+
+func TestHub_Publish(t *testing.T) {
+	t.Parallel()
+
+	hub := events.NewHub(10)
+
+	sub, replay, complete := hub.Subscribe("", 1)
+	if len(replay) != 0 || !complete {
+		t.Fatalf("expected no replay, got %d %t", len(replay), complete)
+	}
+
+	evt := hub.Publish(internal.TaskEvent{Type: internal.TaskEventCreated, Task: internal.Task{ID: "1"}})
+	if evt.ID == "" {
+		t.Fatalf("expected ID to be assigned")
+	}
+
+	if actual := <-sub.Events(); !cmp.Equal(evt, actual) {
+		t.Fatalf("expected results don't match: %s", cmp.Diff(evt, actual))
+	}
+
+	// Slow subscribers are dropped when their buffer is full.
+
+	hub.Publish(internal.TaskEvent{Type: internal.TaskEventDeleted, Task: internal.Task{ID: "1"}})
+	hub.Publish(internal.TaskEvent{Type: internal.TaskEventRestored, Task: internal.Task{ID: "1"}})
+
+	<-sub.Events()
+
+	if _, ok := <-sub.Events(); ok {
+		t.Fatalf("expected subscription to be dropped")
+	}
+
+	sub.Close()
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	hub := events.NewHub(2)
+
+	first := hub.Publish(internal.TaskEvent{Type: internal.TaskEventCreated})
+	second := hub.Publish(internal.TaskEvent{Type: internal.TaskEventUpdated})
+	third := hub.Publish(internal.TaskEvent{Type: internal.TaskEventDeleted})
+
+	tests := []struct {
+		name     string
+		lastID   string
+		replay   []internal.TaskEvent
+		complete bool
+	}{
+		{
+			"OK: latest",
+			third.ID,
+			nil,
+			true,
+		},
+		{
+			"OK: replay",
+			second.ID,
+			[]internal.TaskEvent{third},
+			true,
+		},
+		{
+			"OK: evicted",
+			first.ID[:len(first.ID)-1] + "0",
+			[]internal.TaskEvent{second, third},
+			false,
+		},
+		{
+			"OK: unknown epoch",
+			"other-1",
+			nil,
+			false,
+		},
+		{
+			"OK: invalid",
+			"invalid",
+			nil,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sub, replay, complete := hub.Subscribe(tt.lastID, 1)
+			defer sub.Close()
+
+			if complete != tt.complete {
+				t.Fatalf("expected complete %t, got %t", tt.complete, complete)
+			}
+
+			if len(replay) == 0 && len(tt.replay) == 0 {
+				return
+			}
+
+			if !cmp.Equal(tt.replay, replay) {
+				t.Fatalf("expected results don't match: %s", cmp.Diff(tt.replay, replay))
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// Publisher publishes the Task events straight to the Hub, it replaces the message broker when the service runs
+// as a single process, like when using the SQLite repository.
+type Publisher struct {
+	hub *Hub
+}
+
+// NewPublisher instantiates the Publisher.
+func NewPublisher(hub *Hub) *Publisher {
+	return &Publisher{
+		hub: hub,
+	}
+}
+
+// Created ...
+func (p *Publisher) Created(_ context.Context, task internal.Task) error {
+	p.hub.Publish(internal.TaskEvent{Type: internal.TaskEventCreated, Task: task})
+
+	return nil
+}
+
+// Deleted ...
+func (p *Publisher) Deleted(_ context.Context, id string) error {
+	p.hub.Publish(internal.TaskEvent{Type: internal.TaskEventDeleted, Task: internal.Task{ID: id}})
+
+	return nil
+}
+
+// Restored ...
+func (p *Publisher) Restored(_ context.Context, task internal.Task) error {
+	p.hub.Publish(internal.TaskEvent{Type: internal.TaskEventRestored, Task: task})
+
+	return nil
+}
+
+// Updated ...
+func (p *Publisher) Updated(_ context.Context, task internal.Task) error {
+	p.hub.Publish(internal.TaskEvent{Type: internal.TaskEventUpdated, Task: task})
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/events"
+)
+
+func TestPublisher(t *testing.T) {
+	t.Parallel()
+
+	hub := events.NewHub(0)
+
+	sub, _, _ := hub.Subscribe("", 4)
+
+	publisher := events.NewPublisher(hub)
+	task := internal.Task{ID: "1", Description: "test"}
+
+	_ = publisher.Created(context.Background(), task)
+	_ = publisher.Updated(context.Background(), task)
+	_ = publisher.Deleted(context.Background(), task.ID)
+	_ = publisher.Restored(context.Background(), task)
+
+	expected := []internal.TaskEvent{
+		{Type: internal.TaskEventCreated, Task: task},
+		{Type: internal.TaskEventUpdated, Task: task},
+		{Type: internal.TaskEventDeleted, Task: internal.Task{ID: "1"}},
+		{Type: internal.TaskEventRestored, Task: task},
+	}
+
+	actual := make([]internal.TaskEvent, len(expected))
+
+	for i := range actual {
+		actual[i] = <-sub.Events()
+	}
+
+	if diff := cmp.Diff(expected, actual, cmpopts.IgnoreFields(internal.TaskEvent{}, "ID")); diff != "" {
+		t.Fatalf("expected results don't match: %s", diff)
+	}
+}
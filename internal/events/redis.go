@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+	redispublisher "github.com/lrweck/todo/internal/publisher/redis"
+)
+
+//nolint:gochecknoglobals
+var redisChannels = map[string]internal.TaskEventType{
+	"tasks.event.created":  internal.TaskEventCreated,
+	"tasks.event.deleted":  internal.TaskEventDeleted,
+	"tasks.event.restored": internal.TaskEventRestored,
+	"tasks.event.updated":  internal.TaskEventUpdated,
+}
+
+// SubscribeRedis publishes to the Hub the events published to Redis by "publisher/redis", it blocks until the
+// context is canceled. Messages that can't be decoded are logged and skipped.
+func SubscribeRedis(ctx context.Context, logger *zap.Logger, client *redis.Client, hub *Hub) error {
+	channels := make([]string, 0, len(redisChannels))
+
+	for channel := range redisChannels {
+		channels = append(channels, channel)
+	}
+
+	pubsub := client.Subscribe(ctx, channels...)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return internal.WrapErrorf(err, internal.Code(err), "pubsub.Receive")
+	}
+
+	ch := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			evt, err := decodeRedisMessage(ctx, msg)
+			if err != nil {
+				logger.Warn("decoding event", zap.String("channel", msg.Channel), zap.Error(err))
+
+				continue
+			}
+
+			hub.Publish(evt)
+		}
+	}
+}
+
+func decodeRedisMessage(ctx context.Context, msg *redis.Message) (internal.TaskEvent, error) {
+	var envelope struct {
+		Headers redispublisher.HeadersCarrier `json:"headers"`
+		Value   json.RawMessage               `json:"value"`
+	}
+
+	if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil {
+		return internal.TaskEvent{}, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "json.Unmarshal")
+	}
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, envelope.Headers)
+
+	// XXX: The extracted span is remote, its TracerProvider is a no-op one so the global one is used instead.
+	_, span := otel.Tracer("events").Start(ctx, "events.Receive",
+		trace.WithSpanKind(trace.SpanKindConsumer))
+	defer span.End()
+
+	span.SetAttributes(semconv.DBSystemRedis, semconv.MessagingDestinationKey.String(msg.Channel))
+
+	evt := internal.TaskEvent{
+		Type: redisChannels[msg.Channel],
+	}
+
+	// XXX: Deleted events only include the ID of the Task, the rest include the full Task.
+	var dst interface{} = &evt.Task
+	if evt.Type == internal.TaskEventDeleted {
+		dst = &evt.Task.ID
+	}
+
+	if err := json.Unmarshal(envelope.Value, dst); err != nil {
+		span.RecordError(err)
+
+		return internal.TaskEvent{}, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "json.Unmarshal")
+	}
+
+	return evt, nil
+}
@@ -0,0 +1,43 @@
+package graphql
+
+import (
+	"errors"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// Error is returned by the resolvers, the code of the error is included in the "extensions" of the response
+// so clients can branch on it like they do with the REST API.
+type Error struct {
+	msg string
+	err error
+}
+
+func newError(msg string, err error) error {
+	var ierr *internal.Error
+	if !errors.As(err, &ierr) {
+		msg = "internal error"
+	}
+
+	return &Error{
+		msg: msg,
+		err: err,
+	}
+}
+
+// Error returns the message, the wrapped error is not included to avoid leaking internal details.
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Extensions returns the additional fields included in the error response.
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": internal.Code(e.err).String(),
+	}
+}
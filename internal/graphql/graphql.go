@@ -0,0 +1,157 @@
+// Package graphql implements the GraphQL API, backed by the same service used by the REST API.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/events"
+)
+
+//go:embed schema.graphql
+var schema string
+
+//go:generate counterfeiter -generate
+
+//counterfeiter:generate -o graphqltesting/task_service.gen.go . TaskService
+
+// TaskService defines the methods used by the resolvers.
+type TaskService interface {
+	By(ctx context.Context, args internal.SearchParams) (internal.SearchResults, error)
+	Create(ctx context.Context, params internal.CreateParams) (internal.Task, error)
+	Delete(ctx context.Context, id string) error
+	Task(ctx context.Context, id string) (internal.Task, error)
+	Tasks(ctx context.Context, ids []string) ([]internal.Task, error)
+	Update(ctx context.Context, id, description string, priority internal.Priority, dates internal.Dates, isDone bool) error
+}
+
+// TaskEventsHub defines the methods used for receiving the Task events, used by subscriptions.
+type TaskEventsHub interface {
+	Subscribe(lastID string, buffer int) (*events.Subscription, []internal.TaskEvent, bool)
+}
+
+// Handler serves the GraphQL API, queries and mutations are sent using POST requests while subscriptions use
+// WebSockets following the "graphql-transport-ws" protocol.
+type Handler struct {
+	svc        TaskService
+	hub        TaskEventsHub
+	buffer     int
+	maxDepth   int
+	loaderWait time.Duration
+	initWait   time.Duration
+	upgrader   websocket.Upgrader
+	schema     *graphqlgo.Schema
+}
+
+// Option defines the options used by Handler.
+type Option func(*Handler)
+
+// WithEventsHub enables subscriptions, fed by the received hub.
+func WithEventsHub(hub TaskEventsHub) Option {
+	return func(h *Handler) {
+		h.hub = hub
+	}
+}
+
+// WithMaxDepth limits how deeply queries can nest fields, defaults to 10.
+func WithMaxDepth(depth int) Option {
+	return func(h *Handler) {
+		h.maxDepth = depth
+	}
+}
+
+// WithLoaderWait indicates how long Task lookups are collected before finding them as a batch, defaults to
+// 1 millisecond.
+func WithLoaderWait(d time.Duration) Option {
+	return func(h *Handler) {
+		h.loaderWait = d
+	}
+}
+
+// WithCheckOrigin defines the origins allowed to open WebSockets, by default only same-origin requests are.
+func WithCheckOrigin(check func(r *http.Request) bool) Option {
+	return func(h *Handler) {
+		h.upgrader.CheckOrigin = check
+	}
+}
+
+// NewHandler parses the schema and instantiates the Handler.
+func NewHandler(svc TaskService, opts ...Option) (*Handler, error) {
+	h := &Handler{
+		svc:        svc,
+		buffer:     64,
+		maxDepth:   10,
+		loaderWait: time.Millisecond,
+		initWait:   10 * time.Second,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{subprotocol},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	s, err := graphqlgo.ParseSchema(schema,
+		&resolver{
+			svc:    h.svc,
+			hub:    h.hub,
+			buffer: h.buffer,
+		},
+		graphqlgo.MaxDepth(h.maxDepth),
+		graphqlgo.Tracer(tracer{}),
+	)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "graphql.ParseSchema")
+	}
+
+	h.schema = s
+
+	return h, nil
+}
+
+// Register connects the handlers to the router.
+func (h *Handler) Register(r *mux.Router) {
+	r.HandleFunc("/graphql", h.websocket).Methods(http.MethodGet).Headers("Upgrade", "websocket")
+	r.HandleFunc("/graphql", h.query).Methods(http.MethodPost)
+}
+
+// Request defines the request used for executing operations.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (h *Handler) query(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		renderResponse(w, &graphqlgo.Response{
+			Errors: []*errors.QueryError{errors.Errorf("invalid request")},
+		}, http.StatusBadRequest)
+
+		return
+	}
+
+	defer r.Body.Close()
+
+	ctx := withLoader(r.Context(), newTaskLoader(h.svc, h.loaderWait))
+
+	renderResponse(w, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables), http.StatusOK)
+}
+
+func renderResponse(w http.ResponseWriter, res *graphqlgo.Response, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,401 @@
+package graphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/events"
+	"github.com/lrweck/todo/internal/graphql"
+	"github.com/lrweck/todo/internal/graphql/graphqltesting"
+)
+
+func TestHandler_Query(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		expected interface{}
+		verify   func(*testing.T, *graphqltesting.FakeTaskService)
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(*graphqltesting.FakeTaskService)
+		input  graphql.Request
+		output output
+	}{
+		{
+			"OK: task batched",
+			func(s *graphqltesting.FakeTaskService) {
+				s.TasksReturns([]internal.Task{{
+					ID:          "1",
+					Description: "groceries",
+					Priority:    internal.PriorityHigh,
+					Dates:       internal.Dates{Due: time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)},
+				}}, nil)
+			},
+			graphql.Request{
+				Query: `{ a: task(id: "1") { id description priority dates { start due } } b: task(id: "1") { isDone } }`,
+			},
+			output{
+				map[string]interface{}{
+					"data": map[string]interface{}{
+						"a": map[string]interface{}{
+							"id":          "1",
+							"description": "groceries",
+							"priority":    "HIGH",
+							"dates": map[string]interface{}{
+								"start": nil,
+								"due":   "2009-11-10T23:00:00Z",
+							},
+						},
+						"b": map[string]interface{}{
+							"isDone": false,
+						},
+					},
+				},
+				func(t *testing.T, s *graphqltesting.FakeTaskService) {
+					t.Helper()
+
+					if s.TasksCallCount() != 1 {
+						t.Fatalf("expected one lookup, actual %d", s.TasksCallCount())
+					}
+				},
+			},
+		},
+		{
+			"OK: tasks batched",
+			func(s *graphqltesting.FakeTaskService) {
+				s.TasksReturns([]internal.Task{{ID: "2"}, {ID: "1"}}, nil)
+			},
+			graphql.Request{
+				Query: `{ tasks(ids: ["1", "2", "3"]) { id } other: task(id: "2") { id } }`,
+			},
+			output{
+				map[string]interface{}{
+					"data": map[string]interface{}{
+						"tasks": []interface{}{
+							map[string]interface{}{"id": "1"},
+							map[string]interface{}{"id": "2"},
+							nil,
+						},
+						"other": map[string]interface{}{"id": "2"},
+					},
+				},
+				func(t *testing.T, s *graphqltesting.FakeTaskService) {
+					t.Helper()
+
+					if s.TasksCallCount() != 1 {
+						t.Fatalf("expected one lookup, actual %d", s.TasksCallCount())
+					}
+
+					if _, ids := s.TasksArgsForCall(0); len(ids) != 3 {
+						t.Fatalf("expected the ids to be deduplicated, actual %v", ids)
+					}
+				},
+			},
+		},
+		{
+			"ERR: tasks too many ids",
+			func(*graphqltesting.FakeTaskService) {},
+			graphql.Request{
+				Query: `query Find($ids: [ID!]!) { tasks(ids: $ids) { id } }`,
+				Variables: map[string]interface{}{
+					"ids": strings.Split(strings.Repeat("1,", 100)+"1", ","),
+				},
+			},
+			output{
+				map[string]interface{}{
+					"errors": []interface{}{
+						map[string]interface{}{
+							"message": "invalid ids",
+							"path":    []interface{}{"tasks"},
+							"extensions": map[string]interface{}{
+								"code": "invalid_argument",
+							},
+						},
+					},
+					"data": nil,
+				},
+				func(t *testing.T, s *graphqltesting.FakeTaskService) {
+					t.Helper()
+
+					if s.TasksCallCount() != 0 {
+						t.Fatalf("expected no lookups, actual %d", s.TasksCallCount())
+					}
+				},
+			},
+		},
+		{
+			"OK: task not found",
+			func(s *graphqltesting.FakeTaskService) {
+				s.TasksReturns(nil, nil)
+			},
+			graphql.Request{
+				Query: `query Find($id: ID!) { task(id: $id) { id } }`,
+				Variables: map[string]interface{}{
+					"id": "1",
+				},
+			},
+			output{
+				map[string]interface{}{
+					"data": map[string]interface{}{
+						"task": nil,
+					},
+				},
+				func(*testing.T, *graphqltesting.FakeTaskService) {},
+			},
+		},
+		{
+			"OK: searchTasks",
+			func(s *graphqltesting.FakeTaskService) {
+				s.ByReturns(internal.SearchResults{
+					Tasks: []internal.Task{{ID: "2"}, {ID: "3"}},
+					Total: 5,
+				}, nil)
+			},
+			graphql.Request{
+				Query: `{ searchTasks(filter: {priority: LOW}, first: 2, after: "Y3Vyc29yOjA=") {
+					edges { cursor node { id } }
+					pageInfo { hasNextPage hasPreviousPage startCursor endCursor }
+					totalCount
+				} }`,
+			},
+			output{
+				map[string]interface{}{
+					"data": map[string]interface{}{
+						"searchTasks": map[string]interface{}{
+							"edges": []interface{}{
+								map[string]interface{}{"cursor": "Y3Vyc29yOjE=", "node": map[string]interface{}{"id": "2"}},
+								map[string]interface{}{"cursor": "Y3Vyc29yOjI=", "node": map[string]interface{}{"id": "3"}},
+							},
+							"pageInfo": map[string]interface{}{
+								"hasNextPage":     true,
+								"hasPreviousPage": true,
+								"startCursor":     "Y3Vyc29yOjE=",
+								"endCursor":       "Y3Vyc29yOjI=",
+							},
+							"totalCount": float64(5),
+						},
+					},
+				},
+				func(t *testing.T, s *graphqltesting.FakeTaskService) {
+					t.Helper()
+
+					_, params := s.ByArgsForCall(0)
+
+					if params.From != 1 || params.Size != 2 || *params.Priority != internal.PriorityLow {
+						t.Fatalf("unexpected params %+v", params)
+					}
+				},
+			},
+		},
+		{
+			"OK: updateTask",
+			func(s *graphqltesting.FakeTaskService) {
+				s.TaskReturns(internal.Task{
+					ID:          "1",
+					Description: "groceries",
+					Priority:    internal.PriorityHigh,
+				}, nil)
+			},
+			graphql.Request{
+				Query: `mutation { updateTask(input: {id: "1", description: "laundry"}) { description priority } }`,
+			},
+			output{
+				map[string]interface{}{
+					"data": map[string]interface{}{
+						"updateTask": map[string]interface{}{
+							"description": "laundry",
+							"priority":    "HIGH",
+						},
+					},
+				},
+				func(t *testing.T, s *graphqltesting.FakeTaskService) {
+					t.Helper()
+
+					_, id, description, priority, _, isDone := s.UpdateArgsForCall(0)
+
+					if id != "1" || description != "laundry" || priority != internal.PriorityHigh || isDone {
+						t.Fatalf("unexpected update %s %s %d %t", id, description, priority, isDone)
+					}
+				},
+			},
+		},
+		{
+			"OK: completeTask",
+			func(s *graphqltesting.FakeTaskService) {
+				s.TaskReturns(internal.Task{ID: "1", Description: "groceries"}, nil)
+			},
+			graphql.Request{
+				Query: `mutation { completeTask(id: "1") { isDone } }`,
+			},
+			output{
+				map[string]interface{}{
+					"data": map[string]interface{}{
+						"completeTask": map[string]interface{}{
+							"isDone": true,
+						},
+					},
+				},
+				func(t *testing.T, s *graphqltesting.FakeTaskService) {
+					t.Helper()
+
+					if _, _, _, _, _, isDone := s.UpdateArgsForCall(0); !isDone {
+						t.Fatalf("expected task to be completed")
+					}
+				},
+			},
+		},
+		{
+			"ERR: createTask",
+			func(s *graphqltesting.FakeTaskService) {
+				s.CreateReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeUnavailable, "not ready"))
+			},
+			graphql.Request{
+				Query: `mutation { createTask(input: {description: "groceries", priority: LOW}) { id } }`,
+			},
+			output{
+				map[string]interface{}{
+					"errors": []interface{}{
+						map[string]interface{}{
+							"message": "create failed",
+							"path":    []interface{}{"createTask"},
+							"extensions": map[string]interface{}{
+								"code": "unavailable",
+							},
+						},
+					},
+					"data": nil,
+				},
+				func(*testing.T, *graphqltesting.FakeTaskService) {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &graphqltesting.FakeTaskService{}
+			tt.setup(svc)
+
+			router := newRouter(t, svc)
+
+			body, _ := json.Marshal(tt.input)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+
+			var actual map[string]interface{}
+			if err := json.NewDecoder(rr.Body).Decode(&actual); err != nil {
+				t.Fatalf("couldn't decode %s", err)
+			}
+
+			if !cmp.Equal(tt.output.expected, actual) {
+				t.Fatalf("expected results don't match: %s", cmp.Diff(tt.output.expected, actual))
+			}
+
+			tt.output.verify(t, svc)
+		})
+	}
+}
+
+func TestHandler_Subscription(t *testing.T) {
+	t.Parallel()
+
+	hub := events.NewHub(10)
+
+	srv := httptest.NewServer(newRouter(t, &graphqltesting.FakeTaskService{}, graphql.WithEventsHub(hub)))
+	defer srv.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/graphql", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	type message struct {
+		ID      string          `json:"id,omitempty"`
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload,omitempty"`
+	}
+
+	write := func(msg message) {
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+	}
+
+	read := func() message {
+		var msg message
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		return msg
+	}
+
+	write(message{Type: "connection_init"})
+
+	if msg := read(); msg.Type != "connection_ack" {
+		t.Fatalf("expected connection_ack, got %s", msg.Type)
+	}
+
+	write(message{
+		ID:      "1",
+		Type:    "subscribe",
+		Payload: json.RawMessage(`{"query":"subscription { taskEvents(filter: {isDone: true}) { type taskId task { id } } }"}`),
+	})
+
+	// The subscription is asynchronous, keep publishing until the first event is received.
+	go func() {
+		for i := 0; i < 50; i++ {
+			hub.Publish(internal.TaskEvent{Type: internal.TaskEventUpdated, Task: internal.Task{ID: "open"}})
+			hub.Publish(internal.TaskEvent{Type: internal.TaskEventUpdated, Task: internal.Task{ID: "done", IsDone: true}})
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	msg := read()
+	if msg.Type != "next" || msg.ID != "1" {
+		t.Fatalf("expected next for 1, got %s for %s", msg.Type, msg.ID)
+	}
+
+	expected := `{"data":{"taskEvents":{"type":"UPDATED","taskId":"done","task":{"id":"done"}}}}`
+	if string(msg.Payload) != expected {
+		t.Fatalf("expected %s, got %s", expected, msg.Payload)
+	}
+
+	write(message{ID: "1", Type: "complete"})
+}
+
+func newRouter(t *testing.T, svc graphql.TaskService, opts ...graphql.Option) *mux.Router {
+	t.Helper()
+
+	h, err := graphql.NewHandler(svc, opts...)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	router := mux.NewRouter()
+	h.Register(router)
+
+	return router
+}
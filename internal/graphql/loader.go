@@ -0,0 +1,161 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lrweck/todo/internal"
+)
+
+type loaderKey struct{}
+
+// taskLoader batches and caches the Tasks found during a request, so resolving the same Task more than once,
+// or many Tasks in the same query, results in one lookup per wait window.
+type taskLoader struct {
+	batchFn func(ctx context.Context, ids []string) ([]internal.Task, []error)
+	wait    time.Duration
+
+	mu      sync.Mutex
+	cache   map[string]*taskThunk
+	pending []*taskThunk
+}
+
+type taskThunk struct {
+	id   string
+	done chan struct{}
+	task internal.Task
+	err  error
+}
+
+func newTaskLoader(svc TaskService, wait time.Duration) *taskLoader {
+	return &taskLoader{
+		wait:  wait,
+		cache: make(map[string]*taskThunk),
+		batchFn: func(ctx context.Context, ids []string) ([]internal.Task, []error) {
+			tasks := make([]internal.Task, len(ids))
+			errs := make([]error, len(ids))
+
+			found, err := svc.Tasks(ctx, ids)
+			if err != nil {
+				for i := range errs {
+					errs[i] = err
+				}
+
+				return tasks, errs
+			}
+
+			byID := make(map[string]internal.Task, len(found))
+
+			for _, task := range found {
+				byID[task.ID] = task
+			}
+
+			for i, id := range ids {
+				task, ok := byID[id]
+				if !ok {
+					errs[i] = internal.NewErrorf(internal.ErrCodeNotFound, "task not found")
+
+					continue
+				}
+
+				tasks[i] = task
+			}
+
+			return tasks, errs
+		},
+	}
+}
+
+func withLoader(ctx context.Context, l *taskLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+func loaderFromContext(ctx context.Context) (*taskLoader, bool) {
+	l, ok := ctx.Value(loaderKey{}).(*taskLoader)
+
+	return l, ok
+}
+
+// Load returns the Task, the IDs requested during the wait window are found in the same batch.
+func (l *taskLoader) Load(ctx context.Context, id string) (internal.Task, error) {
+	return l.await(ctx, l.enqueue(ctx, id)[0])
+}
+
+// LoadMany returns the Tasks in the same order, all of them are found in the same batch.
+func (l *taskLoader) LoadMany(ctx context.Context, ids []string) ([]internal.Task, []error) {
+	thunks := l.enqueue(ctx, ids...)
+
+	tasks := make([]internal.Task, len(ids))
+	errs := make([]error, len(ids))
+
+	for i, thunk := range thunks {
+		tasks[i], errs[i] = l.await(ctx, thunk)
+	}
+
+	return tasks, errs
+}
+
+// enqueue returns the thunks of the IDs, the ones not cached are added to the pending batch.
+func (l *taskLoader) enqueue(ctx context.Context, ids ...string) []*taskThunk {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	thunks := make([]*taskThunk, len(ids))
+
+	for i, id := range ids {
+		thunk, ok := l.cache[id]
+		if !ok {
+			thunk = &taskThunk{id: id, done: make(chan struct{})}
+
+			l.cache[id] = thunk
+			l.pending = append(l.pending, thunk)
+
+			if len(l.pending) == 1 {
+				time.AfterFunc(l.wait, func() { l.dispatch(ctx) })
+			}
+		}
+
+		thunks[i] = thunk
+	}
+
+	return thunks
+}
+
+func (l *taskLoader) await(ctx context.Context, thunk *taskThunk) (internal.Task, error) {
+	select {
+	case <-thunk.done:
+		return thunk.task, thunk.err
+	case <-ctx.Done():
+		return internal.Task{}, internal.WrapErrorf(ctx.Err(), internal.Code(ctx.Err()), "loader")
+	}
+}
+
+// Clear removes the Task from the cache, used after changing it.
+func (l *taskLoader) Clear(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.cache, id)
+}
+
+func (l *taskLoader) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	ids := make([]string, len(batch))
+
+	for i, thunk := range batch {
+		ids[i] = thunk.id
+	}
+
+	tasks, errs := l.batchFn(ctx, ids)
+
+	for i, thunk := range batch {
+		thunk.task, thunk.err = tasks[i], errs[i]
+
+		close(thunk.done)
+	}
+}
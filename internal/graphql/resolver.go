@@ -0,0 +1,326 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+
+	"github.com/lrweck/todo/internal"
+)
+
+const (
+	cursorPrefix = "cursor:"
+	maxPageSize  = 100
+	maxTaskIDs   = 100
+)
+
+// resolver is the root resolver, it implements the Query, Mutation and Subscription types.
+type resolver struct {
+	svc    TaskService
+	hub    TaskEventsHub
+	buffer int
+}
+
+func (r *resolver) Task(ctx context.Context, args struct{ ID graphqlgo.ID }) (*taskResolver, error) {
+	task, err := r.find(ctx, string(args.ID))
+	if err != nil {
+		if internal.Code(err) == internal.ErrCodeNotFound {
+			return nil, nil
+		}
+
+		return nil, newError("find failed", err)
+	}
+
+	return &taskResolver{task: task}, nil
+}
+
+func (r *resolver) Tasks(ctx context.Context, args struct{ IDs []graphqlgo.ID }) ([]*taskResolver, error) {
+	if len(args.IDs) > maxTaskIDs {
+		return nil, newError("invalid ids",
+			internal.NewErrorf(internal.ErrCodeInvalidArgument, "ids must have at most %d elements", maxTaskIDs))
+	}
+
+	ids := make([]string, len(args.IDs))
+
+	for i, id := range args.IDs {
+		ids[i] = string(id)
+	}
+
+	tasks, errs := r.findMany(ctx, ids)
+
+	res := make([]*taskResolver, len(ids))
+
+	for i, err := range errs {
+		if err != nil {
+			if internal.Code(err) != internal.ErrCodeNotFound {
+				return nil, newError("find failed", err)
+			}
+
+			continue
+		}
+
+		res[i] = &taskResolver{task: tasks[i]}
+	}
+
+	return res, nil
+}
+
+func (r *resolver) SearchTasks(ctx context.Context, args struct {
+	Filter *searchTasksInput
+	First  *int32
+	After  *string
+}) (*taskConnectionResolver, error) {
+	size := int64(10)
+	if args.First != nil {
+		size = int64(*args.First)
+	}
+
+	if size < 0 || size > maxPageSize {
+		return nil, newError("invalid first",
+			internal.NewErrorf(internal.ErrCodeInvalidArgument, "first must be between 0 and %d", maxPageSize))
+	}
+
+	var from int64
+
+	if args.After != nil {
+		offset, err := decodeCursor(*args.After)
+		if err != nil {
+			return nil, newError("invalid after", err)
+		}
+
+		from = offset + 1
+	}
+
+	params := args.Filter.convert()
+	params.From = from
+	params.Size = size
+
+	res, err := r.svc.By(ctx, params)
+	if err != nil {
+		return nil, newError("search failed", err)
+	}
+
+	return &taskConnectionResolver{
+		tasks: res.Tasks,
+		total: res.Total,
+		from:  from,
+	}, nil
+}
+
+func (r *resolver) CreateTask(ctx context.Context, args struct{ Input createTaskInput }) (*taskResolver, error) {
+	task, err := r.svc.Create(ctx, internal.CreateParams{
+		Description: args.Input.Description,
+		Priority:    convertPriority(args.Input.Priority),
+		Dates:       args.Input.Dates.convert(),
+	})
+	if err != nil {
+		return nil, newError("create failed", err)
+	}
+
+	return &taskResolver{task: task}, nil
+}
+
+func (r *resolver) UpdateTask(ctx context.Context, args struct{ Input updateTaskInput }) (*taskResolver, error) {
+	return r.update(ctx, args.Input)
+}
+
+func (r *resolver) DeleteTask(ctx context.Context, args struct{ ID graphqlgo.ID }) (graphqlgo.ID, error) {
+	if err := r.svc.Delete(ctx, string(args.ID)); err != nil {
+		return "", newError("delete failed", err)
+	}
+
+	r.clear(ctx, string(args.ID))
+
+	return args.ID, nil
+}
+
+func (r *resolver) CompleteTask(ctx context.Context, args struct{ ID graphqlgo.ID }) (*taskResolver, error) {
+	done := true
+
+	return r.update(ctx, updateTaskInput{ID: args.ID, IsDone: &done})
+}
+
+// update reads the current Task for changing only the received fields, like "PATCH /v2/tasks/{id}".
+//
+// XXX: Reading and updating is not atomic, the last write wins.
+func (r *resolver) update(ctx context.Context, input updateTaskInput) (*taskResolver, error) {
+	id := string(input.ID)
+
+	current, err := r.svc.Task(ctx, id)
+	if err != nil {
+		return nil, newError("find failed", err)
+	}
+
+	task := input.apply(current)
+
+	if err := r.svc.Update(ctx, id, task.Description, task.Priority, task.Dates, task.IsDone); err != nil {
+		return nil, newError("update failed", err)
+	}
+
+	r.clear(ctx, id)
+
+	return &taskResolver{task: task}, nil
+}
+
+func (r *resolver) TaskEvents(ctx context.Context, args struct{ Filter *searchTasksInput }) (<-chan *taskEventResolver, error) {
+	if r.hub == nil {
+		return nil, newError("subscriptions unavailable",
+			internal.NewErrorf(internal.ErrCodeUnavailable, "events hub not configured"))
+	}
+
+	params := args.Filter.convert()
+
+	sub, _, _ := r.hub.Subscribe("", r.buffer)
+
+	res := make(chan *taskEventResolver)
+
+	go func() {
+		defer close(res)
+		defer sub.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+
+				// XXX: Deleted events can't be matched against the filter, clients discard unknown IDs.
+				if evt.Type != internal.TaskEventDeleted && !params.Matches(evt.Task) {
+					continue
+				}
+
+				select {
+				case res <- &taskEventResolver{evt: evt}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return res, nil
+}
+
+func (r *resolver) find(ctx context.Context, id string) (internal.Task, error) {
+	if l, ok := loaderFromContext(ctx); ok {
+		return l.Load(ctx, id)
+	}
+
+	return r.svc.Task(ctx, id)
+}
+
+// findMany returns the Tasks in the same order, using one lookup for all of them.
+func (r *resolver) findMany(ctx context.Context, ids []string) ([]internal.Task, []error) {
+	if l, ok := loaderFromContext(ctx); ok {
+		return l.LoadMany(ctx, ids)
+	}
+
+	return newTaskLoader(r.svc, 0).LoadMany(ctx, ids)
+}
+
+func (r *resolver) clear(ctx context.Context, id string) {
+	if l, ok := loaderFromContext(ctx); ok {
+		l.Clear(id)
+	}
+}
+
+type taskConnectionResolver struct {
+	tasks []internal.Task
+	total int64
+	from  int64
+}
+
+func (t *taskConnectionResolver) Edges() []*taskEdgeResolver {
+	res := make([]*taskEdgeResolver, len(t.tasks))
+
+	for i, task := range t.tasks {
+		res[i] = &taskEdgeResolver{
+			cursor: encodeCursor(t.from + int64(i)),
+			task:   task,
+		}
+	}
+
+	return res
+}
+
+func (t *taskConnectionResolver) PageInfo() *pageInfoResolver {
+	res := pageInfoResolver{
+		hasNextPage:     t.from+int64(len(t.tasks)) < t.total,
+		hasPreviousPage: t.from > 0,
+	}
+
+	if len(t.tasks) > 0 {
+		start := encodeCursor(t.from)
+		end := encodeCursor(t.from + int64(len(t.tasks)) - 1)
+
+		res.startCursor = &start
+		res.endCursor = &end
+	}
+
+	return &res
+}
+
+func (t *taskConnectionResolver) TotalCount() int32 {
+	return int32(t.total)
+}
+
+type taskEdgeResolver struct {
+	cursor string
+	task   internal.Task
+}
+
+func (t *taskEdgeResolver) Cursor() string {
+	return t.cursor
+}
+
+func (t *taskEdgeResolver) Node() *taskResolver {
+	return &taskResolver{task: t.task}
+}
+
+type pageInfoResolver struct {
+	hasNextPage     bool
+	hasPreviousPage bool
+	startCursor     *string
+	endCursor       *string
+}
+
+func (p *pageInfoResolver) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *pageInfoResolver) HasPreviousPage() bool {
+	return p.hasPreviousPage
+}
+
+func (p *pageInfoResolver) StartCursor() *string {
+	return p.startCursor
+}
+
+func (p *pageInfoResolver) EndCursor() *string {
+	return p.endCursor
+}
+
+// encodeCursor returns the opaque cursor of the result at the offset.
+func encodeCursor(offset int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(offset, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	val, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "base64.DecodeString")
+	}
+
+	offset, err := strconv.ParseInt(strings.TrimPrefix(string(val), cursorPrefix), 10, 64)
+	if err != nil || !strings.HasPrefix(string(val), cursorPrefix) || offset < 0 {
+		return 0, internal.NewErrorf(internal.ErrCodeInvalidArgument, "invalid cursor")
+	}
+
+	return offset, nil
+}
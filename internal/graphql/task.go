@@ -0,0 +1,199 @@
+package graphql
+
+import (
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+
+	"github.com/lrweck/todo/internal"
+)
+
+type taskResolver struct {
+	task internal.Task
+}
+
+func (t *taskResolver) ID() graphqlgo.ID {
+	return graphqlgo.ID(t.task.ID)
+}
+
+func (t *taskResolver) Description() string {
+	return t.task.Description
+}
+
+func (t *taskResolver) Priority() string {
+	return newPriority(t.task.Priority)
+}
+
+func (t *taskResolver) Dates() *datesResolver {
+	return &datesResolver{dates: t.task.Dates}
+}
+
+func (t *taskResolver) IsDone() bool {
+	return t.task.IsDone
+}
+
+type datesResolver struct {
+	dates internal.Dates
+}
+
+func (d *datesResolver) Start() *graphqlgo.Time {
+	return newTime(d.dates.Start)
+}
+
+func (d *datesResolver) Due() *graphqlgo.Time {
+	return newTime(d.dates.Due)
+}
+
+func newTime(t time.Time) *graphqlgo.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &graphqlgo.Time{Time: t}
+}
+
+// newPriority converts the received domain type to the enum value, when the argument is unknown "NONE" is used.
+func newPriority(p internal.Priority) string {
+	switch p {
+	case internal.PriorityNone:
+		return "NONE"
+	case internal.PriorityLow:
+		return "LOW"
+	case internal.PriorityMedium:
+		return "MEDIUM"
+	case internal.PriorityHigh:
+		return "HIGH"
+	}
+
+	return "NONE"
+}
+
+// convertPriority returns the domain type of the enum value, the schema guarantees the value is valid.
+func convertPriority(p string) internal.Priority {
+	switch p {
+	case "LOW":
+		return internal.PriorityLow
+	case "MEDIUM":
+		return internal.PriorityMedium
+	case "HIGH":
+		return internal.PriorityHigh
+	}
+
+	return internal.PriorityNone
+}
+
+type searchTasksInput struct {
+	Description *string
+	Priority    *string
+	IsDone      *bool
+}
+
+func (s *searchTasksInput) convert() internal.SearchParams {
+	if s == nil {
+		return internal.SearchParams{}
+	}
+
+	res := internal.SearchParams{
+		Description: s.Description,
+		IsDone:      s.IsDone,
+	}
+
+	if s.Priority != nil {
+		priority := convertPriority(*s.Priority)
+		res.Priority = &priority
+	}
+
+	return res
+}
+
+type datesInput struct {
+	Start *graphqlgo.Time
+	Due   *graphqlgo.Time
+}
+
+func (d *datesInput) convert() internal.Dates {
+	var res internal.Dates
+
+	if d == nil {
+		return res
+	}
+
+	if d.Start != nil {
+		res.Start = d.Start.Time
+	}
+
+	if d.Due != nil {
+		res.Due = d.Due.Time
+	}
+
+	return res
+}
+
+type createTaskInput struct {
+	Description string
+	Priority    string
+	Dates       *datesInput
+}
+
+type updateTaskInput struct {
+	ID          graphqlgo.ID
+	Description *string
+	Priority    *string
+	Dates       *datesInput
+	IsDone      *bool
+}
+
+// apply returns the task with the received fields changed.
+func (u updateTaskInput) apply(task internal.Task) internal.Task {
+	if u.Description != nil {
+		task.Description = *u.Description
+	}
+
+	if u.Priority != nil {
+		task.Priority = convertPriority(*u.Priority)
+	}
+
+	if u.Dates != nil {
+		task.Dates = u.Dates.convert()
+	}
+
+	if u.IsDone != nil {
+		task.IsDone = *u.IsDone
+	}
+
+	return task
+}
+
+type taskEventResolver struct {
+	evt internal.TaskEvent
+}
+
+func (t *taskEventResolver) ID() string {
+	return t.evt.ID
+}
+
+func (t *taskEventResolver) Type() string {
+	switch t.evt.Type {
+	case internal.TaskEventCreated:
+		return "CREATED"
+	case internal.TaskEventDeleted:
+		return "DELETED"
+	case internal.TaskEventRestored:
+		return "RESTORED"
+	case internal.TaskEventUpdated:
+	}
+
+	return "UPDATED"
+}
+
+func (t *taskEventResolver) TaskID() graphqlgo.ID {
+	return graphqlgo.ID(t.evt.Task.ID)
+}
+
+func (t *taskEventResolver) Task() *taskResolver {
+	if t.evt.Type == internal.TaskEventDeleted {
+		return nil
+	}
+
+	return &taskResolver{task: t.evt.Task}
+}
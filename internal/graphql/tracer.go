@@ -0,0 +1,57 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/introspection"
+	"github.com/graph-gophers/graphql-go/trace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates OpenTelemetry spans for the queries and the non-trivial fields, those using resolver methods.
+type tracer struct{}
+
+func (tracer) TraceQuery(ctx context.Context, queryString string, operationName string, _ map[string]interface{}, _ map[string]*introspection.Type) (context.Context, trace.TraceQueryFinishFunc) {
+	ctx, span := oteltrace.SpanFromContext(ctx).TracerProvider().Tracer("todo.graphql").Start(ctx, "GraphQL request")
+
+	span.SetAttributes(
+		attribute.String("graphql.document", queryString),
+		attribute.String("graphql.operation.name", operationName),
+	)
+
+	return ctx, func(errs []*errors.QueryError) {
+		for _, err := range errs {
+			span.RecordError(err)
+		}
+
+		if len(errs) > 0 {
+			span.SetStatus(codes.Error, errs[0].Message)
+		}
+
+		span.End()
+	}
+}
+
+func (tracer) TraceField(ctx context.Context, _, typeName, fieldName string, trivial bool, _ map[string]interface{}) (context.Context, trace.TraceFieldFinishFunc) {
+	if trivial {
+		return ctx, func(*errors.QueryError) {}
+	}
+
+	ctx, span := oteltrace.SpanFromContext(ctx).TracerProvider().Tracer("todo.graphql").Start(ctx, typeName+"."+fieldName)
+
+	return ctx, func(err *errors.QueryError) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Message)
+		}
+
+		span.End()
+	}
+}
+
+func (tracer) TraceValidation(context.Context) trace.TraceValidationFinishFunc {
+	return func([]*errors.QueryError) {}
+}
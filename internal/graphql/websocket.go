@@ -0,0 +1,230 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// subprotocol is the protocol used for executing operations over WebSockets, see
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const subprotocol = "graphql-transport-ws"
+
+const (
+	msgConnectionInit = "connection_init"
+	msgConnectionAck  = "connection_ack"
+	msgPing           = "ping"
+	msgPong           = "pong"
+	msgSubscribe      = "subscribe"
+	msgNext           = "next"
+	msgComplete       = "complete"
+)
+
+// Close codes defined by the protocol.
+const (
+	closeInvalidMessage      = 4400
+	closeUnauthorized        = 4401
+	closeInitTimeout         = 4408
+	closeSubscriberExists    = 4409
+	closeTooManyInitRequests = 4429
+)
+
+const (
+	wsWriteTimeout  = 10 * time.Second
+	wsMaxOperations = 100
+)
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsConn holds the state of a connection, writes are serialized because each operation writes from its own
+// goroutine.
+type wsConn struct {
+	h    *Handler
+	conn *websocket.Conn
+
+	writeMu sync.Mutex
+
+	mu   sync.Mutex
+	ops  map[string]context.CancelFunc
+	init bool
+}
+
+func (h *Handler) websocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // XXX: Upgrade already replied with an error.
+	}
+
+	defer conn.Close()
+
+	if conn.Subprotocol() != subprotocol {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseProtocolError, "unsupported subprotocol"),
+			time.Now().Add(wsWriteTimeout))
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	c := wsConn{
+		h:    h,
+		conn: conn,
+		ops:  make(map[string]context.CancelFunc),
+	}
+
+	c.serve(ctx)
+}
+
+// serve reads the messages sent by the client until the connection is closed.
+func (c *wsConn) serve(ctx context.Context) {
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.h.initWait))
+
+	for {
+		var msg wsMessage
+
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			if !c.initialized() {
+				c.close(closeInitTimeout, "connection initialisation timeout")
+			}
+
+			return
+		}
+
+		switch msg.Type {
+		case msgConnectionInit:
+			c.mu.Lock()
+			again := c.init
+			c.init = true
+			c.mu.Unlock()
+
+			if again {
+				c.close(closeTooManyInitRequests, "too many initialisation requests")
+
+				return
+			}
+
+			_ = c.conn.SetReadDeadline(time.Time{})
+
+			c.write(wsMessage{Type: msgConnectionAck})
+		case msgPing:
+			c.write(wsMessage{Type: msgPong})
+		case msgPong:
+		case msgSubscribe:
+			if !c.initialized() {
+				c.close(closeUnauthorized, "unauthorized")
+
+				return
+			}
+
+			if code, reason := c.subscribe(ctx, msg); code != 0 {
+				c.close(code, reason)
+
+				return
+			}
+		case msgComplete:
+			c.mu.Lock()
+			if cancel, ok := c.ops[msg.ID]; ok {
+				cancel()
+			}
+			c.mu.Unlock()
+		default:
+			c.close(closeInvalidMessage, "invalid message type")
+
+			return
+		}
+	}
+}
+
+// subscribe executes the operation, any kind of operation is supported but only subscriptions produce more than
+// one result. A non-zero close code is returned when the connection must be closed.
+func (c *wsConn) subscribe(ctx context.Context, msg wsMessage) (int, string) {
+	var req Request
+
+	if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil {
+		return closeInvalidMessage, "invalid subscribe message"
+	}
+
+	c.mu.Lock()
+
+	if _, ok := c.ops[msg.ID]; ok {
+		c.mu.Unlock()
+
+		return closeSubscriberExists, "subscriber for " + msg.ID + " already exists"
+	}
+
+	if len(c.ops) >= wsMaxOperations {
+		c.mu.Unlock()
+
+		return closeInvalidMessage, "too many operations"
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c.ops[msg.ID] = cancel
+
+	c.mu.Unlock()
+
+	ctx = withLoader(ctx, newTaskLoader(c.h.svc, c.h.loaderWait))
+
+	responses, err := c.h.schema.Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		// XXX: Only returned when the schema has no subscriptions, it always does.
+		cancel()
+
+		return closeInvalidMessage, err.Error()
+	}
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.ops, msg.ID)
+			c.mu.Unlock()
+
+			cancel()
+		}()
+
+		for res := range responses {
+			payload, _ := json.Marshal(res)
+
+			c.write(wsMessage{ID: msg.ID, Type: msgNext, Payload: payload})
+		}
+
+		// XXX: Completing an operation canceled by the client is not needed but it's harmless.
+		c.write(wsMessage{ID: msg.ID, Type: msgComplete})
+	}()
+
+	return 0, ""
+}
+
+func (c *wsConn) initialized() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.init
+}
+
+func (c *wsConn) write(msg wsMessage) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	_ = c.conn.WriteJSON(msg)
+}
+
+func (c *wsConn) close(code int, reason string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_ = c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(wsWriteTimeout))
+}
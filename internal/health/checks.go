@@ -0,0 +1,164 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/go-redis/redis/v8"
+	vault "github.com/hashicorp/vault/api"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/streadway/amqp"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// Pinger is implemented by the PostgreSQL connection pools, like pgxpool.Pool and postgresql.RotatingPool.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PostgreSQL checks the database is reachable.
+func PostgreSQL(pool Pinger) Check {
+	return func(ctx context.Context) error {
+		if err := pool.Ping(ctx); err != nil {
+			return internal.WrapErrorf(err, internal.ErrCodeUnknown, "pool.Ping")
+		}
+
+		return nil
+	}
+}
+
+// Querier is implemented by the PostgreSQL connection pools, like pgxpool.Pool, postgresql.RotatingPool and
+// postgresql.Router.
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Migrations checks the database schema is at the expected version and it's not dirty, meaning the last
+// migration did not fail halfway. The expected version is usually the one returned by
+// postgresql.Migrator.Latest.
+func Migrations(pool Querier, version uint) Check {
+	return func(ctx context.Context) error {
+		var (
+			current uint
+			dirty   bool
+		)
+
+		if err := pool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&current, &dirty); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgErr) && pgErr.Code == "42P01" { // undefined_table
+				return internal.NewErrorf(internal.ErrCodeUnknown, "migrations pending: none applied, expected %d", version)
+			}
+
+			return internal.WrapErrorf(err, internal.ErrCodeUnknown, "pool.QueryRow")
+		}
+
+		if dirty {
+			return internal.NewErrorf(internal.ErrCodeUnknown, "migration %d is dirty", current)
+		}
+
+		if current < version {
+			return internal.NewErrorf(internal.ErrCodeUnknown, "migrations pending: at %d, expected %d", current, version)
+		}
+
+		return nil
+	}
+}
+
+// Searcher is implemented by the search stores, like sqlite.Task.
+type Searcher interface {
+	Search(ctx context.Context, args internal.SearchParams) (internal.SearchResults, error)
+}
+
+// Search checks the search store answers queries. Pass the store itself instead of a caching decorator,
+// otherwise cached results hide an unhealthy store.
+func Search(store Searcher) Check {
+	return func(ctx context.Context) error {
+		if _, err := store.Search(ctx, internal.SearchParams{Size: 1}); err != nil {
+			return internal.WrapErrorf(err, internal.ErrCodeUnknown, "store.Search")
+		}
+
+		return nil
+	}
+}
+
+// Memcached checks all the memcached servers are reachable.
+func Memcached(client *memcache.Client) Check {
+	return func(ctx context.Context) error {
+		return withContext(ctx, func() error {
+			if err := client.Ping(); err != nil {
+				return internal.WrapErrorf(err, internal.ErrCodeUnknown, "client.Ping")
+			}
+
+			return nil
+		})
+	}
+}
+
+// Kafka checks the brokers are reachable by requesting the topic metadata.
+func Kafka(producer *kafka.Producer, topic string) Check {
+	return func(ctx context.Context) error {
+		timeout := time.Second
+
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+
+		if _, err := producer.GetMetadata(&topic, false, int(timeout.Milliseconds())); err != nil {
+			return internal.WrapErrorf(err, internal.ErrCodeUnknown, "producer.GetMetadata")
+		}
+
+		return nil
+	}
+}
+
+// RabbitMQ checks the connection is open and a channel can be created.
+func RabbitMQ(conn *amqp.Connection) Check {
+	return func(ctx context.Context) error {
+		if conn.IsClosed() {
+			return internal.NewErrorf(internal.ErrCodeUnknown, "connection is closed")
+		}
+
+		return withContext(ctx, func() error {
+			ch, err := conn.Channel()
+			if err != nil {
+				return internal.WrapErrorf(err, internal.ErrCodeUnknown, "conn.Channel")
+			}
+
+			if err := ch.Close(); err != nil {
+				return internal.WrapErrorf(err, internal.ErrCodeUnknown, "ch.Close")
+			}
+
+			return nil
+		})
+	}
+}
+
+// Redis checks the server is reachable.
+func Redis(client *redis.Client) Check {
+	return func(ctx context.Context) error {
+		if err := client.Ping(ctx).Err(); err != nil {
+			return internal.WrapErrorf(err, internal.ErrCodeUnknown, "client.Ping")
+		}
+
+		return nil
+	}
+}
+
+// Vault checks the token in use is still valid.
+func Vault(client *vault.Client) Check {
+	return func(ctx context.Context) error {
+		return withContext(ctx, func() error {
+			// XXX: Expired or revoked tokens are rejected by the lookup itself.
+			if _, err := client.Auth().Token().LookupSelf(); err != nil {
+				return internal.WrapErrorf(err, internal.ErrCodeUnknown, "Token.LookupSelf")
+			}
+
+			return nil
+		})
+	}
+}
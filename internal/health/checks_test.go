@@ -0,0 +1,53 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/health"
+)
+
+type searcher func(context.Context, internal.SearchParams) (internal.SearchResults, error)
+
+func (s searcher) Search(ctx context.Context, args internal.SearchParams) (internal.SearchResults, error) {
+	return s(ctx, args)
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		err     error
+		healthy bool
+	}{
+		{
+			"OK",
+			nil,
+			true,
+		},
+		{
+			"ERR: store failing",
+			errors.New("failed"),
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			check := health.Search(searcher(func(context.Context, internal.SearchParams) (internal.SearchResults, error) {
+				return internal.SearchResults{}, tt.err
+			}))
+
+			if err := check(context.Background()); (err == nil) != tt.healthy {
+				t.Fatalf("expected healthy %t, got %v", tt.healthy, err)
+			}
+		})
+	}
+}
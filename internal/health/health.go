@@ -0,0 +1,137 @@
+// Package health implements the checks used for determining whether the service and its dependencies are
+// available.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// Check verifies a dependency is available, it returns an error otherwise.
+type Check func(ctx context.Context) error
+
+// Status represents the result of checking a dependency.
+type Status struct {
+	Name    string
+	Latency time.Duration
+	Err     error
+}
+
+// Healthy indicates whether the dependency is available.
+func (s Status) Healthy() bool {
+	return s.Err == nil
+}
+
+// Report represents the result of checking all the dependencies.
+type Report struct {
+	Draining     bool
+	Dependencies []Status
+}
+
+// Ready indicates whether the service can receive traffic.
+func (r Report) Ready() bool {
+	if r.Draining {
+		return false
+	}
+
+	for _, s := range r.Dependencies {
+		if !s.Healthy() {
+			return false
+		}
+	}
+
+	return true
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker determines the readiness of the service by checking all its registered dependencies concurrently.
+type Checker struct {
+	timeout  time.Duration
+	draining int32
+
+	mu     sync.RWMutex
+	checks []namedCheck
+}
+
+// NewChecker instantiates the Checker, each check is bounded by timeout.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+	}
+}
+
+// Register adds a new dependency to be checked.
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Drain marks the service as shutting down, from this point on the service is not considered ready anymore.
+func (c *Checker) Drain() {
+	atomic.StoreInt32(&c.draining, 1)
+}
+
+// Check runs all the registered checks, results are returned in the same order they were registered.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make([]namedCheck, len(c.checks))
+	copy(checks, c.checks)
+	c.mu.RUnlock()
+
+	res := Report{
+		Draining:     atomic.LoadInt32(&c.draining) == 1,
+		Dependencies: make([]Status, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+
+	for i, nc := range checks {
+		wg.Add(1)
+
+		go func(i int, nc namedCheck) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := nc.check(ctx)
+
+			res.Dependencies[i] = Status{
+				Name:    nc.name,
+				Latency: time.Since(start),
+				Err:     err,
+			}
+		}(i, nc)
+	}
+
+	wg.Wait()
+
+	return res
+}
+
+// withContext runs fn, returning early if ctx is done first; used for clients not supporting contexts.
+func withContext(ctx context.Context, fn func() error) error {
+	errC := make(chan error, 1)
+
+	go func() {
+		errC <- fn()
+	}()
+
+	select {
+	case err := <-errC:
+		return err
+	case <-ctx.Done():
+		return internal.WrapErrorf(ctx.Err(), internal.ErrCodeDeadlineExceeded, "check timed out")
+	}
+}
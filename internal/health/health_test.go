@@ -0,0 +1,65 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lrweck/todo/internal/health"
+)
+
+func TestChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	checker := health.NewChecker(10 * time.Millisecond)
+
+	checker.Register("ok", func(_ context.Context) error { return nil })
+	checker.Register("failing", func(_ context.Context) error { return errors.New("failed") })
+	checker.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	report := checker.Check(context.Background())
+	if report.Ready() {
+		t.Fatalf("expected not ready")
+	}
+
+	expected := []struct {
+		name    string
+		healthy bool
+	}{
+		{"ok", true},
+		{"failing", false},
+		{"slow", false},
+	}
+
+	if len(report.Dependencies) != len(expected) {
+		t.Fatalf("expected %d dependencies, got %d", len(expected), len(report.Dependencies))
+	}
+
+	for i, e := range expected {
+		if dep := report.Dependencies[i]; dep.Name != e.name || dep.Healthy() != e.healthy {
+			t.Fatalf("expected %s healthy %t, got %s %t", e.name, e.healthy, dep.Name, dep.Healthy())
+		}
+	}
+}
+
+func TestChecker_Drain(t *testing.T) {
+	t.Parallel()
+
+	checker := health.NewChecker(time.Second)
+	checker.Register("ok", func(_ context.Context) error { return nil })
+
+	if report := checker.Check(context.Background()); !report.Ready() {
+		t.Fatalf("expected ready")
+	}
+
+	checker.Drain()
+
+	if report := checker.Check(context.Background()); report.Ready() || !report.Draining {
+		t.Fatalf("expected not ready while draining")
+	}
+}
@@ -0,0 +1,20 @@
+package internal
+
+import (
+	"net/http"
+)
+
+// IdempotentResponse is the response stored for replaying requests retried using the same idempotency key.
+type IdempotentResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// IdempotencyRecord represents the request that used an idempotency key first, Response is nil while that
+// request is still in flight. Owner identifies the request holding the key, it's only set when acquiring it.
+type IdempotencyRecord struct {
+	Fingerprint string
+	Owner       string
+	Response    *IdempotentResponse
+}
@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"strings"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
@@ -45,7 +47,35 @@ func (s SearchParams) IsZero() bool {
 		s.IsDone == nil
 }
 
+// Matches indicates whether the task satisfies the params, the description matches when it's contained in the
+// task's one ignoring case.
+func (s SearchParams) Matches(task Task) bool {
+	if s.Description != nil && !strings.Contains(strings.ToLower(task.Description), strings.ToLower(*s.Description)) {
+		return false
+	}
+
+	if s.Priority != nil && *s.Priority != task.Priority {
+		return false
+	}
+
+	if s.IsDone != nil && *s.IsDone != task.IsDone {
+		return false
+	}
+
+	return true
+}
+
 type SearchResults struct {
 	Tasks []Task
 	Total int64
 }
+
+type TrashParams struct {
+	From int64
+	Size int64
+}
+
+type TrashResults struct {
+	Tasks []TrashedTask
+	Total int64
+}
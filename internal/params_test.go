@@ -118,3 +118,79 @@ func TestSearchParams_IsZero(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchParams_Matches(t *testing.T) {
+	t.Parallel()
+
+	newString := func(str string) *string {
+		return &str
+	}
+
+	newPriority := func(p internal.Priority) *internal.Priority {
+		return &p
+	}
+
+	newBool := func(b bool) *bool {
+		return &b
+	}
+
+	task := internal.Task{
+		Description: "Buy Groceries",
+		Priority:    internal.PriorityHigh,
+		IsDone:      true,
+	}
+
+	tests := []struct {
+		name   string
+		input  internal.SearchParams
+		output bool
+	}{
+		{
+			"OK: zero",
+			internal.SearchParams{},
+			true,
+		},
+		{
+			"OK: all",
+			internal.SearchParams{
+				Description: newString("groceries"),
+				Priority:    newPriority(internal.PriorityHigh),
+				IsDone:      newBool(true),
+			},
+			true,
+		},
+		{
+			"OK: Description",
+			internal.SearchParams{
+				Description: newString("laundry"),
+			},
+			false,
+		},
+		{
+			"OK: Priority",
+			internal.SearchParams{
+				Priority: newPriority(internal.PriorityLow),
+			},
+			false,
+		},
+		{
+			"OK: IsDone",
+			internal.SearchParams{
+				IsDone: newBool(false),
+			},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if actual := tt.input.Matches(task); actual != tt.output {
+				t.Fatalf("expected %t, got %t", tt.output, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var _ propagation.TextMapCarrier = (*HeadersCarrier)(nil)
+
+// HeadersCarrier adapts the Kafka message headers to propagate the trace context, consumers use it to extract
+// the context injected when publishing.
+type HeadersCarrier []kafka.Header
+
+// Get returns the value associated with the passed key.
+func (c *HeadersCarrier) Get(key string) string {
+	for _, h := range *c {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set stores the key-value pair, replacing any existing value.
+func (c *HeadersCarrier) Set(key, value string) {
+	for i, h := range *c {
+		if h.Key == key {
+			(*c)[i].Value = []byte(value)
+
+			return
+		}
+	}
+
+	*c = append(*c, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys lists the keys stored in this carrier.
+func (c *HeadersCarrier) Keys() []string {
+	res := make([]string, 0, len(*c))
+
+	for _, h := range *c {
+		res = append(res, h.Key)
+	}
+
+	return res
+}
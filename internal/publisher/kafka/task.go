@@ -6,17 +6,20 @@ import (
 	"encoding/json"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/publisher"
 )
 
 // Task represents the repository used for publishing Task records.
 type Task struct {
 	producer  *kafka.Producer
 	topicName string
+	metrics   publisher.Metrics
 }
 
 type event struct {
@@ -29,6 +32,7 @@ func NewTask(producer *kafka.Producer, topicName string) *Task {
 	return &Task{
 		topicName: topicName,
 		producer:  producer,
+		metrics:   publisher.NewMetrics("kafka"),
 	}
 }
 
@@ -42,14 +46,20 @@ func (t *Task) Deleted(ctx context.Context, id string) error {
 	return t.Publish(ctx, "Task.Deleted", "tasks.event.deleted", internal.Task{ID: id})
 }
 
+// Restored publishes a message indicating a task was restored from the trash.
+func (t *Task) Restored(ctx context.Context, task internal.Task) error {
+	return t.Publish(ctx, "Task.Restored", "tasks.event.restored", task)
+}
+
 // Updated publishes a message indicating a task was updated.
 func (t *Task) Updated(ctx context.Context, task internal.Task) error {
 	return t.Publish(ctx, "Task.Updated", "tasks.event.updated", task)
 }
 
-func (t *Task) Publish(ctx context.Context, spanName, msgType string, task internal.Task) error {
-	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("kafka").Start(ctx, spanName)
+func (t *Task) Publish(ctx context.Context, spanName, msgType string, task internal.Task) (err error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("kafka").Start(ctx, spanName)
 	defer span.End()
+	defer t.metrics.Record(ctx, msgType, &err)
 
 	span.SetAttributes(
 		attribute.KeyValue{
@@ -71,14 +81,19 @@ func (t *Task) Publish(ctx context.Context, spanName, msgType string, task inter
 		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "json.Encode")
 	}
 
+	var headers HeadersCarrier
+
+	otel.GetTextMapPropagator().Inject(ctx, &headers)
+
 	if err := t.producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{
 			Topic:     &t.topicName,
 			Partition: kafka.PartitionAny,
 		},
-		Value: b.Bytes(),
+		Value:   b.Bytes(),
+		Headers: headers,
 	}, nil); err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "product.Producer")
+		return internal.WrapErrorf(err, internal.ErrCodeUnavailable, "product.Producer")
 	}
 
 	return nil
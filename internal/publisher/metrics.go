@@ -0,0 +1,39 @@
+package publisher
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+)
+
+// Metrics counts the messages published by a backend.
+type Metrics struct {
+	backend   string
+	published metric.Int64Counter
+}
+
+// NewMetrics instantiates the Metrics for the backend, for example "kafka".
+func NewMetrics(backend string) Metrics {
+	return Metrics{
+		backend: backend,
+		published: metric.Must(global.Meter("publisher")).NewInt64Counter("publisher.messages",
+			metric.WithDescription("Published messages by backend, event and result")),
+	}
+}
+
+// Record counts the message as published successfully when the error is nil, it is meant to be deferred
+// using the named error result.
+func (m Metrics) Record(ctx context.Context, event string, err *error) {
+	result := "success"
+	if *err != nil {
+		result = "failure"
+	}
+
+	m.published.Add(ctx, 1,
+		attribute.String("backend", m.backend),
+		attribute.String("event", event),
+		attribute.String("result", result),
+	)
+}
@@ -13,6 +13,7 @@ type TaskPublisherClient interface {
 type TaskPublisher interface {
 	Created(ctx context.Context, task internal.Task) error
 	Deleted(ctx context.Context, id string) error
+	Restored(ctx context.Context, task internal.Task) error
 	Updated(ctx context.Context, task internal.Task) error
 }
 
@@ -34,6 +35,10 @@ func (p *Task) Deleted(ctx context.Context, id string) error {
 	return p.client.Publish(ctx, "Task.Deleted", "task.event.deleted", id)
 }
 
+func (p *Task) Restored(ctx context.Context, task internal.Task) error {
+	return p.client.Publish(ctx, "Task.Restored", "task.event.restored", task)
+}
+
 func (p *Task) Updated(ctx context.Context, task internal.Task) error {
 	return p.client.Publish(ctx, "Task.Updated", "task.event.updated", task)
 }
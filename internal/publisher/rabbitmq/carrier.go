@@ -0,0 +1,38 @@
+package rabbitmq
+
+import (
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var _ propagation.TextMapCarrier = TableCarrier{}
+
+// TableCarrier adapts the AMQP headers to propagate the trace context, consumers use it to extract the context
+// injected when publishing.
+type TableCarrier amqp.Table
+
+// Get returns the value associated with the passed key.
+func (c TableCarrier) Get(key string) string {
+	val, ok := c[key].(string)
+	if !ok {
+		return ""
+	}
+
+	return val
+}
+
+// Set stores the key-value pair.
+func (c TableCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys lists the keys stored in this carrier.
+func (c TableCarrier) Keys() []string {
+	res := make([]string, 0, len(c))
+
+	for k := range c {
+		res = append(res, k)
+	}
+
+	return res
+}
@@ -7,22 +7,26 @@ import (
 	"time"
 
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/publisher"
 )
 
 // Task represents the repository used for publishing Task records.
 type Task struct {
-	ch *amqp.Channel
+	ch      *amqp.Channel
+	metrics publisher.Metrics
 }
 
 // NewTask instantiates the Task repository.
 func NewTask(channel *amqp.Channel) (*Task, error) {
 	return &Task{
-		ch: channel,
+		ch:      channel,
+		metrics: publisher.NewMetrics("rabbitmq"),
 	}, nil
 }
 
@@ -36,14 +40,20 @@ func (t *Task) Deleted(ctx context.Context, id string) error {
 	return t.publish(ctx, "Task.Deleted", "tasks.event.deleted", id)
 }
 
+// Restored publishes a message indicating a task was restored from the trash.
+func (t *Task) Restored(ctx context.Context, task internal.Task) error {
+	return t.publish(ctx, "Task.Restored", "tasks.event.restored", task)
+}
+
 // Updated publishes a message indicating a task was updated.
 func (t *Task) Updated(ctx context.Context, task internal.Task) error {
 	return t.publish(ctx, "Task.Updated", "tasks.event.updated", task)
 }
 
-func (t *Task) publish(ctx context.Context, spanName, routingKey string, e interface{}) error {
-	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("rabbitmq").Start(ctx, spanName)
+func (t *Task) publish(ctx context.Context, spanName, routingKey string, e interface{}) (err error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("rabbitmq").Start(ctx, spanName)
 	defer span.End()
+	defer t.metrics.Record(ctx, routingKey, &err)
 
 	span.SetAttributes(
 		attribute.KeyValue{
@@ -65,19 +75,24 @@ func (t *Task) publish(ctx context.Context, spanName, routingKey string, e inter
 		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "gob.Encode")
 	}
 
-	err := t.ch.Publish(
+	headers := TableCarrier{}
+
+	otel.GetTextMapPropagator().Inject(ctx, headers)
+
+	err = t.ch.Publish(
 		"tasks",    // exchange
 		routingKey, // routing key
 		false,      // mandatory
 		false,      // immediate
 		amqp.Publishing{
 			AppId:       "tasks-rest-server",
+			Headers:     amqp.Table(headers),
 			ContentType: "application/x-encoding-gob", // XXX: We will revisit this in future episodes
 			Body:        b.Bytes(),
 			Timestamp:   time.Now(),
 		})
 	if err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "ch.Publish")
+		return internal.WrapErrorf(err, internal.ErrCodeUnavailable, "ch.Publish")
 	}
 
 	return nil
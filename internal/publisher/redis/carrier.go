@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var _ propagation.TextMapCarrier = HeadersCarrier{}
+
+// HeadersCarrier adapts the Envelope headers to propagate the trace context.
+type HeadersCarrier map[string]string
+
+// Get returns the value associated with the passed key.
+func (c HeadersCarrier) Get(key string) string {
+	return c[key]
+}
+
+// Set stores the key-value pair.
+func (c HeadersCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys lists the keys stored in this carrier.
+func (c HeadersCarrier) Keys() []string {
+	res := make([]string, 0, len(c))
+
+	for k := range c {
+		res = append(res, k)
+	}
+
+	return res
+}
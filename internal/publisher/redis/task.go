@@ -6,21 +6,32 @@ import (
 	"encoding/json"
 
 	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/publisher"
 )
 
+// Envelope wraps the published values to propagate the trace context, consumers use its Headers to extract the
+// context injected when publishing.
+type Envelope struct {
+	Headers HeadersCarrier `json:"headers"`
+	Value   interface{}    `json:"value"`
+}
+
 type Task struct {
-	client *redis.Client
+	client  *redis.Client
+	metrics publisher.Metrics
 }
 
 // NewTask instantiates the Task repository.
 func NewTask(client *redis.Client) *Task {
 	return &Task{
-		client: client,
+		client:  client,
+		metrics: publisher.NewMetrics("redis"),
 	}
 }
 
@@ -34,14 +45,20 @@ func (t *Task) Deleted(ctx context.Context, id string) error {
 	return t.Publish(ctx, "Task.Deleted", "tasks.event.deleted", id)
 }
 
+// Restored publishes a message indicating a task was restored from the trash.
+func (t *Task) Restored(ctx context.Context, task internal.Task) error {
+	return t.Publish(ctx, "Task.Restored", "tasks.event.restored", task)
+}
+
 // Updated publishes a message indicating a task was updated.
 func (t *Task) Updated(ctx context.Context, task internal.Task) error {
 	return t.Publish(ctx, "Task.Updated", "tasks.event.updated", task)
 }
 
-func (t *Task) Publish(ctx context.Context, spanName, channel string, e interface{}) error {
+func (t *Task) Publish(ctx context.Context, spanName, channel string, e interface{}) (err error) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("redis").Start(ctx, spanName)
 	defer span.End()
+	defer t.metrics.Record(ctx, channel, &err)
 
 	span.SetAttributes(
 		semconv.DBSystemRedis,
@@ -55,13 +72,20 @@ func (t *Task) Publish(ctx context.Context, spanName, channel string, e interfac
 
 	var b bytes.Buffer
 
-	if err := json.NewEncoder(&b).Encode(e); err != nil {
+	evt := Envelope{
+		Headers: HeadersCarrier{},
+		Value:   e,
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, evt.Headers)
+
+	if err := json.NewEncoder(&b).Encode(evt); err != nil {
 		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "json.Encode")
 	}
 
 	res := t.client.Publish(ctx, channel, b.Bytes())
 	if err := res.Err(); err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "client.Publish")
+		return internal.WrapErrorf(err, internal.ErrCodeUnavailable, "client.Publish")
 	}
 
 	return nil
@@ -0,0 +1,192 @@
+package memcached
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/mercari/go-circuitbreaker"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+)
+
+const (
+	clientTimeout = 100 * time.Millisecond
+	clientRetries = 2
+	clientBackoff = 10 * time.Millisecond
+)
+
+// Client wraps the memcached client for making the cache optional: every operation has a timeout, failures
+// are tracked by a circuit breaker and those failing fast, like refused connections, are retried a few times.
+// Timeouts are not retried, a slow server would otherwise add latency to every request until the breaker
+// opens. When the breaker is open operations fail immediately, callers are expected to treat those errors as
+// cache misses and use the original store instead.
+//
+// The same Client is meant to be shared by all the decorators, so they share the breaker as well.
+type Client struct {
+	mc      *memcache.Client
+	cb      *circuitbreaker.CircuitBreaker
+	logger  *zap.Logger
+	metrics clientMetrics
+}
+
+// NewClient instantiates the Client, it replaces the network timeout of the memcached client.
+func NewClient(mc *memcache.Client, logger *zap.Logger) *Client {
+	// XXX: The memcached client does not support contexts, its own network timeout is what bounds each
+	// operation; the deadline is set when getting the connection so the dial and the operation can take up to
+	// "clientTimeout" each.
+	mc.Timeout = clientTimeout
+
+	return &Client{
+		mc:      mc,
+		logger:  logger,
+		metrics: newClientMetrics(),
+		cb: circuitbreaker.New(
+			circuitbreaker.WithOpenTimeout(10*time.Second),
+			circuitbreaker.WithTripFunc(circuitbreaker.NewTripFuncConsecutiveFailures(5)),
+			circuitbreaker.WithOnStateChangeHookFn(func(oldState, newState circuitbreaker.State) {
+				logger.Info("memcached state changed",
+					zap.String("old", string(oldState)),
+					zap.String("new", string(newState)),
+				)
+			}),
+		),
+	}
+}
+
+func (c *Client) Add(ctx context.Context, item *memcache.Item) error {
+	_, err := c.do(ctx, "add", item.Key, func() (interface{}, error) {
+		return nil, c.mc.Add(item)
+	})
+
+	return err
+}
+
+func (c *Client) Delete(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "delete", key, func() (interface{}, error) {
+		return nil, c.mc.Delete(key)
+	})
+
+	return err
+}
+
+func (c *Client) Get(ctx context.Context, key string) (*memcache.Item, error) {
+	res, err := c.do(ctx, "get", key, func() (interface{}, error) {
+		return c.mc.Get(key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*memcache.Item), nil //nolint: forcetypeassert
+}
+
+func (c *Client) GetMulti(ctx context.Context, keys []string) (map[string]*memcache.Item, error) {
+	res, err := c.do(ctx, "get_multi", strings.Join(keys, " "), func() (interface{}, error) {
+		return c.mc.GetMulti(keys)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(map[string]*memcache.Item), nil //nolint: forcetypeassert
+}
+
+func (c *Client) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	res, err := c.do(ctx, "incr", key, func() (interface{}, error) {
+		return c.mc.Increment(key, delta)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(uint64), nil //nolint: forcetypeassert
+}
+
+func (c *Client) Set(ctx context.Context, item *memcache.Item) error {
+	_, err := c.do(ctx, "set", item.Key, func() (interface{}, error) {
+		return nil, c.mc.Set(item)
+	})
+
+	return err
+}
+
+// do runs the operation through the circuit breaker, retrying it when it fails fast.
+func (c *Client) do(ctx context.Context, op, key string, fn func() (interface{}, error)) (interface{}, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("memcached").Start(ctx, "memcached."+op)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.system", "memcached"),
+		attribute.String("db.operation", op),
+	)
+
+	var (
+		res interface{}
+		err error
+	)
+
+	start := time.Now()
+
+	for attempt := 0; attempt <= clientRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, internal.WrapErrorf(ctx.Err(), ErrorCode(ctx.Err()), "memcached %s", op)
+			case <-time.After(clientBackoff * time.Duration(attempt)):
+			}
+		}
+
+		res, err = c.cb.Do(ctx, func() (interface{}, error) {
+			res, err := fn()
+			if isExpected(err) {
+				return res, circuitbreaker.MarkAsSuccess(err)
+			}
+
+			return res, err
+		})
+		if !isRetryable(err) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	c.metrics.record(ctx, op, start, err != nil && !isExpected(err))
+
+	if err != nil {
+		if !isExpected(err) {
+			span.RecordError(err)
+
+			c.logger.Warn("memcached operation failed",
+				zap.String("op", op),
+				zap.String("key", key),
+				zap.Error(err),
+			)
+		}
+
+		return nil, internal.WrapErrorf(err, ErrorCode(err), "memcached %s", op)
+	}
+
+	return res, nil
+}
+
+// isExpected indicates whether the error is part of the regular memcached protocol, and therefore should not
+// be considered as a failure.
+func isExpected(err error) bool {
+	return errors.Is(err, memcache.ErrCacheMiss) ||
+		errors.Is(err, memcache.ErrNotStored) ||
+		errors.Is(err, memcache.ErrCASConflict) ||
+		errors.Is(err, memcache.ErrMalformedKey)
+}
+
+// isRetryable indicates whether the operation failed fast, timeouts are not retried.
+func isRetryable(err error) bool {
+	return err != nil &&
+		!isExpected(err) &&
+		!errors.Is(err, circuitbreaker.ErrOpen) &&
+		ErrorCode(err) != internal.ErrCodeDeadlineExceeded
+}
@@ -0,0 +1,106 @@
+package memcached_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/memcached"
+	"github.com/lrweck/todo/internal/repository/memcached/memcachedtesting"
+)
+
+func TestClient_Timeout(t *testing.T) {
+	t.Parallel()
+
+	server := memcachedtesting.NewServer(t)
+	server.SetDelay(500 * time.Millisecond)
+
+	client := memcached.NewClient(memcache.New(server.Addr()), zap.NewNop())
+
+	start := time.Now()
+
+	_, err := client.Get(context.Background(), "key")
+	if internal.Code(err) != internal.ErrCodeDeadlineExceeded {
+		t.Fatalf("expected deadline exceeded error, got %v", err)
+	}
+
+	// Timeouts are not retried.
+
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Fatalf("expected failing fast, took %s", elapsed)
+	}
+
+	if calls := server.Calls("gets"); calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestClient_Breaker(t *testing.T) {
+	t.Parallel()
+
+	server := memcachedtesting.NewServer(t)
+	server.SetDelay(500 * time.Millisecond)
+
+	client := memcached.NewClient(memcache.New(server.Addr()), zap.NewNop())
+
+	for i := 0; i < 5; i++ {
+		_, _ = client.Get(context.Background(), "key")
+	}
+
+	// Opened by the consecutive failures, every decorator sharing the client skips the server from now on.
+
+	start := time.Now()
+
+	_, err := client.Get(context.Background(), "key")
+	if internal.Code(err) != internal.ErrCodeUnavailable {
+		t.Fatalf("expected unavailable error, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("expected failing immediately, took %s", elapsed)
+	}
+
+	store := &memcachedtesting.FakeSearchableTaskStore{}
+
+	if _, err := memcached.NewSearchableTask(client, store).
+		Search(context.Background(), internal.SearchParams{Size: 10}); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if calls := server.Calls("gets"); calls != 5 {
+		t.Fatalf("expected 5 calls, got %d", calls)
+	}
+}
+
+func TestClient_Expected(t *testing.T) {
+	t.Parallel()
+
+	server := memcachedtesting.NewServer(t)
+
+	client := memcached.NewClient(memcache.New(server.Addr()), zap.NewNop())
+
+	// Cache misses are part of the protocol, they don't open the breaker.
+
+	for i := 0; i < 10; i++ {
+		if _, err := client.Get(context.Background(), "missing"); internal.Code(err) != internal.ErrCodeNotFound {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	}
+
+	if err := client.Set(context.Background(), &memcache.Item{Key: "key", Value: []byte("value")}); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	item, err := client.Get(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if string(item.Value) != "value" {
+		t.Fatalf("expected value, got %s", item.Value)
+	}
+}
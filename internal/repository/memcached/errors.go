@@ -0,0 +1,47 @@
+package memcached
+
+import (
+	"errors"
+	"net"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/mercari/go-circuitbreaker"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// ErrorCode classifies the errors returned by the memcached client.
+func ErrorCode(err error) internal.ErrorCode {
+	switch {
+	case errors.Is(err, memcache.ErrCacheMiss):
+		return internal.ErrCodeNotFound
+	case errors.Is(err, memcache.ErrNotStored), errors.Is(err, memcache.ErrCASConflict):
+		return internal.ErrCodeConflict
+	case errors.Is(err, memcache.ErrMalformedKey):
+		return internal.ErrCodeInvalidArgument
+	case errors.Is(err, memcache.ErrNoServers),
+		errors.Is(err, memcache.ErrServerError),
+		errors.Is(err, circuitbreaker.ErrOpen):
+		return internal.ErrCodeUnavailable
+	}
+
+	if code, ok := internal.ContextCode(err); ok {
+		return code
+	}
+
+	var connErr *memcache.ConnectTimeoutError
+	if errors.As(err, &connErr) {
+		return internal.ErrCodeDeadlineExceeded
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return internal.ErrCodeDeadlineExceeded
+		}
+
+		return internal.ErrCodeUnavailable
+	}
+
+	return internal.ErrCodeUnknown
+}
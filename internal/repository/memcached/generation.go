@@ -0,0 +1,83 @@
+package memcached
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+
+	"github.com/lrweck/todo/internal"
+)
+
+const generationKey = "search_generation"
+
+// generations implements namespace versioning for cached search results: the current generation is part of
+// their keys, bumping it makes all of them unreachable at once and they eventually expire.
+//
+// Generations are scoped, the empty scope is global and applies to all search results. Narrower scopes (for
+// example per owner) are meant to be combined with the global one once Tasks have owners.
+type generations struct {
+	client *Client
+}
+
+func newGenerations(client *Client) generations {
+	return generations{
+		client: client,
+	}
+}
+
+// current returns the generation for the scope, initializing it when missing.
+func (g generations) current(ctx context.Context, scope string) (uint64, error) {
+	item, err := g.client.Get(ctx, newGenerationKey(scope))
+	if err != nil {
+		if errors.Is(err, memcache.ErrCacheMiss) {
+			return g.reset(ctx, scope)
+		}
+
+		return 0, internal.WrapErrorf(err, internal.Code(err), "client.Get")
+	}
+
+	gen, err := strconv.ParseUint(string(item.Value), 10, 64)
+	if err != nil {
+		return 0, internal.WrapErrorf(err, internal.ErrCodeUnknown, "strconv.ParseUint")
+	}
+
+	return gen, nil
+}
+
+// bump moves the scope to the next generation.
+func (g generations) bump(ctx context.Context, scope string) {
+	if _, err := g.client.Increment(ctx, newGenerationKey(scope), 1); errors.Is(err, memcache.ErrCacheMiss) {
+		_, _ = g.reset(ctx, scope)
+	}
+}
+
+// reset initializes the generation using the current time, this prevents reusing old generations when the
+// key is evicted.
+func (g generations) reset(ctx context.Context, scope string) (uint64, error) {
+	gen := uint64(time.Now().UnixNano())
+
+	err := g.client.Add(ctx, &memcache.Item{
+		Key:   newGenerationKey(scope),
+		Value: []byte(strconv.FormatUint(gen, 10)),
+	})
+	if err != nil {
+		if errors.Is(err, memcache.ErrNotStored) { // Initialized concurrently by somebody else.
+			return g.current(ctx, scope)
+		}
+
+		return 0, internal.WrapErrorf(err, internal.Code(err), "client.Add")
+	}
+
+	return gen, nil
+}
+
+func newGenerationKey(scope string) string {
+	if scope == "" {
+		return generationKey
+	}
+
+	return generationKey + "_" + scope
+}
@@ -2,30 +2,33 @@ package memcached
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
 	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
+	"go.uber.org/zap"
 
 	"github.com/lrweck/todo/internal"
 )
 
-// XXX: "delete" and "set" intentionally ignore errors, a better approach
-// would be to implement an unexported "client" type defining all the three
-// methods defined in this file that includes a Circuit Breaker for logging
-// errors and retry as needed.
-// See https://youtu.be/UnL2iGcD7vE for more details about that pattern.
+// NOTE: "delete" and "set" don't return errors, those are logged by the client and ignored because a cache
+// failure must not fail the request.
 
-func deleteTask(client *memcache.Client, key string) {
-	_ = client.Delete(key)
+func deleteTask(ctx context.Context, client *Client, key string) {
+	_ = client.Delete(ctx, key)
 }
 
-func getTask(client *memcache.Client, key string, target interface{}) error {
-	item, err := client.Get(key)
+func getTask(ctx context.Context, client *Client, key string, target interface{}) error {
+	item, err := client.Get(ctx, key)
 	if err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "client.Get")
+		return internal.WrapErrorf(err, internal.Code(err), "client.Get")
 	}
 
+	return decodeTask(item, target)
+}
+
+func decodeTask(item *memcache.Item, target interface{}) error {
 	if err := gob.NewDecoder(bytes.NewReader(item.Value)).Decode(target); err != nil {
 		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "gob.NewDecoder")
 	}
@@ -33,14 +36,16 @@ func getTask(client *memcache.Client, key string, target interface{}) error {
 	return nil
 }
 
-func setTask(client *memcache.Client, key string, value interface{}, expiration time.Duration) {
+func setTask(ctx context.Context, client *Client, key string, value interface{}, expiration time.Duration) {
 	var b bytes.Buffer
 
 	if err := gob.NewEncoder(&b).Encode(value); err != nil {
+		client.logger.Warn("encoding value failed", zap.Error(err))
+
 		return
 	}
 
-	_ = client.Set(&memcache.Item{
+	_ = client.Set(ctx, &memcache.Item{
 		Key:        key,
 		Value:      b.Bytes(),
 		Expiration: int32(time.Now().Add(expiration).Unix()),
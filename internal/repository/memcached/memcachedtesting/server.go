@@ -0,0 +1,278 @@
+// Package memcachedtesting implements an in-memory memcached server for testing the memcached package without
+// running the real one.
+package memcachedtesting
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Server implements the subset of the memcached text protocol used by the memcached client, expiration times
+// are ignored.
+type Server struct {
+	ln net.Listener
+
+	mu     sync.Mutex
+	closed bool
+	conns  map[net.Conn]struct{}
+	items  map[string][]byte
+	calls  map[string]int
+	delay  time.Duration
+	cas    uint64
+}
+
+// NewServer starts listening on a random local port, the server is closed when the test completes.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("Couldn't listen: %s", err)
+	}
+
+	s := Server{
+		ln:    ln,
+		conns: make(map[net.Conn]struct{}),
+		items: make(map[string][]byte),
+		calls: make(map[string]int),
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		s.serve(&wg)
+	}()
+
+	tb.Cleanup(func() {
+		_ = ln.Close()
+
+		s.mu.Lock()
+		s.closed = true
+		for conn := range s.conns {
+			_ = conn.Close()
+		}
+		s.mu.Unlock()
+
+		wg.Wait()
+	})
+
+	return &s
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// SetDelay makes the server wait before replying to each command.
+func (s *Server) SetDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delay = d
+}
+
+// Calls returns the number of times the command was received, for example "gets" or "set".
+func (s *Server) Calls(cmd string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[cmd]
+}
+
+// Get returns the stored value.
+func (s *Server) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.items[key]
+
+	return val, ok
+}
+
+// Set stores the value.
+func (s *Server) Set(key string, val []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[key] = val
+}
+
+// Delete removes the value.
+func (s *Server) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.items, key)
+}
+
+// Keys returns the stored keys.
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.items))
+
+	for key := range s.items {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (s *Server) serve(wg *sync.WaitGroup) {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			s.handle(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+
+			_ = conn.Close()
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+
+	for {
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var data []byte
+
+		if fields[0] == "set" || fields[0] == "add" {
+			if len(fields) < 5 {
+				return
+			}
+
+			n, err := strconv.Atoi(fields[4])
+			if err != nil {
+				return
+			}
+
+			data = make([]byte, n+2)
+
+			if _, err := io.ReadFull(rw, data); err != nil {
+				return
+			}
+
+			data = data[:n]
+		}
+
+		s.mu.Lock()
+		s.calls[fields[0]]++
+		delay := s.delay
+		s.mu.Unlock()
+
+		time.Sleep(delay)
+
+		if _, err := rw.WriteString(s.exec(fields, data)); err != nil {
+			return
+		}
+
+		if err := rw.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) exec(fields []string, data []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch cmd, args := fields[0], fields[1:]; {
+	case (cmd == "get" || cmd == "gets") && len(args) > 0:
+		var b strings.Builder
+
+		for _, key := range args {
+			if val, ok := s.items[key]; ok {
+				s.cas++
+				fmt.Fprintf(&b, "VALUE %s 0 %d %d\r\n%s\r\n", key, len(val), s.cas, val)
+			}
+		}
+
+		return b.String() + "END\r\n"
+	case cmd == "set" && len(args) >= 4:
+		s.items[args[0]] = data
+
+		return "STORED\r\n"
+	case cmd == "add" && len(args) >= 4:
+		if _, ok := s.items[args[0]]; ok {
+			return "NOT_STORED\r\n"
+		}
+
+		s.items[args[0]] = data
+
+		return "STORED\r\n"
+	case cmd == "delete" && len(args) == 1:
+		if _, ok := s.items[args[0]]; !ok {
+			return "NOT_FOUND\r\n"
+		}
+
+		delete(s.items, args[0])
+
+		return "DELETED\r\n"
+	case cmd == "incr" && len(args) == 2:
+		val, ok := s.items[args[0]]
+		if !ok {
+			return "NOT_FOUND\r\n"
+		}
+
+		cur, err := strconv.ParseUint(string(val), 10, 64)
+		if err != nil {
+			return "CLIENT_ERROR cannot increment or decrement non-numeric value\r\n"
+		}
+
+		delta, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return "CLIENT_ERROR invalid numeric delta argument\r\n"
+		}
+
+		cur += delta
+		s.items[args[0]] = []byte(strconv.FormatUint(cur, 10))
+
+		return strconv.FormatUint(cur, 10) + "\r\n"
+	}
+
+	return "ERROR\r\n"
+}
@@ -0,0 +1,116 @@
+package memcached
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+)
+
+// findMetrics counts how "Task.Find" requests are served.
+type findMetrics struct {
+	hits      metric.Int64Counter
+	misses    metric.Int64Counter
+	coalesced metric.Int64Counter
+	stale     metric.Int64Counter
+	errors    metric.Int64Counter
+}
+
+func newFindMetrics() findMetrics {
+	meter := metric.Must(global.Meter("memcached"))
+
+	return findMetrics{
+		hits: meter.NewInt64Counter("memcached.task.find.hits",
+			metric.WithDescription("Requests served from the cache, including cached not found values")),
+		misses: meter.NewInt64Counter("memcached.task.find.misses",
+			metric.WithDescription("Requests that had to use the original store")),
+		coalesced: meter.NewInt64Counter("memcached.task.find.coalesced",
+			metric.WithDescription("Requests that shared the result of a concurrent call to the original store")),
+		stale: meter.NewInt64Counter("memcached.task.find.stale",
+			metric.WithDescription("Requests served from the cache while the value was being refreshed early")),
+		errors: meter.NewInt64Counter("memcached.task.find.errors",
+			metric.WithDescription("Requests that had to use the original store because the cache failed")),
+	}
+}
+
+func (m findMetrics) hit(ctx context.Context) {
+	m.hits.Add(ctx, 1)
+}
+
+func (m findMetrics) miss(ctx context.Context) {
+	m.misses.Add(ctx, 1)
+}
+
+func (m findMetrics) coalesce(ctx context.Context) {
+	m.coalesced.Add(ctx, 1)
+}
+
+func (m findMetrics) serveStale(ctx context.Context) {
+	m.stale.Add(ctx, 1)
+}
+
+func (m findMetrics) fail(ctx context.Context) {
+	m.errors.Add(ctx, 1)
+}
+
+// searchMetrics counts how "SearchableTask.Search" requests are served.
+type searchMetrics struct {
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+	errors metric.Int64Counter
+}
+
+func newSearchMetrics() searchMetrics {
+	meter := metric.Must(global.Meter("memcached"))
+
+	return searchMetrics{
+		hits: meter.NewInt64Counter("memcached.search.hits",
+			metric.WithDescription("Search requests served from the cache")),
+		misses: meter.NewInt64Counter("memcached.search.misses",
+			metric.WithDescription("Search requests that had to use the original store")),
+		errors: meter.NewInt64Counter("memcached.search.errors",
+			metric.WithDescription("Search requests that had to use the original store because the cache failed")),
+	}
+}
+
+func (m searchMetrics) hit(ctx context.Context) {
+	m.hits.Add(ctx, 1)
+}
+
+func (m searchMetrics) miss(ctx context.Context) {
+	m.misses.Add(ctx, 1)
+}
+
+func (m searchMetrics) fail(ctx context.Context) {
+	m.errors.Add(ctx, 1)
+}
+
+// clientMetrics measures the calls made to memcached.
+type clientMetrics struct {
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+func newClientMetrics() clientMetrics {
+	meter := metric.Must(global.Meter("memcached"))
+
+	return clientMetrics{
+		duration: meter.NewFloat64Histogram("memcached.client.duration",
+			metric.WithDescription("Duration of the memcached operations, including retries"),
+			metric.WithUnit("ms")),
+		errors: meter.NewInt64Counter("memcached.client.errors",
+			metric.WithDescription("Failed memcached operations, cache misses are excluded")),
+	}
+}
+
+func (m clientMetrics) record(ctx context.Context, op string, start time.Time, failed bool) {
+	attr := attribute.String("operation", op)
+
+	m.duration.Record(ctx, float64(time.Since(start))/float64(time.Millisecond), attr)
+
+	if failed {
+		m.errors.Add(ctx, 1, attr)
+	}
+}
@@ -2,21 +2,23 @@ package memcached
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
-	"github.com/bradfitz/gomemcache/memcache"
-
 	"github.com/lrweck/todo/internal"
 )
 
-// SearchableTask ...
+// SearchableTask caches search results, those are invalidated when the indexed tasks change.
 type SearchableTask struct {
-	client *memcache.Client
-	orig   SearchableTaskStore
+	client      *Client
+	orig        SearchableTaskStore
+	generations generations
+	metrics     searchMetrics
 }
 
+//counterfeiter:generate -o memcachedtesting/searchable_task_store.gen.go . SearchableTaskStore
+
+// SearchableTaskStore defines the original store used for searching the Task records missing in the cache.
 type SearchableTaskStore interface {
 	Delete(ctx context.Context, id string) error
 	Index(ctx context.Context, task internal.Task) error
@@ -24,52 +26,76 @@ type SearchableTaskStore interface {
 }
 
 // NewSearchableTask instantiates the Task repository.
-func NewSearchableTask(client *memcache.Client, orig SearchableTaskStore) *SearchableTask {
+func NewSearchableTask(client *Client, orig SearchableTaskStore) *SearchableTask {
 	return &SearchableTask{
-		client: client,
-		orig:   orig,
+		client:      client,
+		orig:        orig,
+		generations: newGenerations(client),
+		metrics:     newSearchMetrics(),
 	}
 }
 
-// Index ...
+// Index indexes the task and invalidates the cached search results.
 func (t *SearchableTask) Index(ctx context.Context, task internal.Task) error {
 	if err := t.orig.Index(ctx, task); err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "orig.Index")
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Index")
 	}
 
+	t.generations.bump(ctx, "")
+
 	return nil
 }
 
-// Delete ...
+// Delete removes the task from the index and invalidates the cached search results.
 func (t *SearchableTask) Delete(ctx context.Context, id string) error {
 	if err := t.orig.Delete(ctx, id); err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "orig.Delete")
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Delete")
 	}
 
+	t.generations.bump(ctx, "")
+
 	return nil
 }
 
-// Search ...
+// Search returns the cached results for the current generation, when missing the original store is used.
 func (t *SearchableTask) Search(ctx context.Context, args internal.SearchParams) (internal.SearchResults, error) {
-	key := newSearchableKey(args)
+	gen, err := t.generations.current(ctx, "")
+	if err != nil {
+		// Without a generation results can't be safely cached.
+		res, err := t.orig.Search(ctx, args)
+		if err != nil {
+			return internal.SearchResults{}, internal.WrapErrorf(err, internal.Code(err), "orig.Search")
+		}
+
+		return res, nil
+	}
+
+	key := fmt.Sprintf("%d_%s", gen, newSearchableKey(args))
 
 	var res internal.SearchResults
 
-	if err := getTask(t.client, key, &res); err != nil {
-		if errors.Is(err, memcache.ErrCacheMiss) {
-			res, err := t.orig.Search(ctx, args)
-			if err != nil {
-				return internal.SearchResults{}, internal.WrapErrorf(err, internal.ErrCodeUnknown, "orig.Search")
-			}
+	err = getTask(ctx, t.client, key, &res)
+	if err == nil {
+		t.metrics.hit(ctx)
 
-			setTask(t.client, key, &res, 25*time.Second)
+		return res, nil
+	}
 
-			return res, nil
-		}
+	// Cache misses and cache failures are handled the same way, but measured separately.
 
-		return internal.SearchResults{}, internal.WrapErrorf(err, internal.ErrCodeUnknown, "get")
+	if internal.Code(err) == internal.ErrCodeNotFound {
+		t.metrics.miss(ctx)
+	} else {
+		t.metrics.fail(ctx)
 	}
 
+	res, err = t.orig.Search(ctx, args)
+	if err != nil {
+		return internal.SearchResults{}, internal.WrapErrorf(err, internal.Code(err), "orig.Search")
+	}
+
+	setTask(ctx, t.client, key, &res, 25*time.Second)
+
 	return res, nil
 }
 
@@ -0,0 +1,158 @@
+package memcached_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/memcached"
+	"github.com/lrweck/todo/internal/repository/memcached/memcachedtesting"
+)
+
+func TestSearchableTask_Search(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		write func(*memcached.Task, *memcached.SearchableTask) error
+	}{
+		{
+			"OK: create",
+			func(task *memcached.Task, _ *memcached.SearchableTask) error {
+				_, err := task.Create(context.Background(), internal.CreateParams{Description: "new"})
+
+				return err
+			},
+		},
+		{
+			"OK: update",
+			func(task *memcached.Task, _ *memcached.SearchableTask) error {
+				return task.Update(context.Background(), "1", "updated", internal.PriorityHigh, internal.Dates{}, true)
+			},
+		},
+		{
+			"OK: delete",
+			func(task *memcached.Task, _ *memcached.SearchableTask) error {
+				return task.Delete(context.Background(), "1")
+			},
+		},
+		{
+			"OK: restore",
+			func(task *memcached.Task, _ *memcached.SearchableTask) error {
+				return task.Restore(context.Background(), "1")
+			},
+		},
+		{
+			"OK: index",
+			func(_ *memcached.Task, search *memcached.SearchableTask) error {
+				return search.Index(context.Background(), internal.Task{ID: "1"})
+			},
+		},
+		{
+			"OK: delete from index",
+			func(_ *memcached.Task, search *memcached.SearchableTask) error {
+				return search.Delete(context.Background(), "1")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := memcachedtesting.NewServer(t)
+			client := newClient(t, server)
+
+			store := &memcachedtesting.FakeSearchableTaskStore{}
+			store.SearchReturns(internal.SearchResults{Tasks: []internal.Task{{ID: "1"}}, Total: 1}, nil)
+
+			search := memcached.NewSearchableTask(client, store)
+			task := memcached.NewTask(client, &memcachedtesting.FakeTaskStore{}, zap.NewNop())
+
+			args := internal.SearchParams{Size: 10}
+
+			for i := 0; i < 2; i++ {
+				if _, err := search.Search(context.Background(), args); err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+			}
+
+			if calls := store.SearchCallCount(); calls != 1 {
+				t.Fatalf("expected results to be cached, got %d calls", calls)
+			}
+
+			before := generation(t, server)
+
+			if err := tt.write(task, search); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if after := generation(t, server); after != before+1 {
+				t.Fatalf("expected generation %d, got %d", before+1, after)
+			}
+
+			// Results cached using the previous generation are not used anymore.
+
+			if _, err := search.Search(context.Background(), args); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if calls := store.SearchCallCount(); calls != 2 {
+				t.Fatalf("expected results to be invalidated, got %d calls", calls)
+			}
+		})
+	}
+}
+
+func TestSearchableTask_Search_GenerationEvicted(t *testing.T) {
+	t.Parallel()
+
+	server := memcachedtesting.NewServer(t)
+
+	store := &memcachedtesting.FakeSearchableTaskStore{}
+
+	search := memcached.NewSearchableTask(newClient(t, server), store)
+
+	if _, err := search.Search(context.Background(), internal.SearchParams{Size: 10}); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	before := generation(t, server)
+
+	// Evicted generations are not reused, otherwise results cached before invalidating them would be used.
+
+	server.Delete("search_generation")
+
+	if _, err := search.Search(context.Background(), internal.SearchParams{Size: 10}); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if after := generation(t, server); after <= before {
+		t.Fatalf("expected generation greater than %d, got %d", before, after)
+	}
+
+	if calls := store.SearchCallCount(); calls != 2 {
+		t.Fatalf("expected results to be invalidated, got %d calls", calls)
+	}
+}
+
+func generation(tb testing.TB, server *memcachedtesting.Server) uint64 {
+	tb.Helper()
+
+	val, ok := server.Get("search_generation")
+	if !ok {
+		tb.Fatalf("expected generation to be initialized")
+	}
+
+	gen, err := strconv.ParseUint(string(val), 10, 64)
+	if err != nil {
+		tb.Fatalf("expected valid generation, got %s", val)
+	}
+
+	return gen
+}
@@ -2,88 +2,316 @@ package memcached
 
 import (
 	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync/atomic"
 	"time"
 
-	"github.com/bradfitz/gomemcache/memcache"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/lrweck/todo/internal"
 )
 
+const (
+	// notFoundExpiration is how long IDs of missing tasks are cached.
+	notFoundExpiration = 30 * time.Second
+
+	// loadTimeout is how long getting a missing task from the original store can take.
+	loadTimeout = 5 * time.Second
+
+	// xfetchBeta controls how eagerly values are refreshed before expiring, values greater than 1 favor earlier
+	// refreshes. See "Optimal Probabilistic Cache Stampede Prevention" (Vattani et al.).
+	xfetchBeta = 1.0
+)
+
 type Task struct {
-	client     *memcache.Client
-	orig       TaskStore
-	expiration time.Duration
-	logger     *zap.Logger
+	client      *Client
+	orig        TaskStore
+	expiration  int64 // time.Duration, accessed atomically because it can change at runtime.
+	logger      *zap.Logger
+	group       singleflight.Group
+	metrics     findMetrics
+	generations generations
+}
+
+// cachedTask is the value stored in the cache, it includes what is needed for refreshing it early.
+type cachedTask struct {
+	Task     internal.Task
+	NotFound bool
+	Delta    time.Duration // How long it took to get the value from the original store.
+	Expiry   time.Time
 }
 
+// expiresEarly implements XFetch: the closer the value is to expire, and the longer it takes to compute, the
+// more likely is to be considered expired.
+func (c cachedTask) expiresEarly(now time.Time) bool {
+	gap := time.Duration(float64(c.Delta) * xfetchBeta * -math.Log(rand.Float64())) //nolint: gosec
+
+	return !now.Add(gap).Before(c.Expiry)
+}
+
+//go:generate counterfeiter -generate
+
+//counterfeiter:generate -o memcachedtesting/task_store.gen.go . TaskStore
+
+// TaskStore defines the original store used for getting the Task records missing in the cache.
 type TaskStore interface {
 	Create(ctx context.Context, params internal.CreateParams) (internal.Task, error)
 	Delete(ctx context.Context, id string) error
 	Find(ctx context.Context, id string) (internal.Task, error)
+	FindMany(ctx context.Context, ids []string) ([]internal.Task, error)
+	Restore(ctx context.Context, id string) error
+	Trash(ctx context.Context, args internal.TrashParams) (internal.TrashResults, error)
 	Update(ctx context.Context, id, description string, priority internal.Priority, dates internal.Dates, isDone bool) error
 }
 
-func NewTask(client *memcache.Client, orig TaskStore, logger *zap.Logger) *Task {
+// NewTask instantiates the Task repository.
+func NewTask(client *Client, orig TaskStore, logger *zap.Logger) *Task {
 	return &Task{
-		client:     client,
-		orig:       orig,
-		expiration: 10 * time.Minute,
-		logger:     logger,
+		client:      client,
+		orig:        orig,
+		expiration:  int64(10 * time.Minute),
+		logger:      logger,
+		metrics:     newFindMetrics(),
+		generations: newGenerations(client),
 	}
 }
 
+// Expiration returns how long Tasks are cached.
+func (t *Task) Expiration() time.Duration {
+	return time.Duration(atomic.LoadInt64(&t.expiration))
+}
+
+// SetExpiration changes how long Tasks are cached, values already cached keep their expiration.
+func (t *Task) SetExpiration(d time.Duration) {
+	atomic.StoreInt64(&t.expiration, int64(d))
+}
+
 func (t *Task) Create(ctx context.Context, params internal.CreateParams) (internal.Task, error) {
 	task, err := t.orig.Create(ctx, params)
 	if err != nil {
-		return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeUnknown, "orig.Create")
+		return internal.Task{}, internal.WrapErrorf(err, internal.Code(err), "orig.Create")
 	}
 
 	// Write-Through Caching
 
 	t.logger.Info("Create: setting value")
 
-	setTask(t.client, task.ID, &task, t.expiration)
+	expiration := t.Expiration()
+
+	setTask(ctx, t.client, task.ID, &cachedTask{Task: task, Expiry: time.Now().Add(expiration)}, expiration)
+
+	t.generations.bump(ctx, "")
 
 	return task, nil
 }
 
 func (t *Task) Delete(ctx context.Context, id string) error {
 	if err := t.orig.Delete(ctx, id); err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "orig.Delete")
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Delete")
 	}
 
-	deleteTask(t.client, id)
+	deleteTask(ctx, t.client, id)
+
+	t.generations.bump(ctx, "")
 
 	return nil
 }
 
+// Find returns the cached task, concurrent requests for a missing value are coalesced into one call to the
+// original store. Tasks not found are cached as well for a short time.
 func (t *Task) Find(ctx context.Context, id string) (internal.Task, error) {
-	var res internal.Task
+	var item cachedTask
+
+	err := getTask(ctx, t.client, id, &item)
+	if err == nil {
+		t.metrics.hit(ctx)
+
+		if item.NotFound {
+			return internal.Task{}, internal.NewErrorf(internal.ErrCodeNotFound, "task not found")
+		}
+
+		if item.expiresEarly(time.Now()) {
+			t.metrics.serveStale(ctx)
+
+			// XXX: The refresh outlives the request, that's why it uses a detached context.
+			go func() {
+				_, _, _ = t.group.Do(id, func() (interface{}, error) {
+					return t.loadDetached(ctx, id)
+				})
+			}()
+		}
+
+		return item.Task, nil
+	}
+
+	if internal.Code(err) == internal.ErrCodeNotFound {
+		t.metrics.miss(ctx)
+	} else {
+		t.metrics.fail(ctx)
+	}
+
+	// Cache-Aside Caching
+
+	// XXX: The call is shared by all the requests waiting for the same task, it can't use the context of the
+	// first one, otherwise canceling it would fail all of them; each one stops waiting when its own context is
+	// done instead.
+	ch := t.group.DoChan(id, func() (interface{}, error) {
+		return t.loadDetached(ctx, id)
+	})
 
-	t.logger.Info("Find: get value")
+	select {
+	case <-ctx.Done():
+		code, _ := internal.ContextCode(ctx.Err())
 
-	if err := getTask(t.client, id, &res); err == nil {
+		return internal.Task{}, internal.WrapErrorf(ctx.Err(), code, "orig.Find")
+	case res := <-ch:
+		if res.Shared {
+			t.metrics.coalesce(ctx)
+		}
+
+		if res.Err != nil {
+			return internal.Task{}, internal.WrapErrorf(res.Err, internal.Code(res.Err), "orig.Find")
+		}
+
+		return res.Val.(internal.Task), nil //nolint: forcetypeassert
+	}
+}
+
+// FindMany returns the cached tasks, the missing ones are found using one call to the original store and cached
+// afterwards. Tasks not found are not included.
+func (t *Task) FindMany(ctx context.Context, ids []string) ([]internal.Task, error) {
+	items, err := t.client.GetMulti(ctx, ids)
+	if err != nil {
+		t.metrics.fail(ctx)
+	}
+
+	var (
+		res     []internal.Task
+		missing []string
+	)
+
+	for _, id := range ids {
+		var item cachedTask
+
+		if cached, ok := items[id]; !ok || decodeTask(cached, &item) != nil {
+			missing = append(missing, id)
+
+			continue
+		}
+
+		t.metrics.hit(ctx)
+
+		if !item.NotFound {
+			res = append(res, item.Task)
+		}
+	}
+
+	if len(missing) == 0 {
 		return res, nil
 	}
 
-	t.logger.Info("Find: not found, let's cache it")
+	for range missing {
+		t.metrics.miss(ctx)
+	}
 
 	// Cache-Aside Caching
 
+	start := time.Now()
+
+	found, err := t.orig.FindMany(ctx, missing)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.Code(err), "orig.FindMany")
+	}
+
+	delta := time.Since(start)
+	expiration := t.Expiration()
+	notFound := make(map[string]struct{}, len(missing))
+
+	for _, id := range missing {
+		notFound[id] = struct{}{}
+	}
+
+	for _, task := range found {
+		delete(notFound, task.ID)
+
+		setTask(ctx, t.client, task.ID, &cachedTask{
+			Task:   task,
+			Delta:  delta,
+			Expiry: time.Now().Add(expiration),
+		}, expiration)
+	}
+
+	for id := range notFound {
+		setTask(ctx, t.client, id, &cachedTask{NotFound: true}, notFoundExpiration)
+	}
+
+	return append(res, found...), nil
+}
+
+// loadDetached calls load using a context that keeps the values of the received one, like the current span, but
+// not its cancellation; loadTimeout is used instead.
+func (t *Task) loadDetached(ctx context.Context, id string) (internal.Task, error) {
+	ctx, cancel := context.WithTimeout(detachedContext{ctx}, loadTimeout)
+	defer cancel()
+
+	return t.load(ctx, id)
+}
+
+// load gets the task from the original store and caches the result, including when it's not found.
+func (t *Task) load(ctx context.Context, id string) (internal.Task, error) {
+	start := time.Now()
+
 	res, err := t.orig.Find(ctx, id)
 	if err != nil {
-		return res, internal.WrapErrorf(err, internal.ErrCodeUnknown, "orig.Find")
+		var ierr *internal.Error
+		if errors.As(err, &ierr) && ierr.Code() == internal.ErrCodeNotFound {
+			setTask(ctx, t.client, id, &cachedTask{NotFound: true}, notFoundExpiration)
+		}
+
+		return internal.Task{}, err
 	}
 
-	setTask(t.client, res.ID, &res, t.expiration)
+	expiration := t.Expiration()
+
+	setTask(ctx, t.client, res.ID, &cachedTask{
+		Task:   res,
+		Delta:  time.Since(start),
+		Expiry: time.Now().Add(expiration),
+	}, expiration)
+
+	return res, nil
+}
+
+func (t *Task) Restore(ctx context.Context, id string) error {
+	if err := t.orig.Restore(ctx, id); err != nil {
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Restore")
+	}
+
+	// Trashed tasks are never cached, but they could be cached as not found.
+
+	deleteTask(ctx, t.client, id)
+
+	t.generations.bump(ctx, "")
+
+	return nil
+}
+
+// Trash is not cached, trashed tasks are rarely read.
+func (t *Task) Trash(ctx context.Context, args internal.TrashParams) (internal.TrashResults, error) {
+	res, err := t.orig.Trash(ctx, args)
+	if err != nil {
+		return internal.TrashResults{}, internal.WrapErrorf(err, internal.Code(err), "orig.Trash")
+	}
 
 	return res, nil
 }
 
 func (t *Task) Update(ctx context.Context, id string, description string, priority internal.Priority, dates internal.Dates, isDone bool) error {
 	if err := t.orig.Update(ctx, id, description, priority, dates, isDone); err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "orig.Update")
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Update")
 	}
 
 	// Write-Through Caching
@@ -96,14 +324,34 @@ func (t *Task) Update(ctx context.Context, id string, description string, priori
 	// What if any of the following instructions fail? We may end up with stale
 	// values
 
-	deleteTask(t.client, id) // XXX
+	deleteTask(ctx, t.client, id) // XXX
+
+	t.generations.bump(ctx, "")
 
-	task, err := t.orig.Find(ctx, id)
-	if err != nil { // XXX
+	if _, err := t.load(ctx, id); err != nil { // XXX
 		return nil //nolint: nilerr
 	}
 
-	setTask(t.client, task.ID, &task, t.expiration) // XXX
+	return nil
+}
+
+// detachedContext is a context that is never canceled, its values are the ones of the parent.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
 
+func (detachedContext) Done() <-chan struct{} {
 	return nil
 }
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
@@ -0,0 +1,353 @@
+package memcached_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/memcached"
+	"github.com/lrweck/todo/internal/repository/memcached/memcachedtesting"
+)
+
+func TestTask_Find(t *testing.T) {
+	t.Parallel()
+
+	expected := internal.Task{ID: "1", Description: "cached", Priority: internal.PriorityLow}
+
+	tests := []struct {
+		name   string
+		setup  func(*memcachedtesting.FakeTaskStore)
+		calls  int
+		output internal.Task
+		code   internal.ErrorCode
+	}{
+		{
+			"OK: cached after first call",
+			func(s *memcachedtesting.FakeTaskStore) {
+				s.FindReturns(expected, nil)
+			},
+			1,
+			expected,
+			0,
+		},
+		{
+			"ERR: not found is cached",
+			func(s *memcachedtesting.FakeTaskStore) {
+				s.FindReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeNotFound, "not found"))
+			},
+			1,
+			internal.Task{},
+			internal.ErrCodeNotFound,
+		},
+		{
+			"ERR: other errors are not cached",
+			func(s *memcachedtesting.FakeTaskStore) {
+				s.FindReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeUnavailable, "unavailable"))
+			},
+			2,
+			internal.Task{},
+			internal.ErrCodeUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &memcachedtesting.FakeTaskStore{}
+			tt.setup(store)
+
+			task := memcached.NewTask(newClient(t, memcachedtesting.NewServer(t)), store, zap.NewNop())
+
+			for i := 0; i < 2; i++ {
+				actual, err := task.Find(context.Background(), "1")
+				if internal.Code(err) != tt.code {
+					t.Fatalf("expected %s error, got %v", tt.code, err)
+				}
+
+				if diff := cmp.Diff(tt.output, actual); diff != "" {
+					t.Fatalf("expected result does not match: %s", diff)
+				}
+			}
+
+			if calls := store.FindCallCount(); calls != tt.calls {
+				t.Fatalf("expected %d calls to the original store, got %d", tt.calls, calls)
+			}
+		})
+	}
+}
+
+func TestTask_FindMany(t *testing.T) {
+	t.Parallel()
+
+	store := &memcachedtesting.FakeTaskStore{}
+	store.FindReturns(internal.Task{ID: "1"}, nil)
+	store.FindManyReturns([]internal.Task{{ID: "2"}}, nil)
+
+	task := memcached.NewTask(newClient(t, memcachedtesting.NewServer(t)), store, zap.NewNop())
+
+	if _, err := task.Find(context.Background(), "1"); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	// Only the tasks missing in the cache are found, using one call; the ones not found are cached as well.
+
+	actual, err := task.FindMany(context.Background(), []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]internal.Task{{ID: "1"}, {ID: "2"}}, actual); diff != "" {
+		t.Fatalf("expected result does not match: %s", diff)
+	}
+
+	if calls := store.FindManyCallCount(); calls != 1 {
+		t.Fatalf("expected 1 call to the original store, got %d", calls)
+	}
+
+	if _, ids := store.FindManyArgsForCall(0); !cmp.Equal([]string{"2", "3"}, ids) {
+		t.Fatalf("expected missing tasks to be found, got %v", ids)
+	}
+
+	actual, err = task.FindMany(context.Background(), []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]internal.Task{{ID: "1"}, {ID: "2"}}, actual); diff != "" {
+		t.Fatalf("expected result does not match: %s", diff)
+	}
+
+	if calls := store.FindManyCallCount(); calls != 1 {
+		t.Fatalf("expected results to be cached, got %d calls", calls)
+	}
+}
+
+func TestTask_Find_Coalesced(t *testing.T) {
+	t.Parallel()
+
+	server := memcachedtesting.NewServer(t)
+
+	release := make(chan struct{})
+
+	store := &memcachedtesting.FakeTaskStore{}
+	store.FindStub = func(context.Context, string) (internal.Task, error) {
+		<-release
+
+		return internal.Task{ID: "1"}, nil
+	}
+
+	task := memcached.NewTask(newClient(t, server), store, zap.NewNop())
+
+	const requests = 10
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, requests)
+
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := task.Find(context.Background(), "1")
+			errs <- err
+		}()
+	}
+
+	waitFor(t, func() bool { return server.Calls("gets") == requests })
+	time.Sleep(50 * time.Millisecond) // Waiting for the misses to join the call in progress.
+	close(release)
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+	}
+
+	if calls := store.FindCallCount(); calls != 1 {
+		t.Fatalf("expected 1 call to the original store, got %d", calls)
+	}
+}
+
+func TestTask_Find_Canceled(t *testing.T) {
+	t.Parallel()
+
+	server := memcachedtesting.NewServer(t)
+
+	release := make(chan struct{})
+
+	store := &memcachedtesting.FakeTaskStore{}
+	store.FindStub = func(ctx context.Context, _ string) (internal.Task, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return internal.Task{}, ctx.Err()
+		}
+
+		return internal.Task{ID: "1"}, nil
+	}
+
+	task := memcached.NewTask(newClient(t, server), store, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	leader := make(chan error, 1)
+
+	go func() {
+		_, err := task.Find(ctx, "1")
+		leader <- err
+	}()
+
+	waitFor(t, func() bool { return store.FindCallCount() == 1 })
+
+	follower := make(chan error, 1)
+
+	go func() {
+		_, err := task.Find(context.Background(), "1")
+		follower <- err
+	}()
+
+	waitFor(t, func() bool { return server.Calls("gets") == 2 })
+	time.Sleep(50 * time.Millisecond) // Waiting for the follower to join the call in progress.
+
+	// Canceling the first request must not fail the others waiting for the same task.
+
+	cancel()
+
+	if err := <-leader; internal.Code(err) != internal.ErrCodeCanceled {
+		t.Fatalf("expected canceled error, got %v", err)
+	}
+
+	close(release)
+
+	if err := <-follower; err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if calls := store.FindCallCount(); calls != 1 {
+		t.Fatalf("expected 1 call to the original store, got %d", calls)
+	}
+}
+
+func TestTask_Find_EarlyRefresh(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		expiration time.Duration
+		refreshed  bool
+	}{
+		{
+			"OK: expiring",
+			0,
+			true,
+		},
+		{
+			"OK: fresh",
+			time.Hour,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &memcachedtesting.FakeTaskStore{}
+			store.CreateReturns(internal.Task{ID: "1", Description: "created"}, nil)
+			store.FindReturns(internal.Task{ID: "1", Description: "refreshed"}, nil)
+
+			task := memcached.NewTask(newClient(t, memcachedtesting.NewServer(t)), store, zap.NewNop())
+			task.SetExpiration(tt.expiration)
+
+			if _, err := task.Create(context.Background(), internal.CreateParams{}); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			// The cached value is returned right away, even when it's about to expire.
+
+			actual, err := task.Find(context.Background(), "1")
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if actual.Description != "created" {
+				t.Fatalf("expected cached task, got %+v", actual)
+			}
+
+			if !tt.refreshed {
+				time.Sleep(50 * time.Millisecond)
+
+				if calls := store.FindCallCount(); calls != 0 {
+					t.Fatalf("expected no calls to the original store, got %d", calls)
+				}
+
+				return
+			}
+
+			task.SetExpiration(time.Hour)
+
+			waitFor(t, func() bool {
+				actual, err := task.Find(context.Background(), "1")
+
+				return err == nil && actual.Description == "refreshed"
+			})
+		})
+	}
+}
+
+func TestTask_Find_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	server := memcachedtesting.NewServer(t)
+	server.SetDelay(500 * time.Millisecond)
+
+	store := &memcachedtesting.FakeTaskStore{}
+	store.FindReturns(internal.Task{ID: "1"}, nil)
+
+	// A cache failure must not fail the request.
+
+	actual, err := memcached.NewTask(newClient(t, server), store, zap.NewNop()).Find(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if actual.ID != "1" {
+		t.Fatalf("expected task from the original store, got %+v", actual)
+	}
+}
+
+func newClient(tb testing.TB, server *memcachedtesting.Server) *memcached.Client {
+	tb.Helper()
+
+	return memcached.NewClient(memcache.New(server.Addr()), zap.NewNop())
+}
+
+func waitFor(tb testing.TB, cond func() bool) {
+	tb.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+
+	for !cond() {
+		if time.Now().After(deadline) {
+			tb.Fatalf("condition not met before the deadline")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DeleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at < $1
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context, expiresAt time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, DeleteExpiredIdempotencyKeys, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const DeleteIdempotencyKey = `-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE key = $1
+  AND owner = $2
+  AND status_code IS NULL
+`
+
+type DeleteIdempotencyKeyParams struct {
+	Key   string
+	Owner uuid.UUID
+}
+
+func (q *Queries) DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, DeleteIdempotencyKey, arg.Key, arg.Owner)
+	return err
+}
+
+const InsertIdempotencyKey = `-- name: InsertIdempotencyKey :one
+INSERT INTO idempotency_keys (
+  key,
+  fingerprint,
+  expires_at,
+  owner
+)
+VALUES (
+  $1,
+  $2,
+  $3,
+  $4
+)
+ON CONFLICT (key) DO UPDATE SET
+  fingerprint = EXCLUDED.fingerprint,
+  status_code = NULL,
+  headers     = NULL,
+  body        = NULL,
+  expires_at  = EXCLUDED.expires_at,
+  owner       = EXCLUDED.owner
+WHERE idempotency_keys.expires_at < NOW() AT TIME ZONE 'UTC'
+RETURNING owner
+`
+
+type InsertIdempotencyKeyParams struct {
+	Key         string
+	Fingerprint string
+	ExpiresAt   time.Time
+	Owner       uuid.UUID
+}
+
+func (q *Queries) InsertIdempotencyKey(ctx context.Context, arg InsertIdempotencyKeyParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, InsertIdempotencyKey,
+		arg.Key,
+		arg.Fingerprint,
+		arg.ExpiresAt,
+		arg.Owner,
+	)
+	var owner uuid.UUID
+	err := row.Scan(&owner)
+	return owner, err
+}
+
+const SelectIdempotencyKey = `-- name: SelectIdempotencyKey :one
+SELECT
+  key,
+  fingerprint,
+  status_code,
+  headers,
+  body,
+  expires_at,
+  owner
+FROM
+  idempotency_keys
+WHERE
+  key = $1
+LIMIT 1
+`
+
+func (q *Queries) SelectIdempotencyKey(ctx context.Context, key string) (IdempotencyKeys, error) {
+	row := q.db.QueryRow(ctx, SelectIdempotencyKey, key)
+	var i IdempotencyKeys
+	err := row.Scan(
+		&i.Key,
+		&i.Fingerprint,
+		&i.StatusCode,
+		&i.Headers,
+		&i.Body,
+		&i.ExpiresAt,
+		&i.Owner,
+	)
+	return i, err
+}
+
+const UpdateIdempotencyKeyResponse = `-- name: UpdateIdempotencyKeyResponse :execrows
+UPDATE idempotency_keys SET
+  status_code = $2,
+  headers     = $3,
+  body        = $4,
+  expires_at  = $5
+WHERE key = $1
+  AND owner = $6
+  AND status_code IS NULL
+`
+
+type UpdateIdempotencyKeyResponseParams struct {
+	Key        string
+	StatusCode sql.NullInt32
+	Headers    []byte
+	Body       []byte
+	ExpiresAt  time.Time
+	Owner      uuid.UUID
+}
+
+func (q *Queries) UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, UpdateIdempotencyKeyResponse,
+		arg.Key,
+		arg.StatusCode,
+		arg.Headers,
+		arg.Body,
+		arg.ExpiresAt,
+		arg.Owner,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -35,4 +36,15 @@ type Tasks struct {
 	StartDate   sql.NullTime
 	DueDate     sql.NullTime
 	Done        bool
+	DeletedAt   sql.NullTime
+}
+
+type IdempotencyKeys struct {
+	Key         string
+	Fingerprint string
+	StatusCode  sql.NullInt32
+	Headers     []byte
+	Body        []byte
+	ExpiresAt   time.Time
+	Owner       uuid.UUID
 }
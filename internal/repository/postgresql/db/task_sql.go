@@ -7,11 +7,24 @@ import (
 	"github.com/google/uuid"
 )
 
+const CountDeletedTasks = `-- name: CountDeletedTasks :one
+SELECT COUNT(*)
+  FROM tasks
+ WHERE deleted_at IS NOT NULL
+`
+
+func (q *Queries) CountDeletedTasks(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, CountDeletedTasks)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const DeleteTask = `-- name: DeleteTask :one
-DELETE FROM
-  tasks
-WHERE
-  id = $1
+UPDATE tasks SET
+  deleted_at = NOW() AT TIME ZONE 'UTC'
+WHERE id = $1
+  AND deleted_at IS NULL
 RETURNING id AS res
 `
 
@@ -57,6 +70,83 @@ func (q *Queries) InsertTask(ctx context.Context, arg InsertTaskParams) (uuid.UU
 	return id, err
 }
 
+const PurgeDeletedTasks = `-- name: PurgeDeletedTasks :execrows
+DELETE FROM
+  tasks
+WHERE
+  deleted_at IS NOT NULL AND deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedTasks(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	result, err := q.db.Exec(ctx, PurgeDeletedTasks, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const RestoreTask = `-- name: RestoreTask :one
+UPDATE tasks SET
+  deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+RETURNING id AS res
+`
+
+func (q *Queries) RestoreTask(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, RestoreTask, id)
+	var res uuid.UUID
+	err := row.Scan(&res)
+	return res, err
+}
+
+const SelectDeletedTasks = `-- name: SelectDeletedTasks :many
+SELECT id,
+	   description,
+	   priority,
+	   start_date,
+	   due_date,
+	   done,
+	   deleted_at
+  FROM tasks
+ WHERE deleted_at IS NOT NULL
+ ORDER BY deleted_at DESC
+ LIMIT $1
+OFFSET $2`
+
+type SelectDeletedTasksParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) SelectDeletedTasks(ctx context.Context, arg SelectDeletedTasksParams) ([]Tasks, error) {
+	rows, err := q.db.Query(ctx, SelectDeletedTasks, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tasks
+	for rows.Next() {
+		var i Tasks
+		if err := rows.Scan(
+			&i.ID,
+			&i.Description,
+			&i.Priority,
+			&i.StartDate,
+			&i.DueDate,
+			&i.Done,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const SelectTask = `-- name: SelectTask :one
 SELECT id,
 	   description,
@@ -66,6 +156,7 @@ SELECT id,
 	   done
   FROM tasks
  WHERE id = $1
+   AND deleted_at IS NULL
  LIMIT 1`
 
 func (q *Queries) SelectTask(ctx context.Context, id uuid.UUID) (Tasks, error) {
@@ -82,6 +173,44 @@ func (q *Queries) SelectTask(ctx context.Context, id uuid.UUID) (Tasks, error) {
 	return i, err
 }
 
+const SelectTasks = `-- name: SelectTasks :many
+SELECT id,
+	   description,
+	   priority,
+	   start_date,
+	   due_date,
+	   done
+  FROM tasks
+ WHERE id = ANY($1::uuid[])
+   AND deleted_at IS NULL`
+
+func (q *Queries) SelectTasks(ctx context.Context, ids []uuid.UUID) ([]Tasks, error) {
+	rows, err := q.db.Query(ctx, SelectTasks, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tasks
+	for rows.Next() {
+		var i Tasks
+		if err := rows.Scan(
+			&i.ID,
+			&i.Description,
+			&i.Priority,
+			&i.StartDate,
+			&i.DueDate,
+			&i.Done,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const UpdateTask = `-- name: UpdateTask :one
 UPDATE tasks SET
   description = $1,
@@ -90,6 +219,7 @@ UPDATE tasks SET
   due_date    = $4,
   done        = $5
 WHERE id = $6
+  AND deleted_at IS NULL
 RETURNING id AS res
 `
 
@@ -0,0 +1,86 @@
+package postgresql
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// ErrorCode classifies the errors returned by pgx, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+func ErrorCode(err error) internal.ErrorCode {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return internal.ErrCodeNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErrorCode(pgErr.Code)
+	}
+
+	if code, ok := internal.ContextCode(err); ok {
+		return code
+	}
+
+	if pgconn.Timeout(err) {
+		return internal.ErrCodeDeadlineExceeded
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return internal.ErrCodeDeadlineExceeded
+		}
+
+		return internal.ErrCodeUnavailable
+	}
+
+	// XXX: Errors safe to retry happened before sending anything to the server, like failing to connect.
+	var safe interface{ SafeToRetry() bool }
+	if errors.As(err, &safe) && safe.SafeToRetry() {
+		return internal.ErrCodeUnavailable
+	}
+
+	return internal.ErrCodeUnknown
+}
+
+func pgErrorCode(code string) internal.ErrorCode {
+	switch code {
+	case "23505": // unique_violation
+		return internal.ErrCodeAlreadyExists
+	case "23502", // not_null_violation
+		"23503", // foreign_key_violation
+		"23514", // check_violation
+		"22001", // string_data_right_truncation
+		"22007", // invalid_datetime_format
+		"22P02": // invalid_text_representation
+		return internal.ErrCodeInvalidArgument
+	case "40001", // serialization_failure
+		"40P01", // deadlock_detected
+		"55P03": // lock_not_available
+		return internal.ErrCodeConflict
+	case "57014": // query_canceled
+		return internal.ErrCodeCanceled
+	case "42501": // insufficient_privilege
+		return internal.ErrCodePermissionDenied
+	case "57P01", // admin_shutdown
+		"57P02", // crash_shutdown
+		"57P03": // cannot_connect_now
+		return internal.ErrCodeUnavailable
+	}
+
+	switch {
+	case strings.HasPrefix(code, "08"): // connection_exception
+		return internal.ErrCodeUnavailable
+	case strings.HasPrefix(code, "28"): // invalid_authorization_specification
+		return internal.ErrCodeUnauthenticated
+	case strings.HasPrefix(code, "53"): // insufficient_resources
+		return internal.ErrCodeResourceExhausted
+	}
+
+	return internal.ErrCodeUnknown
+}
@@ -0,0 +1,187 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/postgresql/db"
+)
+
+// Idempotency represents the repository used for storing idempotency keys and the responses of the requests
+// using them.
+type Idempotency struct {
+	q *db.Queries
+}
+
+// NewIdempotency instantiates the Idempotency repository.
+func NewIdempotency(d db.DBTX) *Idempotency {
+	return &Idempotency{
+		q: db.New(d),
+	}
+}
+
+// Lock claims the key for the request identified by fingerprint until the received time, acquired is true when
+// the key was not used before or its previous use expired, the returned record includes the owner required for
+// saving or releasing it. Otherwise the record of the request that used it first is returned.
+func (i *Idempotency) Lock(ctx context.Context, key, fingerprint string, until time.Time) (_ internal.IdempotencyRecord, acquired bool, _ error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Idempotency.Lock")
+	span.SetAttributes(attribute.String("db.system", "postgresql"))
+
+	defer span.End()
+
+	// XXX: The owner is generated here instead of by the database, gen_random_uuid requires pgcrypto before
+	// PostgreSQL 13.
+	owner, err := i.q.InsertIdempotencyKey(ctx, db.InsertIdempotencyKeyParams{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   until.UTC(),
+		Owner:       uuid.New(),
+	})
+	if err == nil {
+		return internal.IdempotencyRecord{Fingerprint: fingerprint, Owner: owner.String()}, true, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return internal.IdempotencyRecord{}, false, internal.WrapErrorf(err, ErrorCode(err), "insert idempotency key")
+	}
+
+	res, err := i.q.SelectIdempotencyKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// XXX: The request holding the key failed and released it in the meantime, retrying will succeed.
+			return internal.IdempotencyRecord{}, false, internal.WrapErrorf(err, internal.ErrCodeConflict, "idempotency key released")
+		}
+
+		return internal.IdempotencyRecord{}, false, internal.WrapErrorf(err, ErrorCode(err), "select idempotency key")
+	}
+
+	record := internal.IdempotencyRecord{
+		Fingerprint: res.Fingerprint,
+	}
+
+	if res.StatusCode.Valid {
+		var header http.Header
+
+		if len(res.Headers) > 0 {
+			if err := json.Unmarshal(res.Headers, &header); err != nil {
+				return internal.IdempotencyRecord{}, false, internal.WrapErrorf(err, internal.ErrCodeUnknown, "json.Unmarshal")
+			}
+		}
+
+		record.Response = &internal.IdempotentResponse{
+			StatusCode: int(res.StatusCode.Int32),
+			Header:     header,
+			Body:       res.Body,
+		}
+	}
+
+	return record, false, nil
+}
+
+// Save stores the response of the request holding the key, it is kept until the received time. Saving fails
+// with ErrCodeConflict when the lock expired and the key was claimed by another request in the meantime.
+func (i *Idempotency) Save(ctx context.Context, key, owner string, resp internal.IdempotentResponse, until time.Time) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Idempotency.Save")
+	span.SetAttributes(attribute.String("db.system", "postgresql"))
+
+	defer span.End()
+
+	val, err := uuid.Parse(owner)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid owner")
+	}
+
+	header, err := json.Marshal(resp.Header)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "json.Marshal")
+	}
+
+	count, err := i.q.UpdateIdempotencyKeyResponse(ctx, db.UpdateIdempotencyKeyResponseParams{
+		Key:        key,
+		StatusCode: sql.NullInt32{Int32: int32(resp.StatusCode), Valid: true},
+		Headers:    header,
+		Body:       resp.Body,
+		ExpiresAt:  until.UTC(),
+		Owner:      val,
+	})
+	if err != nil {
+		return internal.WrapErrorf(err, ErrorCode(err), "update idempotency key")
+	}
+
+	if count == 0 {
+		return internal.NewErrorf(internal.ErrCodeConflict, "idempotency key not owned")
+	}
+
+	return nil
+}
+
+// Release frees the key without storing any response, so the request can be retried. Keys claimed by another
+// request in the meantime are kept.
+func (i *Idempotency) Release(ctx context.Context, key, owner string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Idempotency.Release")
+	span.SetAttributes(attribute.String("db.system", "postgresql"))
+
+	defer span.End()
+
+	val, err := uuid.Parse(owner)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid owner")
+	}
+
+	if err := i.q.DeleteIdempotencyKey(ctx, db.DeleteIdempotencyKeyParams{
+		Key:   key,
+		Owner: val,
+	}); err != nil {
+		return internal.WrapErrorf(err, ErrorCode(err), "delete idempotency key")
+	}
+
+	return nil
+}
+
+// Purge deletes the keys that expired before the received time, it returns the number of deleted keys.
+func (i *Idempotency) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Idempotency.Purge")
+	span.SetAttributes(attribute.String("db.system", "postgresql"))
+
+	defer span.End()
+
+	count, err := i.q.DeleteExpiredIdempotencyKeys(ctx, before.UTC())
+	if err != nil {
+		return 0, internal.WrapErrorf(err, ErrorCode(err), "delete expired idempotency keys")
+	}
+
+	return count, nil
+}
+
+// Run purges the expired keys periodically, it blocks until the context is canceled.
+func (i *Idempotency) Run(ctx context.Context, logger *zap.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := i.Purge(ctx, time.Now())
+			if err != nil {
+				logger.Error("purging idempotency keys", zap.Error(err))
+
+				continue
+			}
+
+			logger.Info("idempotency keys purged", zap.Int64("count", count))
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package postgresql_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/postgresql"
+)
+
+func TestIdempotency_Lock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Lock: OK", func(t *testing.T) {
+		t.Parallel()
+
+		store := postgresql.NewIdempotency(newDB(t))
+		ctx := context.Background()
+
+		owned, acquired, err := store.Lock(ctx, "key", "fingerprint", time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if !acquired || owned.Owner == "" {
+			t.Fatalf("expected key to be acquired, got %+v", owned)
+		}
+
+		record, acquired, err := store.Lock(ctx, "key", "other", time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if acquired || record.Fingerprint != "fingerprint" || record.Response != nil {
+			t.Fatalf("expected in flight record, got %t %+v", acquired, record)
+		}
+
+		resp := internal.IdempotentResponse{
+			StatusCode: http.StatusCreated,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       []byte(`{}`),
+		}
+
+		if err := store.Save(ctx, "key", owned.Owner, resp, time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		record, _, err = store.Lock(ctx, "key", "fingerprint", time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if !cmp.Equal(&resp, record.Response) {
+			t.Fatalf("expected results don't match: %s", cmp.Diff(&resp, record.Response))
+		}
+	})
+
+	t.Run("Lock: OK expired", func(t *testing.T) {
+		t.Parallel()
+
+		store := postgresql.NewIdempotency(newDB(t))
+		ctx := context.Background()
+
+		if _, _, err := store.Lock(ctx, "key", "fingerprint", time.Now().Add(-time.Minute)); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		_, acquired, err := store.Lock(ctx, "key", "other", time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if !acquired {
+			t.Fatalf("expected expired key to be acquired")
+		}
+	})
+
+	t.Run("Lock: OK released", func(t *testing.T) {
+		t.Parallel()
+
+		store := postgresql.NewIdempotency(newDB(t))
+		ctx := context.Background()
+
+		record, _, err := store.Lock(ctx, "key", "fingerprint", time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if err := store.Release(ctx, "key", record.Owner); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		_, acquired, err := store.Lock(ctx, "key", "fingerprint", time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if !acquired {
+			t.Fatalf("expected released key to be acquired")
+		}
+	})
+
+	t.Run("Save: ERR reclaimed", func(t *testing.T) {
+		t.Parallel()
+
+		store := postgresql.NewIdempotency(newDB(t))
+		ctx := context.Background()
+
+		expired, _, err := store.Lock(ctx, "key", "fingerprint", time.Now().Add(-time.Minute))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		current, acquired, err := store.Lock(ctx, "key", "fingerprint", time.Now().Add(time.Minute))
+		if err != nil || !acquired {
+			t.Fatalf("expected expired key to be acquired, got %t %v", acquired, err)
+		}
+
+		// The request that lost the key can't overwrite the result of the one holding it now.
+
+		resp := internal.IdempotentResponse{StatusCode: http.StatusCreated}
+
+		err = store.Save(ctx, "key", expired.Owner, resp, time.Now().Add(time.Hour))
+		if internal.Code(err) != internal.ErrCodeConflict {
+			t.Fatalf("expected conflict error, got %v", err)
+		}
+
+		if err := store.Release(ctx, "key", expired.Owner); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if err := store.Save(ctx, "key", current.Owner, resp, time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"io/fs"
+
+	migrate "github.com/golang-migrate/migrate/v4"
+	migratepostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	// Initialize "pgx".
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// migrationsLockID is the key of the advisory lock held while migrating.
+//
+// XXX: Arbitrary, it must be different to the one used by golang-migrate itself, which is derived from the
+// database name.
+const migrationsLockID = 2021103101
+
+// MigrateOnStartFlag defines the "migrate-on-start" flag, servers supporting it call Migrator.Up before
+// receiving traffic when it's set.
+func MigrateOnStartFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("migrate-on-start", false, "Apply the pending database migrations before starting")
+}
+
+// Migrator applies the migrations to the database. Every operation holds a PostgreSQL advisory lock, so
+// multiple replicas starting at the same time apply the migrations only once: the rest wait for the lock and
+// then find nothing pending.
+type Migrator struct {
+	dsn        string
+	migrations fs.FS
+	logger     *zap.Logger
+}
+
+// NewMigrator instantiates the Migrator, "migrations" contains the SQL files, like migrations.FS does.
+func NewMigrator(logger *zap.Logger, dsn string, migrations fs.FS) *Migrator {
+	return &Migrator{
+		dsn:        dsn,
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+// Up applies all the pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, "Up", func(mg *migrate.Migrate) error {
+		return mg.Up()
+	})
+}
+
+// Down reverts the last "steps" migrations, zero reverts all of them.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.run(ctx, "Down", func(mg *migrate.Migrate) error {
+		if steps == 0 {
+			return mg.Down()
+		}
+
+		return mg.Steps(-steps)
+	})
+}
+
+// Goto migrates up or down to the indicated version.
+func (m *Migrator) Goto(ctx context.Context, version uint) error {
+	return m.run(ctx, "Goto", func(mg *migrate.Migrate) error {
+		return mg.Migrate(version)
+	})
+}
+
+// Force sets the version without running any migration and clears the dirty flag, it is meant for recovering
+// from a migration that failed halfway after fixing the database manually. -1 means no migration.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	return m.run(ctx, "Force", func(mg *migrate.Migrate) error {
+		return mg.Force(version)
+	})
+}
+
+// Version returns the current version of the database and whether the last migration failed halfway, zero
+// means no migration was applied.
+func (m *Migrator) Version(ctx context.Context) (uint, bool, error) {
+	var (
+		version uint
+		dirty   bool
+	)
+
+	err := m.run(ctx, "Version", func(mg *migrate.Migrate) (err error) {
+		version, dirty, err = mg.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return nil
+		}
+
+		return err
+	})
+
+	return version, dirty, err
+}
+
+// Latest returns the version of the last migration available.
+func (m *Migrator) Latest() (uint, error) {
+	src, err := iofs.New(m.migrations, ".")
+	if err != nil {
+		return 0, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "iofs.New")
+	}
+
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "src.First")
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+
+		if err != nil {
+			return 0, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "src.Next")
+		}
+
+		version = next
+	}
+}
+
+// run calls fn while holding the advisory lock.
+func (m *Migrator) run(ctx context.Context, op string, fn func(*migrate.Migrate) error) error {
+	db, err := sql.Open("pgx", m.dsn)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "sql.Open")
+	}
+
+	defer db.Close()
+
+	// XXX: The lock is held using its own connection, golang-migrate closes the database when done.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeUnavailable, "db.Conn")
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID); err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeUnavailable, "pg_advisory_lock")
+	}
+
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationsLockID); err != nil {
+			m.logger.Warn("releasing migrations lock", zap.Error(err))
+		}
+	}()
+
+	src, err := iofs.New(m.migrations, ".")
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "iofs.New")
+	}
+
+	driver, err := migratepostgres.WithInstance(db, &migratepostgres.Config{})
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeUnavailable, "postgres.WithInstance")
+	}
+
+	mg, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "migrate.NewWithInstance")
+	}
+
+	defer mg.Close()
+
+	mg.Log = migrateLogger{m.logger}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			mg.GracefulStop <- true
+		case <-stop:
+		}
+	}()
+
+	if err := fn(mg); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		var derr migrate.ErrDirty
+		if errors.As(err, &derr) {
+			return internal.WrapErrorf(err, internal.ErrCodeFailedPrecondition, "migrate.%s", op)
+		}
+
+		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "migrate.%s", op)
+	}
+
+	return nil
+}
+
+// migrateLogger implements migrate.Logger.
+type migrateLogger struct {
+	logger *zap.Logger
+}
+
+func (l migrateLogger) Printf(format string, v ...interface{}) {
+	l.logger.Sugar().Infof(format, v...)
+}
+
+func (l migrateLogger) Verbose() bool {
+	return false
+}
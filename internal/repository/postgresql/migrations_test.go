@@ -0,0 +1,113 @@
+package postgresql_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/db/migrations"
+	"github.com/lrweck/todo/internal/repository/postgresql"
+)
+
+func TestMigrator_Latest(t *testing.T) {
+	t.Parallel()
+
+	migrator := postgresql.NewMigrator(zap.NewNop(), "", fstest.MapFS{
+		"1_create.up.sql":   &fstest.MapFile{Data: []byte("CREATE TABLE one(id INT);")},
+		"1_create.down.sql": &fstest.MapFile{Data: []byte("DROP TABLE one;")},
+		"3_alter.up.sql":    &fstest.MapFile{Data: []byte("ALTER TABLE one ADD COLUMN name TEXT;")},
+		"2_create.up.sql":   &fstest.MapFile{Data: []byte("CREATE TABLE two(id INT);")},
+	})
+
+	latest, err := migrator.Latest()
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if latest != 3 {
+		t.Fatalf("expected 3, got %d", latest)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	t.Parallel()
+
+	dsn := newDSN(t)
+	ctx := context.Background()
+
+	migrator := postgresql.NewMigrator(zap.NewNop(), dsn, migrations.FS)
+
+	latest, err := migrator.Latest()
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	assertVersion := func(expected uint) {
+		t.Helper()
+
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if version != expected || dirty {
+			t.Fatalf("expected version %d not dirty, got %d (dirty %t)", expected, version, dirty)
+		}
+	}
+
+	assertVersion(0)
+
+	// Replicas starting at the same time.
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 3)
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs <- postgresql.NewMigrator(zap.NewNop(), dsn, migrations.FS).Up(ctx)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+	}
+
+	assertVersion(latest)
+
+	if err := migrator.Down(ctx, 1); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	version, _, err := migrator.Version(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if version >= latest {
+		t.Fatalf("expected version before %d, got %d", latest, version)
+	}
+
+	if err := migrator.Goto(ctx, latest); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	assertVersion(latest)
+
+	if err := migrator.Force(ctx, int(version)); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	assertVersion(version)
+}
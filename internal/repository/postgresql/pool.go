@@ -0,0 +1,332 @@
+package postgresql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+)
+
+const (
+	rotationRetry = 5 * time.Second
+	maxCloseGrace = 30 * time.Second
+)
+
+//go:generate counterfeiter -generate
+
+//counterfeiter:generate -o postgresqltesting/credentials_source.gen.go . CredentialsSource
+
+// CredentialsSource creates and renews the short-lived credentials used by RotatingPool, like the Vault
+// database secrets engine.
+type CredentialsSource interface {
+	Fetch() (internal.Credentials, error)
+	Renew(creds internal.Credentials, increment time.Duration) (internal.Credentials, error)
+	Revoke(creds internal.Credentials) error
+}
+
+// RotatingPool is a connection pool using short-lived credentials. The lease is renewed in the background and
+// once renewing does not extend it anymore, because it reached its maximum TTL, a new pool using new
+// credentials replaces the current one. Queries already running in the previous pool are not interrupted.
+type RotatingPool struct {
+	config *pgxpool.Config
+	source CredentialsSource
+	logger *zap.Logger
+
+	rotations metric.Int64Counter
+	failures  metric.Int64Counter
+
+	wg      sync.WaitGroup
+	closing chan struct{}
+
+	mu      sync.RWMutex
+	closed  bool
+	pool    *pgxpool.Pool
+	creds   internal.Credentials
+	ttl     time.Duration
+	issued  time.Time
+	expires time.Time
+}
+
+// NewRotatingPool fetches credentials and connects to the database, "config" defines everything but the user
+// and password.
+func NewRotatingPool(ctx context.Context, logger *zap.Logger, config *pgxpool.Config, source CredentialsSource) (*RotatingPool, error) {
+	meter := metric.Must(global.Meter("postgresql"))
+
+	p := &RotatingPool{
+		config:  config,
+		source:  source,
+		logger:  logger,
+		closing: make(chan struct{}),
+		rotations: meter.NewInt64Counter("postgresql.credentials.rotations",
+			metric.WithDescription("Connection pools replaced because their credentials were expiring")),
+		failures: meter.NewInt64Counter("postgresql.credentials.failures",
+			metric.WithDescription("Failed attempts to renew or rotate the credentials")),
+	}
+
+	meter.NewFloat64GaugeObserver("postgresql.credentials.lease_age",
+		func(ctx context.Context, res metric.Float64ObserverResult) {
+			p.mu.RLock()
+			defer p.mu.RUnlock()
+
+			res.Observe(time.Since(p.issued).Seconds())
+		},
+		metric.WithDescription("Time since the credentials in use were created"),
+		metric.WithUnit("s"))
+
+	meter.NewFloat64GaugeObserver("postgresql.credentials.lease_remaining",
+		func(ctx context.Context, res metric.Float64ObserverResult) {
+			p.mu.RLock()
+			defer p.mu.RUnlock()
+
+			if !p.expires.IsZero() {
+				res.Observe(time.Until(p.expires).Seconds())
+			}
+		},
+		metric.WithDescription("Time until the lease of the credentials in use expires"),
+		metric.WithUnit("s"))
+
+	pool, creds, err := p.connect(ctx)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.Code(err), "connect")
+	}
+
+	p.swap(pool, creds)
+
+	return p, nil
+}
+
+// Exec ...
+func (p *RotatingPool) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return p.current().Exec(ctx, sql, args...)
+}
+
+// Query ...
+func (p *RotatingPool) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return p.current().Query(ctx, sql, args...)
+}
+
+// QueryRow ...
+func (p *RotatingPool) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return p.current().QueryRow(ctx, sql, args...)
+}
+
+// Begin ...
+func (p *RotatingPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.current().Begin(ctx)
+}
+
+// Ping ...
+func (p *RotatingPool) Ping(ctx context.Context) error {
+	return p.current().Ping(ctx)
+}
+
+// Close stops Run, closes the current pool and revokes its credentials. It waits for the pools replaced by
+// previous rotations to be closed and revoked as well.
+func (p *RotatingPool) Close() {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+
+		return
+	}
+
+	p.closed = true
+	close(p.closing)
+
+	p.mu.Unlock()
+
+	p.wg.Wait()
+
+	p.mu.RLock()
+	pool, creds := p.pool, p.creds
+	p.mu.RUnlock()
+
+	pool.Close()
+
+	if err := p.source.Revoke(creds); err != nil {
+		p.logger.Warn("revoking credentials", zap.Error(err))
+	}
+}
+
+// Run renews the lease after two thirds of its duration and rotates the credentials when that is not enough,
+// it blocks until the context is canceled or the pool is closed.
+func (p *RotatingPool) Run(ctx context.Context) {
+	if !p.track() {
+		return
+	}
+
+	defer p.wg.Done()
+
+	for {
+		p.mu.RLock()
+		creds := p.creds
+		p.mu.RUnlock()
+
+		if creds.LeaseDuration == 0 {
+			return // XXX: Credentials never expire.
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.closing:
+			return
+		case <-time.After(creds.LeaseDuration * 2 / 3):
+		}
+
+		if p.renew(creds) {
+			continue
+		}
+
+		for err := p.rotate(ctx); err != nil; err = p.rotate(ctx) {
+			p.failures.Add(ctx, 1)
+			p.logger.Error("rotating credentials", zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.closing:
+				return
+			case <-time.After(rotationRetry):
+			}
+		}
+
+		p.rotations.Add(ctx, 1)
+	}
+}
+
+// track adds a goroutine to the ones Close waits for, false is returned when the pool is closed already.
+func (p *RotatingPool) track() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return false
+	}
+
+	p.wg.Add(1)
+
+	return true
+}
+
+// renew extends the lease, false is returned when the credentials must be rotated because the lease can't be
+// renewed or because renewing is not extending it enough. The original TTL is requested every time, the
+// duration of the last renewal may be shorter already.
+func (p *RotatingPool) renew(creds internal.Credentials) bool {
+	if !creds.Renewable {
+		return false
+	}
+
+	p.mu.RLock()
+	ttl := p.ttl
+	p.mu.RUnlock()
+
+	renewed, err := p.source.Renew(creds, ttl)
+	if err != nil {
+		p.failures.Add(context.Background(), 1)
+		p.logger.Warn("renewing credentials", zap.Error(err))
+
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.creds = renewed
+	p.expires = time.Now().Add(renewed.LeaseDuration)
+
+	return renewed.LeaseDuration >= p.ttl/3
+}
+
+// rotate connects using new credentials, the previous pool is closed once the in-flight queries complete.
+func (p *RotatingPool) rotate(ctx context.Context) error {
+	pool, creds, err := p.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	prevPool, prevCreds, expires := p.swap(pool, creds)
+
+	// XXX: Called by Run, which is tracked already; Close can't be waiting yet.
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		// XXX: Callers that got the previous pool right before swapping must still be able to acquire
+		// connections, closing waits for the acquired ones to be released.
+		grace := time.Until(expires) / 2
+		if grace > maxCloseGrace {
+			grace = maxCloseGrace
+		}
+
+		select {
+		case <-time.After(grace):
+		case <-p.closing:
+		}
+
+		prevPool.Close()
+
+		if err := p.source.Revoke(prevCreds); err != nil {
+			p.logger.Warn("revoking credentials", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+func (p *RotatingPool) connect(ctx context.Context) (*pgxpool.Pool, internal.Credentials, error) {
+	creds, err := p.source.Fetch()
+	if err != nil {
+		return nil, internal.Credentials{}, internal.WrapErrorf(err, internal.Code(err), "source.Fetch")
+	}
+
+	config := p.config.Copy()
+	config.ConnConfig.User = creds.Username
+	config.ConnConfig.Password = creds.Password
+
+	pool, err := pgxpool.ConnectConfig(ctx, config)
+	if err != nil {
+		_ = p.source.Revoke(creds)
+
+		return nil, internal.Credentials{}, internal.WrapErrorf(err, internal.ErrCodeUnavailable, "pgxpool.ConnectConfig")
+	}
+
+	return pool, creds, nil
+}
+
+// swap replaces the pool, returning the previous one together with its credentials and when they expire.
+func (p *RotatingPool) swap(pool *pgxpool.Pool, creds internal.Credentials) (*pgxpool.Pool, internal.Credentials, time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prevPool, prevCreds, prevExpires := p.pool, p.creds, p.expires
+
+	p.pool = pool
+	p.creds = creds
+	p.ttl = creds.LeaseDuration
+	p.issued = time.Now()
+	p.expires = time.Time{}
+
+	if creds.LeaseDuration > 0 {
+		p.expires = p.issued.Add(creds.LeaseDuration)
+	}
+
+	return prevPool, prevCreds, prevExpires
+}
+
+func (p *RotatingPool) current() *pgxpool.Pool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.pool
+}
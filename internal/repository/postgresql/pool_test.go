@@ -0,0 +1,197 @@
+package postgresql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/postgresql"
+	"github.com/lrweck/todo/internal/repository/postgresql/postgresqltesting"
+)
+
+func TestRotatingPool(t *testing.T) {
+	t.Parallel()
+
+	admin := newDB(t)
+
+	for _, user := range []string{"rotated1", "rotated2"} {
+		if _, err := admin.Exec(context.Background(), "CREATE ROLE "+user+" LOGIN PASSWORD 'password'"); err != nil {
+			t.Fatalf("couldn't create role: %s", err)
+		}
+
+		if _, err := admin.Exec(context.Background(), "GRANT ALL ON ALL TABLES IN SCHEMA public TO "+user); err != nil {
+			t.Fatalf("couldn't grant: %s", err)
+		}
+	}
+
+	source := &postgresqltesting.FakeCredentialsSource{}
+	source.FetchReturnsOnCall(0, internal.Credentials{
+		Username:      "rotated1",
+		Password:      "password",
+		LeaseID:       "1",
+		LeaseDuration: 300 * time.Millisecond,
+	}, nil)
+	source.FetchReturnsOnCall(1, internal.Credentials{
+		Username:      "rotated2",
+		Password:      "password",
+		LeaseID:       "2",
+		LeaseDuration: time.Hour,
+	}, nil)
+
+	pool, err := postgresql.NewRotatingPool(context.Background(), zap.NewNop(), admin.Config(), source)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	t.Cleanup(pool.Close)
+
+	currentUser := func() string {
+		var user string
+
+		if err := pool.QueryRow(context.Background(), "SELECT current_user").Scan(&user); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		return user
+	}
+
+	if user := currentUser(); user != "rotated1" {
+		t.Fatalf("expected rotated1, got %s", user)
+	}
+
+	// In-flight query using the first credentials, it must complete after rotating them.
+	rows, err := pool.Query(context.Background(), "SELECT pg_sleep(0.5)")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go pool.Run(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for currentUser() != "rotated2" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected credentials to be rotated")
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	for rows.Next() { //nolint:revive // Draining the results.
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatalf("expected in-flight query to complete, got %s", err)
+	}
+
+	rows.Close()
+
+	for source.RevokeCallCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected previous credentials to be revoked")
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if creds := source.RevokeArgsForCall(0); creds.LeaseID != "1" {
+		t.Fatalf("expected lease 1 to be revoked, got %s", creds.LeaseID)
+	}
+}
+
+func TestRotatingPool_Renew(t *testing.T) {
+	t.Parallel()
+
+	admin := newDB(t)
+
+	if _, err := admin.Exec(context.Background(), "CREATE ROLE renewed LOGIN PASSWORD 'password'"); err != nil {
+		t.Fatalf("couldn't create role: %s", err)
+	}
+
+	creds := internal.Credentials{
+		Username:      "renewed",
+		Password:      "password",
+		LeaseID:       "1",
+		LeaseDuration: 300 * time.Millisecond,
+		Renewable:     true,
+	}
+
+	source := &postgresqltesting.FakeCredentialsSource{}
+	source.FetchReturns(creds, nil)
+	source.RenewStub = func(creds internal.Credentials, _ time.Duration) (internal.Credentials, error) {
+		// Shorter than requested, as when the lease gets closer to its maximum TTL.
+		creds.LeaseDuration = 150 * time.Millisecond
+
+		return creds, nil
+	}
+
+	pool, err := postgresql.NewRotatingPool(context.Background(), zap.NewNop(), admin.Config(), source)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		pool.Run(context.Background())
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for source.RenewCallCount() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected lease to be renewed twice")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, increment := source.RenewArgsForCall(i); increment != 300*time.Millisecond {
+			t.Fatalf("expected original TTL to be requested, got %s", increment)
+		}
+	}
+
+	pool.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected Run to stop after closing")
+	}
+
+	if source.FetchCallCount() != 1 {
+		t.Fatalf("expected credentials not to be rotated, got %d fetches", source.FetchCallCount())
+	}
+}
+
+func TestNewRotatingPool_Error(t *testing.T) {
+	t.Parallel()
+
+	source := &postgresqltesting.FakeCredentialsSource{}
+	source.FetchReturns(internal.Credentials{}, internal.NewErrorf(internal.ErrCodeUnavailable, "sealed"))
+
+	config, err := pgxpool.ParseConfig("postgres://localhost/todo")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	_, err = postgresql.NewRotatingPool(context.Background(), zap.NewNop(), config, source)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+
+	var ierr *internal.Error
+	if !errors.As(err, &ierr) || ierr.Code() != internal.ErrCodeUnavailable {
+		t.Fatalf("expected unavailable error, got %v", err)
+	}
+}
@@ -0,0 +1,437 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package postgresqltesting
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jackc/pgconn"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/lrweck/todo/internal/repository/postgresql"
+)
+
+type FakeConn struct {
+	BeginStub        func(context.Context) (pgx.Tx, error)
+	beginMutex       sync.RWMutex
+	beginArgsForCall []struct {
+		arg1 context.Context
+	}
+	beginReturns struct {
+		result1 pgx.Tx
+		result2 error
+	}
+	beginReturnsOnCall map[int]struct {
+		result1 pgx.Tx
+		result2 error
+	}
+	ExecStub        func(context.Context, string, ...interface{}) (pgconn.CommandTag, error)
+	execMutex       sync.RWMutex
+	execArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []interface{}
+	}
+	execReturns struct {
+		result1 pgconn.CommandTag
+		result2 error
+	}
+	execReturnsOnCall map[int]struct {
+		result1 pgconn.CommandTag
+		result2 error
+	}
+	PingStub        func(context.Context) error
+	pingMutex       sync.RWMutex
+	pingArgsForCall []struct {
+		arg1 context.Context
+	}
+	pingReturns struct {
+		result1 error
+	}
+	pingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	QueryStub        func(context.Context, string, ...interface{}) (pgx.Rows, error)
+	queryMutex       sync.RWMutex
+	queryArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []interface{}
+	}
+	queryReturns struct {
+		result1 pgx.Rows
+		result2 error
+	}
+	queryReturnsOnCall map[int]struct {
+		result1 pgx.Rows
+		result2 error
+	}
+	QueryRowStub        func(context.Context, string, ...interface{}) pgx.Row
+	queryRowMutex       sync.RWMutex
+	queryRowArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []interface{}
+	}
+	queryRowReturns struct {
+		result1 pgx.Row
+	}
+	queryRowReturnsOnCall map[int]struct {
+		result1 pgx.Row
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeConn) Begin(arg1 context.Context) (pgx.Tx, error) {
+	fake.beginMutex.Lock()
+	ret, specificReturn := fake.beginReturnsOnCall[len(fake.beginArgsForCall)]
+	fake.beginArgsForCall = append(fake.beginArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.BeginStub
+	fakeReturns := fake.beginReturns
+	fake.recordInvocation("Begin", []interface{}{arg1})
+	fake.beginMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeConn) BeginCallCount() int {
+	fake.beginMutex.RLock()
+	defer fake.beginMutex.RUnlock()
+	return len(fake.beginArgsForCall)
+}
+
+func (fake *FakeConn) BeginCalls(stub func(context.Context) (pgx.Tx, error)) {
+	fake.beginMutex.Lock()
+	defer fake.beginMutex.Unlock()
+	fake.BeginStub = stub
+}
+
+func (fake *FakeConn) BeginArgsForCall(i int) context.Context {
+	fake.beginMutex.RLock()
+	defer fake.beginMutex.RUnlock()
+	argsForCall := fake.beginArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConn) BeginReturns(result1 pgx.Tx, result2 error) {
+	fake.beginMutex.Lock()
+	defer fake.beginMutex.Unlock()
+	fake.BeginStub = nil
+	fake.beginReturns = struct {
+		result1 pgx.Tx
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConn) BeginReturnsOnCall(i int, result1 pgx.Tx, result2 error) {
+	fake.beginMutex.Lock()
+	defer fake.beginMutex.Unlock()
+	fake.BeginStub = nil
+	if fake.beginReturnsOnCall == nil {
+		fake.beginReturnsOnCall = make(map[int]struct {
+			result1 pgx.Tx
+			result2 error
+		})
+	}
+	fake.beginReturnsOnCall[i] = struct {
+		result1 pgx.Tx
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConn) Exec(arg1 context.Context, arg2 string, arg3 ...interface{}) (pgconn.CommandTag, error) {
+	fake.execMutex.Lock()
+	ret, specificReturn := fake.execReturnsOnCall[len(fake.execArgsForCall)]
+	fake.execArgsForCall = append(fake.execArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []interface{}
+	}{arg1, arg2, arg3})
+	stub := fake.ExecStub
+	fakeReturns := fake.execReturns
+	fake.recordInvocation("Exec", []interface{}{arg1, arg2, arg3})
+	fake.execMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeConn) ExecCallCount() int {
+	fake.execMutex.RLock()
+	defer fake.execMutex.RUnlock()
+	return len(fake.execArgsForCall)
+}
+
+func (fake *FakeConn) ExecCalls(stub func(context.Context, string, ...interface{}) (pgconn.CommandTag, error)) {
+	fake.execMutex.Lock()
+	defer fake.execMutex.Unlock()
+	fake.ExecStub = stub
+}
+
+func (fake *FakeConn) ExecArgsForCall(i int) (context.Context, string, []interface{}) {
+	fake.execMutex.RLock()
+	defer fake.execMutex.RUnlock()
+	argsForCall := fake.execArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeConn) ExecReturns(result1 pgconn.CommandTag, result2 error) {
+	fake.execMutex.Lock()
+	defer fake.execMutex.Unlock()
+	fake.ExecStub = nil
+	fake.execReturns = struct {
+		result1 pgconn.CommandTag
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConn) ExecReturnsOnCall(i int, result1 pgconn.CommandTag, result2 error) {
+	fake.execMutex.Lock()
+	defer fake.execMutex.Unlock()
+	fake.ExecStub = nil
+	if fake.execReturnsOnCall == nil {
+		fake.execReturnsOnCall = make(map[int]struct {
+			result1 pgconn.CommandTag
+			result2 error
+		})
+	}
+	fake.execReturnsOnCall[i] = struct {
+		result1 pgconn.CommandTag
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConn) Ping(arg1 context.Context) error {
+	fake.pingMutex.Lock()
+	ret, specificReturn := fake.pingReturnsOnCall[len(fake.pingArgsForCall)]
+	fake.pingArgsForCall = append(fake.pingArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.PingStub
+	fakeReturns := fake.pingReturns
+	fake.recordInvocation("Ping", []interface{}{arg1})
+	fake.pingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConn) PingCallCount() int {
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	return len(fake.pingArgsForCall)
+}
+
+func (fake *FakeConn) PingCalls(stub func(context.Context) error) {
+	fake.pingMutex.Lock()
+	defer fake.pingMutex.Unlock()
+	fake.PingStub = stub
+}
+
+func (fake *FakeConn) PingArgsForCall(i int) context.Context {
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	argsForCall := fake.pingArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConn) PingReturns(result1 error) {
+	fake.pingMutex.Lock()
+	defer fake.pingMutex.Unlock()
+	fake.PingStub = nil
+	fake.pingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConn) PingReturnsOnCall(i int, result1 error) {
+	fake.pingMutex.Lock()
+	defer fake.pingMutex.Unlock()
+	fake.PingStub = nil
+	if fake.pingReturnsOnCall == nil {
+		fake.pingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConn) Query(arg1 context.Context, arg2 string, arg3 ...interface{}) (pgx.Rows, error) {
+	fake.queryMutex.Lock()
+	ret, specificReturn := fake.queryReturnsOnCall[len(fake.queryArgsForCall)]
+	fake.queryArgsForCall = append(fake.queryArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []interface{}
+	}{arg1, arg2, arg3})
+	stub := fake.QueryStub
+	fakeReturns := fake.queryReturns
+	fake.recordInvocation("Query", []interface{}{arg1, arg2, arg3})
+	fake.queryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeConn) QueryCallCount() int {
+	fake.queryMutex.RLock()
+	defer fake.queryMutex.RUnlock()
+	return len(fake.queryArgsForCall)
+}
+
+func (fake *FakeConn) QueryCalls(stub func(context.Context, string, ...interface{}) (pgx.Rows, error)) {
+	fake.queryMutex.Lock()
+	defer fake.queryMutex.Unlock()
+	fake.QueryStub = stub
+}
+
+func (fake *FakeConn) QueryArgsForCall(i int) (context.Context, string, []interface{}) {
+	fake.queryMutex.RLock()
+	defer fake.queryMutex.RUnlock()
+	argsForCall := fake.queryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeConn) QueryReturns(result1 pgx.Rows, result2 error) {
+	fake.queryMutex.Lock()
+	defer fake.queryMutex.Unlock()
+	fake.QueryStub = nil
+	fake.queryReturns = struct {
+		result1 pgx.Rows
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConn) QueryReturnsOnCall(i int, result1 pgx.Rows, result2 error) {
+	fake.queryMutex.Lock()
+	defer fake.queryMutex.Unlock()
+	fake.QueryStub = nil
+	if fake.queryReturnsOnCall == nil {
+		fake.queryReturnsOnCall = make(map[int]struct {
+			result1 pgx.Rows
+			result2 error
+		})
+	}
+	fake.queryReturnsOnCall[i] = struct {
+		result1 pgx.Rows
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConn) QueryRow(arg1 context.Context, arg2 string, arg3 ...interface{}) pgx.Row {
+	fake.queryRowMutex.Lock()
+	ret, specificReturn := fake.queryRowReturnsOnCall[len(fake.queryRowArgsForCall)]
+	fake.queryRowArgsForCall = append(fake.queryRowArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []interface{}
+	}{arg1, arg2, arg3})
+	stub := fake.QueryRowStub
+	fakeReturns := fake.queryRowReturns
+	fake.recordInvocation("QueryRow", []interface{}{arg1, arg2, arg3})
+	fake.queryRowMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConn) QueryRowCallCount() int {
+	fake.queryRowMutex.RLock()
+	defer fake.queryRowMutex.RUnlock()
+	return len(fake.queryRowArgsForCall)
+}
+
+func (fake *FakeConn) QueryRowCalls(stub func(context.Context, string, ...interface{}) pgx.Row) {
+	fake.queryRowMutex.Lock()
+	defer fake.queryRowMutex.Unlock()
+	fake.QueryRowStub = stub
+}
+
+func (fake *FakeConn) QueryRowArgsForCall(i int) (context.Context, string, []interface{}) {
+	fake.queryRowMutex.RLock()
+	defer fake.queryRowMutex.RUnlock()
+	argsForCall := fake.queryRowArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeConn) QueryRowReturns(result1 pgx.Row) {
+	fake.queryRowMutex.Lock()
+	defer fake.queryRowMutex.Unlock()
+	fake.QueryRowStub = nil
+	fake.queryRowReturns = struct {
+		result1 pgx.Row
+	}{result1}
+}
+
+func (fake *FakeConn) QueryRowReturnsOnCall(i int, result1 pgx.Row) {
+	fake.queryRowMutex.Lock()
+	defer fake.queryRowMutex.Unlock()
+	fake.QueryRowStub = nil
+	if fake.queryRowReturnsOnCall == nil {
+		fake.queryRowReturnsOnCall = make(map[int]struct {
+			result1 pgx.Row
+		})
+	}
+	fake.queryRowReturnsOnCall[i] = struct {
+		result1 pgx.Row
+	}{result1}
+}
+
+func (fake *FakeConn) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.beginMutex.RLock()
+	defer fake.beginMutex.RUnlock()
+	fake.execMutex.RLock()
+	defer fake.execMutex.RUnlock()
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	fake.queryMutex.RLock()
+	defer fake.queryMutex.RUnlock()
+	fake.queryRowMutex.RLock()
+	defer fake.queryRowMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeConn) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ postgresql.Conn = new(FakeConn)
@@ -0,0 +1,264 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package postgresqltesting
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/postgresql"
+)
+
+type FakeCredentialsSource struct {
+	FetchStub        func() (internal.Credentials, error)
+	fetchMutex       sync.RWMutex
+	fetchArgsForCall []struct {
+	}
+	fetchReturns struct {
+		result1 internal.Credentials
+		result2 error
+	}
+	fetchReturnsOnCall map[int]struct {
+		result1 internal.Credentials
+		result2 error
+	}
+	RenewStub        func(internal.Credentials, time.Duration) (internal.Credentials, error)
+	renewMutex       sync.RWMutex
+	renewArgsForCall []struct {
+		arg1 internal.Credentials
+		arg2 time.Duration
+	}
+	renewReturns struct {
+		result1 internal.Credentials
+		result2 error
+	}
+	renewReturnsOnCall map[int]struct {
+		result1 internal.Credentials
+		result2 error
+	}
+	RevokeStub        func(internal.Credentials) error
+	revokeMutex       sync.RWMutex
+	revokeArgsForCall []struct {
+		arg1 internal.Credentials
+	}
+	revokeReturns struct {
+		result1 error
+	}
+	revokeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCredentialsSource) Fetch() (internal.Credentials, error) {
+	fake.fetchMutex.Lock()
+	ret, specificReturn := fake.fetchReturnsOnCall[len(fake.fetchArgsForCall)]
+	fake.fetchArgsForCall = append(fake.fetchArgsForCall, struct {
+	}{})
+	stub := fake.FetchStub
+	fakeReturns := fake.fetchReturns
+	fake.recordInvocation("Fetch", []interface{}{})
+	fake.fetchMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredentialsSource) FetchCallCount() int {
+	fake.fetchMutex.RLock()
+	defer fake.fetchMutex.RUnlock()
+	return len(fake.fetchArgsForCall)
+}
+
+func (fake *FakeCredentialsSource) FetchCalls(stub func() (internal.Credentials, error)) {
+	fake.fetchMutex.Lock()
+	defer fake.fetchMutex.Unlock()
+	fake.FetchStub = stub
+}
+
+func (fake *FakeCredentialsSource) FetchReturns(result1 internal.Credentials, result2 error) {
+	fake.fetchMutex.Lock()
+	defer fake.fetchMutex.Unlock()
+	fake.FetchStub = nil
+	fake.fetchReturns = struct {
+		result1 internal.Credentials
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredentialsSource) FetchReturnsOnCall(i int, result1 internal.Credentials, result2 error) {
+	fake.fetchMutex.Lock()
+	defer fake.fetchMutex.Unlock()
+	fake.FetchStub = nil
+	if fake.fetchReturnsOnCall == nil {
+		fake.fetchReturnsOnCall = make(map[int]struct {
+			result1 internal.Credentials
+			result2 error
+		})
+	}
+	fake.fetchReturnsOnCall[i] = struct {
+		result1 internal.Credentials
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredentialsSource) Renew(arg1 internal.Credentials, arg2 time.Duration) (internal.Credentials, error) {
+	fake.renewMutex.Lock()
+	ret, specificReturn := fake.renewReturnsOnCall[len(fake.renewArgsForCall)]
+	fake.renewArgsForCall = append(fake.renewArgsForCall, struct {
+		arg1 internal.Credentials
+		arg2 time.Duration
+	}{arg1, arg2})
+	stub := fake.RenewStub
+	fakeReturns := fake.renewReturns
+	fake.recordInvocation("Renew", []interface{}{arg1, arg2})
+	fake.renewMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredentialsSource) RenewCallCount() int {
+	fake.renewMutex.RLock()
+	defer fake.renewMutex.RUnlock()
+	return len(fake.renewArgsForCall)
+}
+
+func (fake *FakeCredentialsSource) RenewCalls(stub func(internal.Credentials, time.Duration) (internal.Credentials, error)) {
+	fake.renewMutex.Lock()
+	defer fake.renewMutex.Unlock()
+	fake.RenewStub = stub
+}
+
+func (fake *FakeCredentialsSource) RenewArgsForCall(i int) (internal.Credentials, time.Duration) {
+	fake.renewMutex.RLock()
+	defer fake.renewMutex.RUnlock()
+	argsForCall := fake.renewArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredentialsSource) RenewReturns(result1 internal.Credentials, result2 error) {
+	fake.renewMutex.Lock()
+	defer fake.renewMutex.Unlock()
+	fake.RenewStub = nil
+	fake.renewReturns = struct {
+		result1 internal.Credentials
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredentialsSource) RenewReturnsOnCall(i int, result1 internal.Credentials, result2 error) {
+	fake.renewMutex.Lock()
+	defer fake.renewMutex.Unlock()
+	fake.RenewStub = nil
+	if fake.renewReturnsOnCall == nil {
+		fake.renewReturnsOnCall = make(map[int]struct {
+			result1 internal.Credentials
+			result2 error
+		})
+	}
+	fake.renewReturnsOnCall[i] = struct {
+		result1 internal.Credentials
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredentialsSource) Revoke(arg1 internal.Credentials) error {
+	fake.revokeMutex.Lock()
+	ret, specificReturn := fake.revokeReturnsOnCall[len(fake.revokeArgsForCall)]
+	fake.revokeArgsForCall = append(fake.revokeArgsForCall, struct {
+		arg1 internal.Credentials
+	}{arg1})
+	stub := fake.RevokeStub
+	fakeReturns := fake.revokeReturns
+	fake.recordInvocation("Revoke", []interface{}{arg1})
+	fake.revokeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCredentialsSource) RevokeCallCount() int {
+	fake.revokeMutex.RLock()
+	defer fake.revokeMutex.RUnlock()
+	return len(fake.revokeArgsForCall)
+}
+
+func (fake *FakeCredentialsSource) RevokeCalls(stub func(internal.Credentials) error) {
+	fake.revokeMutex.Lock()
+	defer fake.revokeMutex.Unlock()
+	fake.RevokeStub = stub
+}
+
+func (fake *FakeCredentialsSource) RevokeArgsForCall(i int) internal.Credentials {
+	fake.revokeMutex.RLock()
+	defer fake.revokeMutex.RUnlock()
+	argsForCall := fake.revokeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCredentialsSource) RevokeReturns(result1 error) {
+	fake.revokeMutex.Lock()
+	defer fake.revokeMutex.Unlock()
+	fake.RevokeStub = nil
+	fake.revokeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredentialsSource) RevokeReturnsOnCall(i int, result1 error) {
+	fake.revokeMutex.Lock()
+	defer fake.revokeMutex.Unlock()
+	fake.RevokeStub = nil
+	if fake.revokeReturnsOnCall == nil {
+		fake.revokeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredentialsSource) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.fetchMutex.RLock()
+	defer fake.fetchMutex.RUnlock()
+	fake.renewMutex.RLock()
+	defer fake.renewMutex.RUnlock()
+	fake.revokeMutex.RLock()
+	defer fake.revokeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeCredentialsSource) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ postgresql.CredentialsSource = new(FakeCredentialsSource)
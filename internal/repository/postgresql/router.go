@@ -0,0 +1,334 @@
+package postgresql
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal/repository/postgresql/db"
+)
+
+const primaryName = "primary"
+
+//counterfeiter:generate -o postgresqltesting/conn.gen.go . Conn
+
+// Conn is a connection pool, like pgxpool.Pool and RotatingPool.
+type Conn interface {
+	db.DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Ping(ctx context.Context) error
+}
+
+// Replica is a read-only Conn, it is selected with a probability proportional to its weight.
+type Replica struct {
+	Name   string
+	Conn   Conn
+	Weight int
+}
+
+type replica struct {
+	Replica
+	healthy bool
+}
+
+// Router is a db.DBTX sending reads to the replicas and everything else to the primary: writes, transactions
+// and reads in a session that wrote recently, so the session reads its own writes even when the replicas are
+// lagging. Reads are SELECT statements not locking rows, anything else is considered a write.
+type Router struct {
+	primary     Conn
+	logger      *zap.Logger
+	stickiness  time.Duration
+	interval    time.Duration
+	sessionFunc func(ctx context.Context) string
+
+	queries metric.Int64Counter
+
+	mu       sync.RWMutex
+	replicas []replica
+	writes   map[string]time.Time
+	pruned   time.Time
+}
+
+// RouterOption defines the options used by Router.
+type RouterOption func(*Router)
+
+// WithStickiness indicates how long a session reads from the primary after writing, defaults to 5 seconds.
+func WithStickiness(d time.Duration) RouterOption {
+	return func(r *Router) {
+		r.stickiness = d
+	}
+}
+
+// WithHealthCheckInterval indicates how often Run pings the replicas, defaults to 10 seconds.
+func WithHealthCheckInterval(d time.Duration) RouterOption {
+	return func(r *Router) {
+		r.interval = d
+	}
+}
+
+// WithSessionFunc indicates how the session is determined, defaults to the one set by WithSession. Returning
+// the request ID makes a request read its own writes, returning a value identifying the client extends that to
+// its subsequent requests.
+func WithSessionFunc(fn func(ctx context.Context) string) RouterOption {
+	return func(r *Router) {
+		r.sessionFunc = fn
+	}
+}
+
+type sessionKey struct{}
+
+type primaryKey struct{}
+
+// WithSession returns a copy of the context indicating the session making the calls.
+func WithSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// WithPrimary returns a copy of the context forcing all the calls to use the primary, it is meant for reads
+// that must not be stale or that have side effects, like calling nextval.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// NewRouter instantiates the Router, all the replicas are considered healthy until Run checks them.
+func NewRouter(logger *zap.Logger, primary Conn, replicas []Replica, opts ...RouterOption) *Router {
+	r := &Router{
+		primary:    primary,
+		logger:     logger,
+		stickiness: 5 * time.Second,
+		interval:   10 * time.Second,
+		sessionFunc: func(ctx context.Context) string {
+			session, _ := ctx.Value(sessionKey{}).(string)
+
+			return session
+		},
+		queries: metric.Must(global.Meter("postgresql")).NewInt64Counter("postgresql.router.queries",
+			metric.WithDescription("Queries sent to each one of the databases")),
+		writes: make(map[string]time.Time),
+	}
+
+	for _, rep := range replicas {
+		r.replicas = append(r.replicas, replica{Replica: rep, healthy: true})
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Exec always uses the primary.
+func (r *Router) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return r.write(ctx).Exec(ctx, sql, args...)
+}
+
+// Query uses a replica for reads.
+func (r *Router) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return r.route(ctx, sql).Query(ctx, sql, args...)
+}
+
+// QueryRow uses a replica for reads.
+func (r *Router) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return r.route(ctx, sql).QueryRow(ctx, sql, args...)
+}
+
+// Begin always uses the primary.
+func (r *Router) Begin(ctx context.Context) (pgx.Tx, error) {
+	return r.write(ctx).Begin(ctx)
+}
+
+// Ping checks the primary, replicas are checked by Run.
+func (r *Router) Ping(ctx context.Context) error {
+	return r.primary.Ping(ctx)
+}
+
+// Run checks the health of the replicas periodically until the context is canceled.
+func (r *Router) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.CheckReplicas(ctx)
+	}
+}
+
+// CheckReplicas pings the replicas, the ones failing are not used until they succeed again. Expired sessions
+// are forgotten as well.
+func (r *Router) CheckReplicas(ctx context.Context) {
+	r.mu.RLock()
+	replicas := make([]replica, len(r.replicas))
+	copy(replicas, r.replicas)
+	r.mu.RUnlock()
+
+	healthy := make([]bool, len(replicas))
+
+	var wg sync.WaitGroup
+
+	for i, rep := range replicas {
+		wg.Add(1)
+
+		go func(i int, rep replica) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, r.interval/2)
+			defer cancel()
+
+			err := rep.Conn.Ping(ctx)
+			healthy[i] = err == nil
+
+			if healthy[i] != rep.healthy {
+				r.logger.Info("replica health changed",
+					zap.String("replica", rep.Name),
+					zap.Bool("healthy", healthy[i]),
+					zap.Error(err),
+				)
+			}
+		}(i, rep)
+	}
+
+	wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.replicas {
+		r.replicas[i].healthy = healthy[i]
+	}
+
+	r.prune(time.Now())
+}
+
+// route returns the database to use for the statement.
+func (r *Router) route(ctx context.Context, sql string) Conn {
+	if !isRead(sql) {
+		return r.write(ctx)
+	}
+
+	if forced, _ := ctx.Value(primaryKey{}).(bool); forced {
+		return r.use(ctx, primaryName, r.primary)
+	}
+
+	session := r.sessionFunc(ctx)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if session != "" {
+		if at, ok := r.writes[session]; ok && time.Since(at) < r.stickiness {
+			return r.use(ctx, primaryName, r.primary)
+		}
+	}
+
+	var total int
+
+	for _, rep := range r.replicas {
+		if rep.healthy && rep.Weight > 0 {
+			total += rep.Weight
+		}
+	}
+
+	if total == 0 {
+		return r.use(ctx, primaryName, r.primary)
+	}
+
+	n := rand.Intn(total) //nolint:gosec
+
+	for _, rep := range r.replicas {
+		if !rep.healthy || rep.Weight <= 0 {
+			continue
+		}
+
+		if n < rep.Weight {
+			return r.use(ctx, rep.Name, rep.Conn)
+		}
+
+		n -= rep.Weight
+	}
+
+	return r.use(ctx, primaryName, r.primary) // XXX: Unreachable.
+}
+
+// write returns the primary, remembering the session wrote. Expired sessions are forgotten at most once per
+// stickiness period, Run may not be running.
+func (r *Router) write(ctx context.Context) Conn {
+	if session := r.sessionFunc(ctx); session != "" {
+		now := time.Now()
+
+		r.mu.Lock()
+
+		r.writes[session] = now
+
+		if now.Sub(r.pruned) > r.stickiness {
+			r.prune(now)
+		}
+
+		r.mu.Unlock()
+	}
+
+	return r.use(ctx, primaryName, r.primary)
+}
+
+// prune forgets the sessions that didn't write recently, it must be called holding the lock.
+func (r *Router) prune(now time.Time) {
+	for session, at := range r.writes {
+		if now.Sub(at) > r.stickiness {
+			delete(r.writes, session)
+		}
+	}
+
+	r.pruned = now
+}
+
+func (r *Router) use(ctx context.Context, name string, conn Conn) Conn {
+	r.queries.Add(ctx, 1, attribute.String("database", name))
+
+	return conn
+}
+
+// isRead indicates whether the statement is a SELECT not locking rows.
+func isRead(sql string) bool {
+	// XXX: Skipping the comments, queries generated by sqlc start with "-- name: <query> <type>".
+	for {
+		sql = strings.TrimSpace(sql)
+		if !strings.HasPrefix(sql, "--") {
+			break
+		}
+
+		i := strings.IndexByte(sql, '\n')
+		if i == -1 {
+			return false
+		}
+
+		sql = sql[i+1:]
+	}
+
+	if len(sql) < len("SELECT") || !strings.EqualFold(sql[:len("SELECT")], "SELECT") {
+		return false
+	}
+
+	upper := strings.ToUpper(sql)
+
+	for _, clause := range []string{"FOR UPDATE", "FOR NO KEY UPDATE", "FOR SHARE", "FOR KEY SHARE"} {
+		if strings.Contains(upper, clause) {
+			return false
+		}
+	}
+
+	return true
+}
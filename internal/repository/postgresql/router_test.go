@@ -0,0 +1,200 @@
+package postgresql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal/repository/postgresql"
+	"github.com/lrweck/todo/internal/repository/postgresql/postgresqltesting"
+)
+
+const (
+	selectTask = `-- name: SelectTask :one
+SELECT id, description FROM tasks WHERE id = $1 LIMIT 1`
+	insertTask = `-- name: InsertTask :one
+INSERT INTO tasks (description) VALUES ($1) RETURNING id`
+)
+
+func TestRouter_Route(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		primary int
+		replica int
+	}
+
+	tests := []struct {
+		name   string
+		call   func(ctx context.Context, r *postgresql.Router)
+		output output
+	}{
+		{
+			"OK: select",
+			func(ctx context.Context, r *postgresql.Router) {
+				_ = r.QueryRow(ctx, selectTask, 1)
+			},
+			output{
+				replica: 1,
+			},
+		},
+		{
+			"OK: insert returning",
+			func(ctx context.Context, r *postgresql.Router) {
+				_ = r.QueryRow(ctx, insertTask, "description")
+			},
+			output{
+				primary: 1,
+			},
+		},
+		{
+			"OK: select for update",
+			func(ctx context.Context, r *postgresql.Router) {
+				_, _ = r.Query(ctx, "SELECT id FROM tasks FOR UPDATE SKIP LOCKED")
+			},
+			output{
+				primary: 1,
+			},
+		},
+		{
+			"OK: forced primary",
+			func(ctx context.Context, r *postgresql.Router) {
+				_ = r.QueryRow(postgresql.WithPrimary(ctx), selectTask, 1)
+			},
+			output{
+				primary: 1,
+			},
+		},
+		{
+			"OK: read your writes",
+			func(ctx context.Context, r *postgresql.Router) {
+				ctx = postgresql.WithSession(ctx, "session")
+
+				_, _ = r.Exec(ctx, "DELETE FROM tasks")
+				_ = r.QueryRow(ctx, selectTask, 1)
+				_ = r.QueryRow(postgresql.WithSession(ctx, "other"), selectTask, 1)
+			},
+			output{
+				primary: 2,
+				replica: 1,
+			},
+		},
+		{
+			"OK: read after stickiness",
+			func(ctx context.Context, r *postgresql.Router) {
+				ctx = postgresql.WithSession(ctx, "session")
+
+				_, _ = r.Exec(ctx, "DELETE FROM tasks")
+				time.Sleep(100 * time.Millisecond)
+				_ = r.QueryRow(ctx, selectTask, 1)
+			},
+			output{
+				primary: 1,
+				replica: 1,
+			},
+		},
+		{
+			"OK: transaction",
+			func(ctx context.Context, r *postgresql.Router) {
+				_, _ = r.Begin(ctx)
+			},
+			output{
+				primary: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			primary := &postgresqltesting.FakeConn{}
+			replica := &postgresqltesting.FakeConn{}
+
+			router := postgresql.NewRouter(zap.NewNop(), primary,
+				[]postgresql.Replica{{Name: "replica", Conn: replica, Weight: 1}},
+				postgresql.WithStickiness(50*time.Millisecond))
+
+			tt.call(context.Background(), router)
+
+			if calls := callCount(primary); calls != tt.output.primary {
+				t.Fatalf("expected %d calls to primary, got %d", tt.output.primary, calls)
+			}
+
+			if calls := callCount(replica); calls != tt.output.replica {
+				t.Fatalf("expected %d calls to replica, got %d", tt.output.replica, calls)
+			}
+		})
+	}
+}
+
+func TestRouter_CheckReplicas(t *testing.T) {
+	t.Parallel()
+
+	primary := &postgresqltesting.FakeConn{}
+	healthy := &postgresqltesting.FakeConn{}
+	unhealthy := &postgresqltesting.FakeConn{}
+	unhealthy.PingReturns(errors.New("connection refused"))
+	disabled := &postgresqltesting.FakeConn{}
+
+	router := postgresql.NewRouter(zap.NewNop(), primary, []postgresql.Replica{
+		{Name: "healthy", Conn: healthy, Weight: 1},
+		{Name: "unhealthy", Conn: unhealthy, Weight: 100},
+		{Name: "disabled", Conn: disabled, Weight: 0},
+	})
+
+	router.CheckReplicas(context.Background())
+
+	for i := 0; i < 10; i++ {
+		_ = router.QueryRow(context.Background(), selectTask, 1)
+	}
+
+	if calls := healthy.QueryRowCallCount(); calls != 10 {
+		t.Fatalf("expected 10 calls to the healthy replica, got %d", calls)
+	}
+
+	// All replicas unhealthy, falling back to the primary.
+	healthy.PingReturns(errors.New("connection refused"))
+
+	router.CheckReplicas(context.Background())
+
+	_ = router.QueryRow(context.Background(), selectTask, 1)
+
+	if calls := primary.QueryRowCallCount(); calls != 1 {
+		t.Fatalf("expected 1 call to the primary, got %d", calls)
+	}
+
+	if calls := unhealthy.QueryRowCallCount() + disabled.QueryRowCallCount(); calls != 0 {
+		t.Fatalf("expected no calls to the unhealthy and disabled replicas, got %d", calls)
+	}
+}
+
+func TestRouter_Weights(t *testing.T) {
+	t.Parallel()
+
+	light := &postgresqltesting.FakeConn{}
+	heavy := &postgresqltesting.FakeConn{}
+
+	router := postgresql.NewRouter(zap.NewNop(), &postgresqltesting.FakeConn{}, []postgresql.Replica{
+		{Name: "light", Conn: light, Weight: 1},
+		{Name: "heavy", Conn: heavy, Weight: 9},
+	})
+
+	for i := 0; i < 1000; i++ {
+		_ = router.QueryRow(context.Background(), selectTask, 1)
+	}
+
+	// XXX: Expecting 900 calls, the margin keeps the test from being flaky.
+	if calls := heavy.QueryRowCallCount(); calls < 800 || calls+light.QueryRowCallCount() != 1000 {
+		t.Fatalf("expected around 900 calls to the heavier replica, got %d", calls)
+	}
+}
+
+func callCount(conn *postgresqltesting.FakeConn) int {
+	return conn.ExecCallCount() + conn.QueryCallCount() + conn.QueryRowCallCount() + conn.BeginCallCount()
+}
@@ -3,6 +3,8 @@ package postgresql
 import (
 	"context"
 	"errors"
+	"math"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
@@ -11,25 +13,29 @@ import (
 
 	"github.com/lrweck/todo/internal"
 	"github.com/lrweck/todo/internal/repository/postgresql/db"
+	"github.com/lrweck/todo/internal/telemetry"
 )
 
 // Task represents the repository used for interacting with Task records.
 type Task struct {
-	q *db.Queries
+	q       *db.Queries
+	metrics telemetry.RepositoryMetrics
 }
 
 // NewTask instantiates the Task repository.
 func NewTask(d db.DBTX) *Task {
 	return &Task{
-		q: db.New(d),
+		q:       db.New(d),
+		metrics: telemetry.NewRepositoryMetrics("postgresql"),
 	}
 }
 
-func (t *Task) Create(ctx context.Context, params internal.CreateParams) (internal.Task, error) {
+func (t *Task) Create(ctx context.Context, params internal.CreateParams) (_ internal.Task, err error) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Task.Create")
 	span.SetAttributes(attribute.String("db.system", "postgresql"))
 
 	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Create", time.Now(), &err)
 
 	// XXX: `ID` and `IsDone` make no sense when creating new records, that's why those are ignored.
 	// XXX: We are intentionally NOT SUPPORTING `SubTasks` and `Categories` JUST YET.
@@ -41,7 +47,7 @@ func (t *Task) Create(ctx context.Context, params internal.CreateParams) (intern
 		DueDate:     newNullTime(params.Dates.Due),
 	})
 	if err != nil {
-		return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeUnknown, "insert task")
+		return internal.Task{}, internal.WrapErrorf(err, ErrorCode(err), "insert task")
 	}
 
 	return internal.Task{
@@ -52,12 +58,13 @@ func (t *Task) Create(ctx context.Context, params internal.CreateParams) (intern
 	}, nil
 }
 
-// Delete deletes the existing record matching the id.
-func (t *Task) Delete(ctx context.Context, id string) error {
+// Delete moves the existing record matching the id to the trash.
+func (t *Task) Delete(ctx context.Context, id string) (err error) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Task.Delete")
 	span.SetAttributes(attribute.String("db.system", "postgresql"))
 
 	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Delete", time.Now(), &err)
 
 	val, err := uuid.Parse(id)
 	if err != nil {
@@ -70,18 +77,19 @@ func (t *Task) Delete(ctx context.Context, id string) error {
 			return internal.WrapErrorf(err, internal.ErrCodeNotFound, "task not found")
 		}
 
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "delete task")
+		return internal.WrapErrorf(err, ErrorCode(err), "delete task")
 	}
 
 	return nil
 }
 
 // Find returns the requested task by searching its id.
-func (t *Task) Find(ctx context.Context, id string) (internal.Task, error) {
+func (t *Task) Find(ctx context.Context, id string) (_ internal.Task, err error) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Task.Find")
 	span.SetAttributes(attribute.String("db.system", "postgresql"))
 
 	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Find", time.Now(), &err)
 
 	val, err := uuid.Parse(id)
 	if err != nil {
@@ -94,7 +102,7 @@ func (t *Task) Find(ctx context.Context, id string) (internal.Task, error) {
 			return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeNotFound, "task not found")
 		}
 
-		return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeUnknown, "select task")
+		return internal.Task{}, internal.WrapErrorf(err, ErrorCode(err), "select task")
 	}
 
 	priority, err := convertPriority(res.Priority)
@@ -114,12 +122,159 @@ func (t *Task) Find(ctx context.Context, id string) (internal.Task, error) {
 	}, nil
 }
 
+// FindMany returns the requested tasks using one query, the ones not found are not included.
+func (t *Task) FindMany(ctx context.Context, ids []string) (_ []internal.Task, err error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Task.FindMany")
+	span.SetAttributes(attribute.String("db.system", "postgresql"))
+
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.FindMany", time.Now(), &err)
+
+	vals := make([]uuid.UUID, len(ids))
+
+	for i, id := range ids {
+		val, err := uuid.Parse(id)
+		if err != nil {
+			return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid uuid")
+		}
+
+		vals[i] = val
+	}
+
+	if len(vals) == 0 {
+		return nil, nil
+	}
+
+	res, err := t.q.SelectTasks(ctx, vals)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, ErrorCode(err), "select tasks")
+	}
+
+	tasks := make([]internal.Task, len(res))
+
+	for i, task := range res {
+		priority, err := convertPriority(task.Priority)
+		if err != nil {
+			return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "convert priority")
+		}
+
+		tasks[i] = internal.Task{
+			ID:          task.ID.String(),
+			Description: task.Description,
+			Priority:    priority,
+			Dates: internal.Dates{
+				Start: task.StartDate.Time,
+				Due:   task.DueDate.Time,
+			},
+			IsDone: task.Done,
+		}
+	}
+
+	return tasks, nil
+}
+
+// Purge permanently deletes the records moved to the trash before the received time, it returns the number of
+// deleted records.
+func (t *Task) Purge(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Task.Purge")
+	span.SetAttributes(attribute.String("db.system", "postgresql"))
+
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Purge", time.Now(), &err)
+
+	count, err := t.q.PurgeDeletedTasks(ctx, newNullTime(before))
+	if err != nil {
+		return 0, internal.WrapErrorf(err, ErrorCode(err), "purge deleted tasks")
+	}
+
+	return count, nil
+}
+
+// Restore moves the existing record matching the id out of the trash.
+func (t *Task) Restore(ctx context.Context, id string) (err error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Task.Restore")
+	span.SetAttributes(attribute.String("db.system", "postgresql"))
+
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Restore", time.Now(), &err)
+
+	val, err := uuid.Parse(id)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid uuid")
+	}
+
+	if _, err = t.q.RestoreTask(ctx, val); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return internal.WrapErrorf(err, internal.ErrCodeNotFound, "task not found in trash")
+		}
+
+		return internal.WrapErrorf(err, ErrorCode(err), "restore task")
+	}
+
+	return nil
+}
+
+// Trash returns the records moved to the trash, most recently deleted first.
+func (t *Task) Trash(ctx context.Context, args internal.TrashParams) (_ internal.TrashResults, err error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Task.Trash")
+	span.SetAttributes(attribute.String("db.system", "postgresql"))
+
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Trash", time.Now(), &err)
+
+	if args.From < 0 || args.From > math.MaxInt32 || args.Size < 0 || args.Size > math.MaxInt32 {
+		return internal.TrashResults{}, internal.NewErrorf(internal.ErrCodeInvalidArgument, "invalid from or size")
+	}
+
+	res, err := t.q.SelectDeletedTasks(ctx, db.SelectDeletedTasksParams{
+		Limit:  int32(args.Size),
+		Offset: int32(args.From),
+	})
+	if err != nil {
+		return internal.TrashResults{}, internal.WrapErrorf(err, ErrorCode(err), "select deleted tasks")
+	}
+
+	total, err := t.q.CountDeletedTasks(ctx)
+	if err != nil {
+		return internal.TrashResults{}, internal.WrapErrorf(err, ErrorCode(err), "count deleted tasks")
+	}
+
+	tasks := make([]internal.TrashedTask, len(res))
+
+	for i, task := range res {
+		priority, err := convertPriority(task.Priority)
+		if err != nil {
+			return internal.TrashResults{}, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "convert priority")
+		}
+
+		tasks[i] = internal.TrashedTask{
+			Task: internal.Task{
+				ID:          task.ID.String(),
+				Description: task.Description,
+				Priority:    priority,
+				Dates: internal.Dates{
+					Start: task.StartDate.Time,
+					Due:   task.DueDate.Time,
+				},
+				IsDone: task.Done,
+			},
+			DeletedAt: task.DeletedAt.Time,
+		}
+	}
+
+	return internal.TrashResults{
+		Tasks: tasks,
+		Total: total,
+	}, nil
+}
+
 // Update updates the existing record with new values.
-func (t *Task) Update(ctx context.Context, id string, description string, priority internal.Priority, dates internal.Dates, isDone bool) error {
+func (t *Task) Update(ctx context.Context, id string, description string, priority internal.Priority, dates internal.Dates, isDone bool) (err error) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("postgresql").Start(ctx, "Task.Update")
 	span.SetAttributes(attribute.String("db.system", "postgresql"))
 
 	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Update", time.Now(), &err)
 
 	// XXX: We will revisit the number of received arguments in future episodes.
 	val, err := uuid.Parse(id)
@@ -139,7 +294,7 @@ func (t *Task) Update(ctx context.Context, id string, description string, priori
 			return internal.WrapErrorf(err, internal.ErrCodeNotFound, "task not found")
 		}
 
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "update task")
+		return internal.WrapErrorf(err, ErrorCode(err), "update task")
 	}
 
 	return nil
@@ -11,9 +11,6 @@ import (
 	"testing"
 	"time"
 
-	migrate "github.com/golang-migrate/migrate/v4"
-	migratepostgres "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/google/go-cmp/cmp"
 
 	// Initialize "pgx".
@@ -22,7 +19,9 @@ import (
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
+	"go.uber.org/zap"
 
+	"github.com/lrweck/todo/db/migrations"
 	"github.com/lrweck/todo/internal"
 	"github.com/lrweck/todo/internal/repository/postgresql"
 )
@@ -62,7 +61,7 @@ func TestTask_Create(t *testing.T) {
 		}
 
 		var ierr *internal.Error
-		if !errors.As(err, &ierr) || ierr.Code() != internal.ErrCodeUnknown {
+		if !errors.As(err, &ierr) || ierr.Code() != internal.ErrCodeInvalidArgument {
 			t.Fatalf("expected %T error, got %T : %v", ierr, err, err)
 		}
 	})
@@ -177,6 +176,46 @@ func TestTask_Find(t *testing.T) {
 	})
 }
 
+func TestTask_FindMany(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FindMany: OK", func(t *testing.T) {
+		t.Parallel()
+
+		store := postgresql.NewTask(newDB(t))
+
+		originalTask, err := store.Create(context.Background(), internal.CreateParams{
+			Description: "test",
+			Priority:    internal.PriorityNone,
+			Dates:       internal.Dates{},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		// Missing tasks are not included.
+
+		actual, err := store.FindMany(context.Background(),
+			[]string{originalTask.ID, "44633fe3-b039-4fb3-a35f-a57fe3c906c7"})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if expected := []internal.Task{originalTask}; !cmp.Equal(expected, actual) {
+			t.Fatalf("expected result does not match: %s", cmp.Diff(expected, actual))
+		}
+	})
+
+	t.Run("FindMany: ERR uuid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := postgresql.NewTask(newDB(t)).FindMany(context.Background(), []string{"x"})
+		if internal.Code(err) != internal.ErrCodeInvalidArgument {
+			t.Fatalf("expected invalid argument error, got %v", err)
+		}
+	})
+}
+
 func TestTask_Update(t *testing.T) {
 	t.Parallel()
 
@@ -265,7 +304,7 @@ func TestTask_Update(t *testing.T) {
 		}
 
 		var ierr *internal.Error
-		if !errors.As(err, &ierr) || ierr.Code() != internal.ErrCodeUnknown {
+		if !errors.As(err, &ierr) || ierr.Code() != internal.ErrCodeInvalidArgument {
 			t.Fatalf("expected %T error, got %T : %v", ierr, err, err)
 		}
 	})
@@ -290,9 +329,124 @@ func TestTask_Update(t *testing.T) {
 	})
 }
 
+func TestTask_Restore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Restore: OK", func(t *testing.T) {
+		t.Parallel()
+
+		store := postgresql.NewTask(newDB(t))
+
+		originalTask, err := store.Create(context.Background(), internal.CreateParams{
+			Description: "test",
+			Priority:    internal.PriorityNone,
+			Dates:       internal.Dates{},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if err := store.Delete(context.Background(), originalTask.ID); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		trash, err := store.Trash(context.Background(), internal.TrashParams{Size: 10})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if trash.Total != 1 || trash.Tasks[0].Task.ID != originalTask.ID {
+			t.Fatalf("expected task in trash, got %#v", trash)
+		}
+
+		if err := store.Restore(context.Background(), originalTask.ID); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		actualTask, err := store.Find(context.Background(), originalTask.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if !cmp.Equal(originalTask, actualTask) {
+			t.Fatalf("expected result does not match: %s", cmp.Diff(originalTask, actualTask))
+		}
+	})
+
+	t.Run("Restore: ERR not found", func(t *testing.T) {
+		t.Parallel()
+
+		err := postgresql.NewTask(newDB(t)).Restore(context.Background(), "44633fe3-b039-4fb3-a35f-a57fe3c906c7")
+
+		var ierr *internal.Error
+		if !errors.As(err, &ierr) || ierr.Code() != internal.ErrCodeNotFound {
+			t.Fatalf("expected %T error, got %T : %v", ierr, err, err)
+		}
+	})
+}
+
+func TestTask_Purge(t *testing.T) {
+	t.Parallel()
+
+	store := postgresql.NewTask(newDB(t))
+
+	task, err := store.Create(context.Background(), internal.CreateParams{
+		Description: "test",
+		Priority:    internal.PriorityNone,
+		Dates:       internal.Dates{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if err := store.Delete(context.Background(), task.ID); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	count, err := store.Purge(context.Background(), time.Now().UTC().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected 1 purged task, got %d", count)
+	}
+
+	err = store.Restore(context.Background(), task.ID)
+
+	var ierr *internal.Error
+	if !errors.As(err, &ierr) || ierr.Code() != internal.ErrCodeNotFound {
+		t.Fatalf("expected %T error, got %T : %v", ierr, err, err)
+	}
+}
+
 func newDB(tb testing.TB) *pgxpool.Pool {
 	tb.Helper()
 
+	dsn := newDSN(tb)
+
+	if err := postgresql.NewMigrator(zap.NewNop(), dsn, migrations.FS).Up(context.Background()); err != nil {
+		tb.Fatalf("Couldn't migrate: %s", err)
+	}
+
+	//-
+
+	dbpool, err := pgxpool.Connect(context.Background(), dsn)
+	if err != nil {
+		tb.Fatalf("Couldn't open DB Pool: %s", err)
+	}
+
+	tb.Cleanup(func() {
+		dbpool.Close()
+	})
+
+	return dbpool
+}
+
+// newDSN starts a new PostgreSQL server and returns the DSN for connecting to it.
+func newDSN(tb testing.TB) string {
+	tb.Helper()
+
 	dsn := &url.URL{
 		Scheme: "postgres",
 		User:   url.UserPassword("username", "password"),
@@ -359,32 +513,5 @@ func newDB(tb testing.TB) *pgxpool.Pool {
 		tb.Fatalf("Couldn't ping DB: %s", err)
 	}
 
-	//-
-
-	instance, err := migratepostgres.WithInstance(db, &migratepostgres.Config{})
-	if err != nil {
-		tb.Fatalf("Couldn't migrate (1): %s", err)
-	}
-
-	m, err := migrate.NewWithDatabaseInstance("file://../../db/migrations/", "postgres", instance)
-	if err != nil {
-		tb.Fatalf("Couldn't migrate (2): %s", err)
-	}
-
-	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		tb.Fatalf("Couldnt' migrate (3): %s", err)
-	}
-
-	//-
-
-	dbpool, err := pgxpool.Connect(context.Background(), dsn.String())
-	if err != nil {
-		tb.Fatalf("Couldn't open DB Pool: %s", err)
-	}
-
-	tb.Cleanup(func() {
-		dbpool.Close()
-	})
-
-	return dbpool
+	return dsn.String()
 }
@@ -0,0 +1,111 @@
+package rediscache
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// localCache is the in-process cache used for client-side caching, "size" limits the number of entries when
+// positive. All methods are safe to call on a nil value in which case they do nothing.
+type localCache struct {
+	mu      sync.Mutex
+	entries map[string]localEntry
+	size    int
+	ttl     time.Duration
+}
+
+type localEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func newLocalCache(size int, ttl time.Duration) *localCache {
+	return &localCache{
+		entries: make(map[string]localEntry, size),
+		size:    size,
+		ttl:     ttl,
+	}
+}
+
+func (l *localCache) get(key string) ([]byte, bool) {
+	if l == nil {
+		return nil, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(l.entries, key)
+
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+func (l *localCache) set(key string, value []byte) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	if _, ok := l.entries[key]; !ok && l.size > 0 && len(l.entries) >= l.size {
+		// Make room by dropping expired entries first, and any entry if none expired.
+		for k, entry := range l.entries {
+			if now.After(entry.expiresAt) {
+				delete(l.entries, k)
+			}
+		}
+
+		for k := range l.entries {
+			if len(l.entries) < l.size {
+				break
+			}
+
+			delete(l.entries, k)
+		}
+	}
+
+	l.entries[key] = localEntry{
+		value:     value,
+		expiresAt: now.Add(l.ttl),
+	}
+}
+
+func (l *localCache) delete(keys ...string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		delete(l.entries, key)
+	}
+}
+
+func encodeKeys(keys []string) string {
+	b, _ := json.Marshal(keys)
+
+	return string(b)
+}
+
+func decodeKeys(payload string) []string {
+	var keys []string
+
+	_ = json.Unmarshal([]byte(payload), &keys)
+
+	return keys
+}
@@ -0,0 +1,208 @@
+package rediscache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/lrweck/todo/internal"
+)
+
+const (
+	invalidationsChannel = "rediscache:invalidations"
+	searchTag            = "tag:search"
+)
+
+// Option configures the Cache.
+type Option func(*Cache)
+
+// WithLocalCache enables client-side caching, values are kept in process memory for "ttl" in addition to Redis.
+// Entries are evicted locally when written by this process, and by other processes when they receive the
+// invalidations published by the writer, see Cache.SubscribeInvalidations.
+func WithLocalCache(size int, ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.local = newLocalCache(size, ttl)
+	}
+}
+
+// Cache wraps the Redis client with the operations shared by both decorators, the same Cache is meant to be
+// used by both so they share the local cache and its invalidations subscription.
+//
+// XXX: Like in the "memcached" package, "set" and "invalidate" intentionally ignore errors, a cache failure
+// must not fail the request.
+type Cache struct {
+	client *redis.Client
+	local  *localCache
+}
+
+// NewCache instantiates the Cache.
+func NewCache(client *redis.Client, opts ...Option) *Cache {
+	c := Cache{
+		client: client,
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c
+}
+
+func (c *Cache) get(ctx context.Context, key string, target interface{}) error {
+	val, ok := c.local.get(key)
+	if !ok {
+		res, err := c.client.Get(ctx, key).Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				return internal.WrapErrorf(err, internal.ErrCodeNotFound, "client.Get")
+			}
+
+			return internal.WrapErrorf(err, internal.Code(err), "client.Get")
+		}
+
+		val = res
+
+		c.local.set(key, val)
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(val)).Decode(target); err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "gob.NewDecoder")
+	}
+
+	return nil
+}
+
+// getMany returns the values found, using one round trip for the ones missing in the local cache. Errors are
+// ignored, the keys are reported as missing instead.
+func (c *Cache) getMany(ctx context.Context, keys []string) map[string][]byte {
+	res := make(map[string][]byte, len(keys))
+
+	var remote []string
+
+	for _, key := range keys {
+		if val, ok := c.local.get(key); ok {
+			res[key] = val
+
+			continue
+		}
+
+		remote = append(remote, key)
+	}
+
+	if len(remote) == 0 {
+		return res
+	}
+
+	vals, err := c.client.MGet(ctx, remote...).Result()
+	if err != nil {
+		return res
+	}
+
+	for i, val := range vals {
+		if str, ok := val.(string); ok {
+			res[remote[i]] = []byte(str)
+
+			c.local.set(remote[i], []byte(str))
+		}
+	}
+
+	return res
+}
+
+// set stores the value, the key is added to each one of the received tags so it can be evicted with them.
+func (c *Cache) set(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) {
+	var b bytes.Buffer
+
+	if err := gob.NewEncoder(&b).Encode(value); err != nil {
+		return
+	}
+
+	_, _ = c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, b.Bytes(), expiration)
+
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tag, key)
+			pipe.Expire(ctx, tag, expiration)
+		}
+
+		return nil
+	})
+
+	c.local.set(key, b.Bytes())
+}
+
+// invalidate deletes the keys as well as all the keys associated to the tags using two round trips: one for
+// reading the tag members and one for deleting everything.
+func (c *Cache) invalidate(ctx context.Context, keys []string, tags ...string) {
+	if len(tags) > 0 {
+		cmds, _ := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, tag := range tags {
+				pipe.SMembers(ctx, tag)
+			}
+
+			return nil
+		})
+
+		for _, cmd := range cmds {
+			if members, err := cmd.(*redis.StringSliceCmd).Result(); err == nil {
+				keys = append(keys, members...)
+			}
+		}
+
+		keys = append(keys, tags...)
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	_, _ = c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+
+		if c.local != nil {
+			pipe.Publish(ctx, invalidationsChannel, encodeKeys(keys))
+		}
+
+		return nil
+	})
+
+	c.local.delete(keys...)
+}
+
+// SubscribeInvalidations listens for the keys invalidated by other processes and evicts them from the local
+// cache, it blocks until the context is canceled. It does nothing when "WithLocalCache" is not used.
+func (c *Cache) SubscribeInvalidations(ctx context.Context) error {
+	if c.local == nil {
+		return nil
+	}
+
+	pubsub := c.client.Subscribe(ctx, invalidationsChannel)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return internal.WrapErrorf(err, internal.Code(err), "pubsub.Receive")
+	}
+
+	ch := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			c.local.delete(decodeKeys(msg.Payload)...)
+		}
+	}
+}
+
+func taskKey(id string) string {
+	return "task:" + id
+}
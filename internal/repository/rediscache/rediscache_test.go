@@ -0,0 +1,363 @@
+package rediscache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/rediscache"
+	"github.com/lrweck/todo/internal/repository/rediscache/rediscachetesting"
+)
+
+func TestTask_Find(t *testing.T) {
+	t.Parallel()
+
+	expected := internal.Task{ID: "1", Description: "cached", Priority: internal.PriorityLow}
+
+	tests := []struct {
+		name   string
+		setup  func(*rediscachetesting.FakeTaskStore)
+		calls  int
+		output internal.Task
+		code   internal.ErrorCode
+	}{
+		{
+			"OK: cached after first call",
+			func(s *rediscachetesting.FakeTaskStore) {
+				s.FindReturns(expected, nil)
+			},
+			1,
+			expected,
+			0,
+		},
+		{
+			"ERR: not found is not cached",
+			func(s *rediscachetesting.FakeTaskStore) {
+				s.FindReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeNotFound, "not found"))
+			},
+			2,
+			internal.Task{},
+			internal.ErrCodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &rediscachetesting.FakeTaskStore{}
+			tt.setup(store)
+
+			task := rediscache.NewTask(rediscache.NewCache(newClient(t)), store, zap.NewNop())
+
+			for i := 0; i < 2; i++ {
+				actual, err := task.Find(context.Background(), "1")
+				if internal.Code(err) != tt.code {
+					t.Fatalf("expected %s error, got %v", tt.code, err)
+				}
+
+				if diff := cmp.Diff(tt.output, actual); diff != "" {
+					t.Fatalf("expected result does not match: %s", diff)
+				}
+			}
+
+			if calls := store.FindCallCount(); calls != tt.calls {
+				t.Fatalf("expected %d calls to the original store, got %d", tt.calls, calls)
+			}
+		})
+	}
+}
+
+func TestTask_FindMany(t *testing.T) {
+	t.Parallel()
+
+	store := &rediscachetesting.FakeTaskStore{}
+	store.FindReturns(internal.Task{ID: "1"}, nil)
+	store.FindManyReturns([]internal.Task{{ID: "2"}}, nil)
+
+	task := rediscache.NewTask(rediscache.NewCache(newClient(t)), store, zap.NewNop())
+
+	if _, err := task.Find(context.Background(), "1"); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	// Only the tasks missing in the cache are found, using one call.
+
+	actual, err := task.FindMany(context.Background(), []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]internal.Task{{ID: "1"}, {ID: "2"}}, actual); diff != "" {
+		t.Fatalf("expected result does not match: %s", diff)
+	}
+
+	if _, ids := store.FindManyArgsForCall(0); !cmp.Equal([]string{"2", "3"}, ids) {
+		t.Fatalf("expected missing tasks to be found, got %v", ids)
+	}
+
+	store.FindManyReturns(nil, nil)
+
+	if _, err := task.FindMany(context.Background(), []string{"1", "2"}); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if calls := store.FindManyCallCount(); calls != 1 {
+		t.Fatalf("expected results to be cached, got %d calls", calls)
+	}
+}
+
+func TestTask_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		write func(*rediscache.Task) error
+		calls int
+	}{
+		{
+			"OK: update refreshes the task",
+			func(task *rediscache.Task) error {
+				return task.Update(context.Background(), "1", "updated", internal.PriorityHigh, internal.Dates{}, true)
+			},
+			2,
+		},
+		{
+			"OK: delete evicts the task",
+			func(task *rediscache.Task) error {
+				return task.Delete(context.Background(), "1")
+			},
+			2,
+		},
+		{
+			"OK: restore keeps the task",
+			func(task *rediscache.Task) error {
+				return task.Restore(context.Background(), "1")
+			},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &rediscachetesting.FakeTaskStore{}
+			store.FindReturns(internal.Task{ID: "1"}, nil)
+
+			task := rediscache.NewTask(rediscache.NewCache(newClient(t)), store, zap.NewNop())
+
+			if _, err := task.Find(context.Background(), "1"); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if err := tt.write(task); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if _, err := task.Find(context.Background(), "1"); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if calls := store.FindCallCount(); calls != tt.calls {
+				t.Fatalf("expected %d calls to the original store, got %d", tt.calls, calls)
+			}
+		})
+	}
+}
+
+func TestSearchableTask_Search(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		write func(*rediscache.Task, *rediscache.SearchableTask) error
+	}{
+		{
+			"OK: create",
+			func(task *rediscache.Task, _ *rediscache.SearchableTask) error {
+				_, err := task.Create(context.Background(), internal.CreateParams{Description: "new"})
+
+				return err
+			},
+		},
+		{
+			"OK: update",
+			func(task *rediscache.Task, _ *rediscache.SearchableTask) error {
+				return task.Update(context.Background(), "1", "updated", internal.PriorityHigh, internal.Dates{}, true)
+			},
+		},
+		{
+			"OK: index",
+			func(_ *rediscache.Task, search *rediscache.SearchableTask) error {
+				return search.Index(context.Background(), internal.Task{ID: "1"})
+			},
+		},
+		{
+			"OK: delete from index",
+			func(_ *rediscache.Task, search *rediscache.SearchableTask) error {
+				return search.Delete(context.Background(), "1")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := rediscache.NewCache(newClient(t))
+
+			store := &rediscachetesting.FakeSearchableTaskStore{}
+			store.SearchReturns(internal.SearchResults{Tasks: []internal.Task{{ID: "1"}}, Total: 1}, nil)
+
+			search := rediscache.NewSearchableTask(cache, store)
+			task := rediscache.NewTask(cache, &rediscachetesting.FakeTaskStore{}, zap.NewNop())
+
+			args := internal.SearchParams{Size: 10}
+
+			for i := 0; i < 2; i++ {
+				if _, err := search.Search(context.Background(), args); err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+			}
+
+			if calls := store.SearchCallCount(); calls != 1 {
+				t.Fatalf("expected results to be cached, got %d calls", calls)
+			}
+
+			if err := tt.write(task, search); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if _, err := search.Search(context.Background(), args); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if calls := store.SearchCallCount(); calls != 2 {
+				t.Fatalf("expected results to be evicted, got %d calls", calls)
+			}
+		})
+	}
+}
+
+func TestSearchableTask_Search_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	client := newClient(t)
+	_ = client.Close()
+
+	store := &rediscachetesting.FakeSearchableTaskStore{}
+	store.SearchReturns(internal.SearchResults{Total: 1}, nil)
+
+	// A cache failure must not fail the request.
+
+	res, err := rediscache.NewSearchableTask(rediscache.NewCache(client), store).
+		Search(context.Background(), internal.SearchParams{Size: 10})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if res.Total != 1 {
+		t.Fatalf("expected results from the original store, got %+v", res)
+	}
+}
+
+func TestCache_SubscribeInvalidations(t *testing.T) {
+	t.Parallel()
+
+	server := miniredis.RunT(t)
+
+	store := &rediscachetesting.FakeTaskStore{}
+	store.FindReturnsOnCall(0, internal.Task{ID: "1", Description: "original"}, nil)
+	store.FindReturnsOnCall(1, internal.Task{ID: "1", Description: "updated"}, nil)
+	store.FindReturnsOnCall(2, internal.Task{ID: "1", Description: "updated"}, nil)
+
+	// Two processes sharing Redis, each one with its own local cache.
+
+	reader := rediscache.NewCache(redis.NewClient(&redis.Options{Addr: server.Addr()}),
+		rediscache.WithLocalCache(10, time.Minute))
+	writer := rediscache.NewCache(redis.NewClient(&redis.Options{Addr: server.Addr()}),
+		rediscache.WithLocalCache(10, time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	errC := make(chan error, 1)
+
+	go func() {
+		errC <- reader.SubscribeInvalidations(ctx)
+	}()
+
+	for server.PubSubNumSub("rediscache:invalidations")["rediscache:invalidations"] == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	readerTask := rediscache.NewTask(reader, store, zap.NewNop())
+
+	if task, _ := readerTask.Find(context.Background(), "1"); task.Description != "original" {
+		t.Fatalf("expected original task, got %+v", task)
+	}
+
+	// Evicted from Redis, still in the reader's local cache.
+
+	server.Del("task:1")
+
+	if task, _ := readerTask.Find(context.Background(), "1"); task.Description != "original" {
+		t.Fatalf("expected local cached task, got %+v", task)
+	}
+
+	if err := rediscache.NewTask(writer, store, zap.NewNop()).
+		Update(context.Background(), "1", "updated", internal.PriorityNone, internal.Dates{}, false); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+
+	for {
+		task, err := readerTask.Find(context.Background(), "1")
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if task.Description == "updated" {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected local cached task to be invalidated")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+
+	if err := <-errC; err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected no error, got %s", err)
+	}
+}
+
+func newClient(tb testing.TB) *redis.Client {
+	tb.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(tb).Addr()})
+
+	tb.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return client
+}
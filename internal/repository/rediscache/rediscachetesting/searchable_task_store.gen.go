@@ -0,0 +1,272 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package rediscachetesting
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/rediscache"
+)
+
+type FakeSearchableTaskStore struct {
+	DeleteStub        func(context.Context, string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	IndexStub        func(context.Context, internal.Task) error
+	indexMutex       sync.RWMutex
+	indexArgsForCall []struct {
+		arg1 context.Context
+		arg2 internal.Task
+	}
+	indexReturns struct {
+		result1 error
+	}
+	indexReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SearchStub        func(context.Context, internal.SearchParams) (internal.SearchResults, error)
+	searchMutex       sync.RWMutex
+	searchArgsForCall []struct {
+		arg1 context.Context
+		arg2 internal.SearchParams
+	}
+	searchReturns struct {
+		result1 internal.SearchResults
+		result2 error
+	}
+	searchReturnsOnCall map[int]struct {
+		result1 internal.SearchResults
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSearchableTaskStore) Delete(arg1 context.Context, arg2 string) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSearchableTaskStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeSearchableTaskStore) DeleteCalls(stub func(context.Context, string) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeSearchableTaskStore) DeleteArgsForCall(i int) (context.Context, string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSearchableTaskStore) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSearchableTaskStore) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSearchableTaskStore) Index(arg1 context.Context, arg2 internal.Task) error {
+	fake.indexMutex.Lock()
+	ret, specificReturn := fake.indexReturnsOnCall[len(fake.indexArgsForCall)]
+	fake.indexArgsForCall = append(fake.indexArgsForCall, struct {
+		arg1 context.Context
+		arg2 internal.Task
+	}{arg1, arg2})
+	stub := fake.IndexStub
+	fakeReturns := fake.indexReturns
+	fake.recordInvocation("Index", []interface{}{arg1, arg2})
+	fake.indexMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSearchableTaskStore) IndexCallCount() int {
+	fake.indexMutex.RLock()
+	defer fake.indexMutex.RUnlock()
+	return len(fake.indexArgsForCall)
+}
+
+func (fake *FakeSearchableTaskStore) IndexCalls(stub func(context.Context, internal.Task) error) {
+	fake.indexMutex.Lock()
+	defer fake.indexMutex.Unlock()
+	fake.IndexStub = stub
+}
+
+func (fake *FakeSearchableTaskStore) IndexArgsForCall(i int) (context.Context, internal.Task) {
+	fake.indexMutex.RLock()
+	defer fake.indexMutex.RUnlock()
+	argsForCall := fake.indexArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSearchableTaskStore) IndexReturns(result1 error) {
+	fake.indexMutex.Lock()
+	defer fake.indexMutex.Unlock()
+	fake.IndexStub = nil
+	fake.indexReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSearchableTaskStore) IndexReturnsOnCall(i int, result1 error) {
+	fake.indexMutex.Lock()
+	defer fake.indexMutex.Unlock()
+	fake.IndexStub = nil
+	if fake.indexReturnsOnCall == nil {
+		fake.indexReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.indexReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSearchableTaskStore) Search(arg1 context.Context, arg2 internal.SearchParams) (internal.SearchResults, error) {
+	fake.searchMutex.Lock()
+	ret, specificReturn := fake.searchReturnsOnCall[len(fake.searchArgsForCall)]
+	fake.searchArgsForCall = append(fake.searchArgsForCall, struct {
+		arg1 context.Context
+		arg2 internal.SearchParams
+	}{arg1, arg2})
+	stub := fake.SearchStub
+	fakeReturns := fake.searchReturns
+	fake.recordInvocation("Search", []interface{}{arg1, arg2})
+	fake.searchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSearchableTaskStore) SearchCallCount() int {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	return len(fake.searchArgsForCall)
+}
+
+func (fake *FakeSearchableTaskStore) SearchCalls(stub func(context.Context, internal.SearchParams) (internal.SearchResults, error)) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = stub
+}
+
+func (fake *FakeSearchableTaskStore) SearchArgsForCall(i int) (context.Context, internal.SearchParams) {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	argsForCall := fake.searchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSearchableTaskStore) SearchReturns(result1 internal.SearchResults, result2 error) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = nil
+	fake.searchReturns = struct {
+		result1 internal.SearchResults
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSearchableTaskStore) SearchReturnsOnCall(i int, result1 internal.SearchResults, result2 error) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = nil
+	if fake.searchReturnsOnCall == nil {
+		fake.searchReturnsOnCall = make(map[int]struct {
+			result1 internal.SearchResults
+			result2 error
+		})
+	}
+	fake.searchReturnsOnCall[i] = struct {
+		result1 internal.SearchResults
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSearchableTaskStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.indexMutex.RLock()
+	defer fake.indexMutex.RUnlock()
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSearchableTaskStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ rediscache.SearchableTaskStore = new(FakeSearchableTaskStore)
@@ -0,0 +1,604 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package rediscachetesting
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/rediscache"
+)
+
+type FakeTaskStore struct {
+	CreateStub        func(context.Context, internal.CreateParams) (internal.Task, error)
+	createMutex       sync.RWMutex
+	createArgsForCall []struct {
+		arg1 context.Context
+		arg2 internal.CreateParams
+	}
+	createReturns struct {
+		result1 internal.Task
+		result2 error
+	}
+	createReturnsOnCall map[int]struct {
+		result1 internal.Task
+		result2 error
+	}
+	DeleteStub        func(context.Context, string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FindStub        func(context.Context, string) (internal.Task, error)
+	findMutex       sync.RWMutex
+	findArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	findReturns struct {
+		result1 internal.Task
+		result2 error
+	}
+	findReturnsOnCall map[int]struct {
+		result1 internal.Task
+		result2 error
+	}
+	FindManyStub        func(context.Context, []string) ([]internal.Task, error)
+	findManyMutex       sync.RWMutex
+	findManyArgsForCall []struct {
+		arg1 context.Context
+		arg2 []string
+	}
+	findManyReturns struct {
+		result1 []internal.Task
+		result2 error
+	}
+	findManyReturnsOnCall map[int]struct {
+		result1 []internal.Task
+		result2 error
+	}
+	RestoreStub        func(context.Context, string) error
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	restoreReturns struct {
+		result1 error
+	}
+	restoreReturnsOnCall map[int]struct {
+		result1 error
+	}
+	TrashStub        func(context.Context, internal.TrashParams) (internal.TrashResults, error)
+	trashMutex       sync.RWMutex
+	trashArgsForCall []struct {
+		arg1 context.Context
+		arg2 internal.TrashParams
+	}
+	trashReturns struct {
+		result1 internal.TrashResults
+		result2 error
+	}
+	trashReturnsOnCall map[int]struct {
+		result1 internal.TrashResults
+		result2 error
+	}
+	UpdateStub        func(context.Context, string, string, internal.Priority, internal.Dates, bool) error
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 internal.Priority
+		arg5 internal.Dates
+		arg6 bool
+	}
+	updateReturns struct {
+		result1 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTaskStore) Create(arg1 context.Context, arg2 internal.CreateParams) (internal.Task, error) {
+	fake.createMutex.Lock()
+	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
+	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		arg1 context.Context
+		arg2 internal.CreateParams
+	}{arg1, arg2})
+	stub := fake.CreateStub
+	fakeReturns := fake.createReturns
+	fake.recordInvocation("Create", []interface{}{arg1, arg2})
+	fake.createMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTaskStore) CreateCallCount() int {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return len(fake.createArgsForCall)
+}
+
+func (fake *FakeTaskStore) CreateCalls(stub func(context.Context, internal.CreateParams) (internal.Task, error)) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = stub
+}
+
+func (fake *FakeTaskStore) CreateArgsForCall(i int) (context.Context, internal.CreateParams) {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	argsForCall := fake.createArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskStore) CreateReturns(result1 internal.Task, result2 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	fake.createReturns = struct {
+		result1 internal.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskStore) CreateReturnsOnCall(i int, result1 internal.Task, result2 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	if fake.createReturnsOnCall == nil {
+		fake.createReturnsOnCall = make(map[int]struct {
+			result1 internal.Task
+			result2 error
+		})
+	}
+	fake.createReturnsOnCall[i] = struct {
+		result1 internal.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskStore) Delete(arg1 context.Context, arg2 string) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeTaskStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeTaskStore) DeleteCalls(stub func(context.Context, string) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeTaskStore) DeleteArgsForCall(i int) (context.Context, string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskStore) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskStore) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskStore) Find(arg1 context.Context, arg2 string) (internal.Task, error) {
+	fake.findMutex.Lock()
+	ret, specificReturn := fake.findReturnsOnCall[len(fake.findArgsForCall)]
+	fake.findArgsForCall = append(fake.findArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.FindStub
+	fakeReturns := fake.findReturns
+	fake.recordInvocation("Find", []interface{}{arg1, arg2})
+	fake.findMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTaskStore) FindCallCount() int {
+	fake.findMutex.RLock()
+	defer fake.findMutex.RUnlock()
+	return len(fake.findArgsForCall)
+}
+
+func (fake *FakeTaskStore) FindCalls(stub func(context.Context, string) (internal.Task, error)) {
+	fake.findMutex.Lock()
+	defer fake.findMutex.Unlock()
+	fake.FindStub = stub
+}
+
+func (fake *FakeTaskStore) FindArgsForCall(i int) (context.Context, string) {
+	fake.findMutex.RLock()
+	defer fake.findMutex.RUnlock()
+	argsForCall := fake.findArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskStore) FindReturns(result1 internal.Task, result2 error) {
+	fake.findMutex.Lock()
+	defer fake.findMutex.Unlock()
+	fake.FindStub = nil
+	fake.findReturns = struct {
+		result1 internal.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskStore) FindReturnsOnCall(i int, result1 internal.Task, result2 error) {
+	fake.findMutex.Lock()
+	defer fake.findMutex.Unlock()
+	fake.FindStub = nil
+	if fake.findReturnsOnCall == nil {
+		fake.findReturnsOnCall = make(map[int]struct {
+			result1 internal.Task
+			result2 error
+		})
+	}
+	fake.findReturnsOnCall[i] = struct {
+		result1 internal.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskStore) FindMany(arg1 context.Context, arg2 []string) ([]internal.Task, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.findManyMutex.Lock()
+	ret, specificReturn := fake.findManyReturnsOnCall[len(fake.findManyArgsForCall)]
+	fake.findManyArgsForCall = append(fake.findManyArgsForCall, struct {
+		arg1 context.Context
+		arg2 []string
+	}{arg1, arg2Copy})
+	stub := fake.FindManyStub
+	fakeReturns := fake.findManyReturns
+	fake.recordInvocation("FindMany", []interface{}{arg1, arg2Copy})
+	fake.findManyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTaskStore) FindManyCallCount() int {
+	fake.findManyMutex.RLock()
+	defer fake.findManyMutex.RUnlock()
+	return len(fake.findManyArgsForCall)
+}
+
+func (fake *FakeTaskStore) FindManyCalls(stub func(context.Context, []string) ([]internal.Task, error)) {
+	fake.findManyMutex.Lock()
+	defer fake.findManyMutex.Unlock()
+	fake.FindManyStub = stub
+}
+
+func (fake *FakeTaskStore) FindManyArgsForCall(i int) (context.Context, []string) {
+	fake.findManyMutex.RLock()
+	defer fake.findManyMutex.RUnlock()
+	argsForCall := fake.findManyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskStore) FindManyReturns(result1 []internal.Task, result2 error) {
+	fake.findManyMutex.Lock()
+	defer fake.findManyMutex.Unlock()
+	fake.FindManyStub = nil
+	fake.findManyReturns = struct {
+		result1 []internal.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskStore) FindManyReturnsOnCall(i int, result1 []internal.Task, result2 error) {
+	fake.findManyMutex.Lock()
+	defer fake.findManyMutex.Unlock()
+	fake.FindManyStub = nil
+	if fake.findManyReturnsOnCall == nil {
+		fake.findManyReturnsOnCall = make(map[int]struct {
+			result1 []internal.Task
+			result2 error
+		})
+	}
+	fake.findManyReturnsOnCall[i] = struct {
+		result1 []internal.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskStore) Restore(arg1 context.Context, arg2 string) error {
+	fake.restoreMutex.Lock()
+	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RestoreStub
+	fakeReturns := fake.restoreReturns
+	fake.recordInvocation("Restore", []interface{}{arg1, arg2})
+	fake.restoreMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeTaskStore) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeTaskStore) RestoreCalls(stub func(context.Context, string) error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = stub
+}
+
+func (fake *FakeTaskStore) RestoreArgsForCall(i int) (context.Context, string) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskStore) RestoreReturns(result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskStore) RestoreReturnsOnCall(i int, result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	if fake.restoreReturnsOnCall == nil {
+		fake.restoreReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskStore) Trash(arg1 context.Context, arg2 internal.TrashParams) (internal.TrashResults, error) {
+	fake.trashMutex.Lock()
+	ret, specificReturn := fake.trashReturnsOnCall[len(fake.trashArgsForCall)]
+	fake.trashArgsForCall = append(fake.trashArgsForCall, struct {
+		arg1 context.Context
+		arg2 internal.TrashParams
+	}{arg1, arg2})
+	stub := fake.TrashStub
+	fakeReturns := fake.trashReturns
+	fake.recordInvocation("Trash", []interface{}{arg1, arg2})
+	fake.trashMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTaskStore) TrashCallCount() int {
+	fake.trashMutex.RLock()
+	defer fake.trashMutex.RUnlock()
+	return len(fake.trashArgsForCall)
+}
+
+func (fake *FakeTaskStore) TrashCalls(stub func(context.Context, internal.TrashParams) (internal.TrashResults, error)) {
+	fake.trashMutex.Lock()
+	defer fake.trashMutex.Unlock()
+	fake.TrashStub = stub
+}
+
+func (fake *FakeTaskStore) TrashArgsForCall(i int) (context.Context, internal.TrashParams) {
+	fake.trashMutex.RLock()
+	defer fake.trashMutex.RUnlock()
+	argsForCall := fake.trashArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskStore) TrashReturns(result1 internal.TrashResults, result2 error) {
+	fake.trashMutex.Lock()
+	defer fake.trashMutex.Unlock()
+	fake.TrashStub = nil
+	fake.trashReturns = struct {
+		result1 internal.TrashResults
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskStore) TrashReturnsOnCall(i int, result1 internal.TrashResults, result2 error) {
+	fake.trashMutex.Lock()
+	defer fake.trashMutex.Unlock()
+	fake.TrashStub = nil
+	if fake.trashReturnsOnCall == nil {
+		fake.trashReturnsOnCall = make(map[int]struct {
+			result1 internal.TrashResults
+			result2 error
+		})
+	}
+	fake.trashReturnsOnCall[i] = struct {
+		result1 internal.TrashResults
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskStore) Update(arg1 context.Context, arg2 string, arg3 string, arg4 internal.Priority, arg5 internal.Dates, arg6 bool) error {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 internal.Priority
+		arg5 internal.Dates
+		arg6 bool
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeTaskStore) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *FakeTaskStore) UpdateCalls(stub func(context.Context, string, string, internal.Priority, internal.Dates, bool) error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *FakeTaskStore) UpdateArgsForCall(i int) (context.Context, string, string, internal.Priority, internal.Dates, bool) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeTaskStore) UpdateReturns(result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskStore) UpdateReturnsOnCall(i int, result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.findMutex.RLock()
+	defer fake.findMutex.RUnlock()
+	fake.findManyMutex.RLock()
+	defer fake.findManyMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	fake.trashMutex.RLock()
+	defer fake.trashMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeTaskStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ rediscache.TaskStore = new(FakeTaskStore)
@@ -0,0 +1,97 @@
+package rediscache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// SearchableTask caches the results of searching Task records in Redis, all cached results are tagged so they
+// can be evicted together when the indexed records change.
+type SearchableTask struct {
+	cache *Cache
+	orig  SearchableTaskStore
+}
+
+//counterfeiter:generate -o rediscachetesting/searchable_task_store.gen.go . SearchableTaskStore
+
+// SearchableTaskStore defines the original store used for searching the Task records missing in the cache.
+type SearchableTaskStore interface {
+	Delete(ctx context.Context, id string) error
+	Index(ctx context.Context, task internal.Task) error
+	Search(ctx context.Context, args internal.SearchParams) (internal.SearchResults, error)
+}
+
+// NewSearchableTask instantiates the SearchableTask repository.
+func NewSearchableTask(cache *Cache, orig SearchableTaskStore) *SearchableTask {
+	return &SearchableTask{
+		cache: cache,
+		orig:  orig,
+	}
+}
+
+// Index indexes the task and evicts all cached search results.
+func (t *SearchableTask) Index(ctx context.Context, task internal.Task) error {
+	if err := t.orig.Index(ctx, task); err != nil {
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Index")
+	}
+
+	t.cache.invalidate(ctx, nil, searchTag)
+
+	return nil
+}
+
+// Delete removes the task from the index and evicts all cached search results.
+func (t *SearchableTask) Delete(ctx context.Context, id string) error {
+	if err := t.orig.Delete(ctx, id); err != nil {
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Delete")
+	}
+
+	t.cache.invalidate(ctx, nil, searchTag)
+
+	return nil
+}
+
+// Search returns the cached results, when missing the original store is used.
+func (t *SearchableTask) Search(ctx context.Context, args internal.SearchParams) (internal.SearchResults, error) {
+	key := newSearchableKey(args)
+
+	var res internal.SearchResults
+
+	if err := t.cache.get(ctx, key, &res); err == nil {
+		return res, nil
+	}
+
+	res, err := t.orig.Search(ctx, args)
+	if err != nil {
+		return internal.SearchResults{}, internal.WrapErrorf(err, internal.Code(err), "orig.Search")
+	}
+
+	t.cache.set(ctx, key, &res, 25*time.Second, searchTag)
+
+	return res, nil
+}
+
+func newSearchableKey(args internal.SearchParams) string {
+	var (
+		description string
+		priority    int8
+		isDone      bool
+	)
+
+	if args.Description != nil {
+		description = *args.Description
+	}
+
+	if args.Priority != nil {
+		priority = int8(*args.Priority)
+	}
+
+	if args.IsDone != nil {
+		isDone = *args.IsDone
+	}
+
+	return fmt.Sprintf("search:%s_%d_%t_%d_%d", description, priority, isDone, args.From, args.Size)
+}
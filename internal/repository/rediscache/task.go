@@ -0,0 +1,185 @@
+package rediscache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// Task caches Task records in Redis.
+type Task struct {
+	cache      *Cache
+	orig       TaskStore
+	expiration int64 // time.Duration, accessed atomically because it can change at runtime.
+	logger     *zap.Logger
+}
+
+//go:generate counterfeiter -generate
+
+//counterfeiter:generate -o rediscachetesting/task_store.gen.go . TaskStore
+
+// TaskStore defines the original store used for getting the Task records missing in the cache.
+type TaskStore interface {
+	Create(ctx context.Context, params internal.CreateParams) (internal.Task, error)
+	Delete(ctx context.Context, id string) error
+	Find(ctx context.Context, id string) (internal.Task, error)
+	FindMany(ctx context.Context, ids []string) ([]internal.Task, error)
+	Restore(ctx context.Context, id string) error
+	Trash(ctx context.Context, args internal.TrashParams) (internal.TrashResults, error)
+	Update(ctx context.Context, id, description string, priority internal.Priority, dates internal.Dates, isDone bool) error
+}
+
+// NewTask instantiates the Task repository.
+func NewTask(cache *Cache, orig TaskStore, logger *zap.Logger) *Task {
+	return &Task{
+		cache:      cache,
+		orig:       orig,
+		expiration: int64(10 * time.Minute),
+		logger:     logger,
+	}
+}
+
+// Expiration returns how long Tasks are cached.
+func (t *Task) Expiration() time.Duration {
+	return time.Duration(atomic.LoadInt64(&t.expiration))
+}
+
+// SetExpiration changes how long Tasks are cached, values already cached keep their expiration.
+func (t *Task) SetExpiration(d time.Duration) {
+	atomic.StoreInt64(&t.expiration, int64(d))
+}
+
+func (t *Task) Create(ctx context.Context, params internal.CreateParams) (internal.Task, error) {
+	task, err := t.orig.Create(ctx, params)
+	if err != nil {
+		return internal.Task{}, internal.WrapErrorf(err, internal.Code(err), "orig.Create")
+	}
+
+	// Write-Through Caching, cached search results may be missing the new task.
+
+	t.cache.invalidate(ctx, nil, searchTag)
+	t.cache.set(ctx, taskKey(task.ID), &task, t.Expiration())
+
+	return task, nil
+}
+
+func (t *Task) Delete(ctx context.Context, id string) error {
+	if err := t.orig.Delete(ctx, id); err != nil {
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Delete")
+	}
+
+	t.cache.invalidate(ctx, []string{taskKey(id)}, searchTag)
+
+	return nil
+}
+
+func (t *Task) Find(ctx context.Context, id string) (internal.Task, error) {
+	var res internal.Task
+
+	if err := t.cache.get(ctx, taskKey(id), &res); err == nil {
+		return res, nil
+	}
+
+	// Cache-Aside Caching
+
+	res, err := t.orig.Find(ctx, id)
+	if err != nil {
+		return res, internal.WrapErrorf(err, internal.Code(err), "orig.Find")
+	}
+
+	t.cache.set(ctx, taskKey(res.ID), &res, t.Expiration())
+
+	return res, nil
+}
+
+// FindMany returns the cached tasks, the missing ones are found using one call to the original store.
+func (t *Task) FindMany(ctx context.Context, ids []string) ([]internal.Task, error) {
+	keys := make([]string, len(ids))
+
+	for i, id := range ids {
+		keys[i] = taskKey(id)
+	}
+
+	cached := t.cache.getMany(ctx, keys)
+
+	var (
+		res     []internal.Task
+		missing []string
+	)
+
+	for i, id := range ids {
+		var task internal.Task
+
+		if val, ok := cached[keys[i]]; !ok || gob.NewDecoder(bytes.NewReader(val)).Decode(&task) != nil {
+			missing = append(missing, id)
+
+			continue
+		}
+
+		res = append(res, task)
+	}
+
+	if len(missing) == 0 {
+		return res, nil
+	}
+
+	// Cache-Aside Caching
+
+	found, err := t.orig.FindMany(ctx, missing)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.Code(err), "orig.FindMany")
+	}
+
+	for i := range found {
+		t.cache.set(ctx, taskKey(found[i].ID), &found[i], t.Expiration())
+	}
+
+	return append(res, found...), nil
+}
+
+func (t *Task) Restore(ctx context.Context, id string) error {
+	if err := t.orig.Restore(ctx, id); err != nil {
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Restore")
+	}
+
+	// Trashed tasks are never cached, the next "Find" call will populate it.
+
+	t.cache.invalidate(ctx, nil, searchTag)
+
+	return nil
+}
+
+// Trash is not cached, trashed tasks are rarely read.
+func (t *Task) Trash(ctx context.Context, args internal.TrashParams) (internal.TrashResults, error) {
+	res, err := t.orig.Trash(ctx, args)
+	if err != nil {
+		return internal.TrashResults{}, internal.WrapErrorf(err, internal.Code(err), "orig.Trash")
+	}
+
+	return res, nil
+}
+
+func (t *Task) Update(ctx context.Context, id string, description string, priority internal.Priority, dates internal.Dates, isDone bool) error {
+	if err := t.orig.Update(ctx, id, description, priority, dates, isDone); err != nil {
+		return internal.WrapErrorf(err, internal.Code(err), "orig.Update")
+	}
+
+	t.cache.invalidate(ctx, []string{taskKey(id)}, searchTag)
+
+	task, err := t.orig.Find(ctx, id)
+	if err != nil {
+		t.logger.Info("Update: couldn't refresh cached value", zap.Error(err))
+
+		return nil
+	}
+
+	t.cache.set(ctx, taskKey(task.ID), &task, t.Expiration())
+
+	return nil
+}
@@ -0,0 +1,29 @@
+-- Times are stored as nanoseconds since the Unix epoch in UTC, so they can be compared.
+CREATE TABLE tasks (
+  seq         INTEGER PRIMARY KEY,
+  id          TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL,
+  priority    TEXT NOT NULL DEFAULT 'none' CHECK (priority IN ('none', 'low', 'medium', 'high')),
+  start_date  INTEGER,
+  due_date    INTEGER,
+  done        INTEGER NOT NULL DEFAULT 0 CHECK (done IN (0, 1)),
+  deleted_at  INTEGER
+);
+
+CREATE INDEX tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- External content table indexing the descriptions, "seq" is used as rowid because it never changes.
+CREATE VIRTUAL TABLE tasks_fts USING fts5(description, content='tasks', content_rowid='seq');
+
+CREATE TRIGGER tasks_fts_insert AFTER INSERT ON tasks BEGIN
+  INSERT INTO tasks_fts (rowid, description) VALUES (new.seq, new.description);
+END;
+
+CREATE TRIGGER tasks_fts_delete AFTER DELETE ON tasks BEGIN
+  INSERT INTO tasks_fts (tasks_fts, rowid, description) VALUES ('delete', old.seq, old.description);
+END;
+
+CREATE TRIGGER tasks_fts_update AFTER UPDATE OF description ON tasks BEGIN
+  INSERT INTO tasks_fts (tasks_fts, rowid, description) VALUES ('delete', old.seq, old.description);
+  INSERT INTO tasks_fts (rowid, description) VALUES (new.seq, new.description);
+END;
@@ -0,0 +1,181 @@
+// Package sqlite implements the Task repositories using an embedded SQLite database, meant for single-binary
+// deployments where running PostgreSQL and a search engine is not worth it. The driver is pure Go, cgo is not
+// required.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	migrate "github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	sqlitedriver "modernc.org/sqlite"
+	sqlitelib "modernc.org/sqlite/lib"
+
+	"github.com/lrweck/todo/internal"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Open opens the database file, creating it when missing, and applies the pending migrations.
+func Open(ctx context.Context, filename string) (*sql.DB, error) {
+	if err := Migrate(filename); err != nil {
+		return nil, internal.WrapErrorf(err, internal.Code(err), "Migrate")
+	}
+
+	db, err := sql.Open("sqlite", dsn(filename))
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "sql.Open")
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+
+		return nil, internal.WrapErrorf(err, ErrorCode(err), "db.PingContext")
+	}
+
+	return db, nil
+}
+
+// Migrate applies the pending migrations to the database file.
+//
+// XXX: Unlike PostgreSQL there's no lock shared between processes, the file is meant to be used by one.
+func Migrate(filename string) error {
+	db, err := sql.Open("sqlite", dsn(filename))
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "sql.Open")
+	}
+
+	// XXX: Closed by golang-migrate, even when failing.
+	driver, err := migratesqlite.WithInstance(db, &migratesqlite.Config{})
+	if err != nil {
+		_ = db.Close()
+
+		return internal.WrapErrorf(err, ErrorCode(err), "sqlite.WithInstance")
+	}
+
+	src, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		_ = driver.Close()
+
+		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "iofs.New")
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite", driver)
+	if err != nil {
+		_ = driver.Close()
+
+		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "migrate.NewWithInstance")
+	}
+
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "migrate.Up")
+	}
+
+	return nil
+}
+
+// dsn waits for locks held by other connections instead of failing right away, and uses write-ahead logging so
+// readers don't block the writer.
+func dsn(filename string) string {
+	q := url.Values{}
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "foreign_keys(1)")
+
+	return fmt.Sprintf("file:%s?%s", filename, q.Encode())
+}
+
+// ErrorCode classifies the errors returned by the driver, see https://www.sqlite.org/rescode.html
+func ErrorCode(err error) internal.ErrorCode {
+	if errors.Is(err, sql.ErrNoRows) {
+		return internal.ErrCodeNotFound
+	}
+
+	if code, ok := internal.ContextCode(err); ok {
+		return code
+	}
+
+	var serr *sqlitedriver.Error
+	if !errors.As(err, &serr) {
+		return internal.ErrCodeUnknown
+	}
+
+	switch serr.Code() {
+	case sqlitelib.SQLITE_CONSTRAINT_UNIQUE, sqlitelib.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return internal.ErrCodeAlreadyExists
+	}
+
+	switch serr.Code() & 0xff { // Primary result code.
+	case sqlitelib.SQLITE_CONSTRAINT, sqlitelib.SQLITE_MISMATCH, sqlitelib.SQLITE_TOOBIG:
+		return internal.ErrCodeInvalidArgument
+	case sqlitelib.SQLITE_BUSY, sqlitelib.SQLITE_LOCKED:
+		return internal.ErrCodeConflict
+	case sqlitelib.SQLITE_FULL:
+		return internal.ErrCodeResourceExhausted
+	case sqlitelib.SQLITE_READONLY, sqlitelib.SQLITE_PERM, sqlitelib.SQLITE_AUTH:
+		return internal.ErrCodePermissionDenied
+	case sqlitelib.SQLITE_CANTOPEN, sqlitelib.SQLITE_IOERR:
+		return internal.ErrCodeUnavailable
+	case sqlitelib.SQLITE_INTERRUPT:
+		return internal.ErrCodeCanceled
+	}
+
+	return internal.ErrCodeUnknown
+}
+
+func convertPriority(p string) (internal.Priority, error) {
+	switch p {
+	case "none":
+		return internal.PriorityNone, nil
+	case "low":
+		return internal.PriorityLow, nil
+	case "medium":
+		return internal.PriorityMedium, nil
+	case "high":
+		return internal.PriorityHigh, nil
+	}
+
+	return internal.Priority(-1), fmt.Errorf("unknown value: %s", p)
+}
+
+func newPriority(p internal.Priority) string {
+	switch p {
+	case internal.PriorityNone:
+		return "none"
+	case internal.PriorityLow:
+		return "low"
+	case internal.PriorityMedium:
+		return "medium"
+	case internal.PriorityHigh:
+		return "high"
+	}
+
+	// XXX: The CHECK constraint fails with the following value.
+
+	return "invalid"
+}
+
+func newNullTime(t time.Time) sql.NullInt64 {
+	return sql.NullInt64{
+		Int64: t.UnixNano(),
+		Valid: !t.IsZero(),
+	}
+}
+
+func convertNullTime(t sql.NullInt64) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+
+	return time.Unix(0, t.Int64).UTC()
+}
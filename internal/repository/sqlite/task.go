@@ -0,0 +1,411 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/telemetry"
+)
+
+const taskColumns = "t.id, t.description, t.priority, t.start_date, t.due_date, t.done"
+
+// Task represents the repository used for interacting with Task records, it implements searching as well.
+type Task struct {
+	db      *sql.DB
+	metrics telemetry.RepositoryMetrics
+}
+
+// NewTask instantiates the Task repository.
+func NewTask(db *sql.DB) *Task {
+	return &Task{
+		db:      db,
+		metrics: telemetry.NewRepositoryMetrics("sqlite"),
+	}
+}
+
+func (t *Task) Create(ctx context.Context, params internal.CreateParams) (_ internal.Task, err error) {
+	ctx, span := t.start(ctx, "Task.Create")
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Create", time.Now(), &err)
+
+	// XXX: `ID` and `IsDone` make no sense when creating new records, that's why those are ignored.
+	// XXX: We are intentionally NOT SUPPORTING `SubTasks` and `Categories` JUST YET.
+
+	id := uuid.New()
+
+	if _, err := t.db.ExecContext(ctx,
+		`INSERT INTO tasks (id, description, priority, start_date, due_date) VALUES (?, ?, ?, ?, ?)`,
+		id.String(),
+		params.Description,
+		newPriority(params.Priority),
+		newNullTime(params.Dates.Start),
+		newNullTime(params.Dates.Due),
+	); err != nil {
+		return internal.Task{}, internal.WrapErrorf(err, ErrorCode(err), "insert task")
+	}
+
+	return internal.Task{
+		ID:          id.String(),
+		Description: params.Description,
+		Priority:    params.Priority,
+		Dates:       params.Dates,
+	}, nil
+}
+
+// Delete moves the existing record matching the id to the trash.
+func (t *Task) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := t.start(ctx, "Task.Delete")
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Delete", time.Now(), &err)
+
+	val, err := uuid.Parse(id)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid uuid")
+	}
+
+	return t.exec(ctx, "delete task", "task not found",
+		`UPDATE tasks SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`,
+		time.Now().UnixNano(), val.String())
+}
+
+// Find returns the requested task by searching its id.
+func (t *Task) Find(ctx context.Context, id string) (_ internal.Task, err error) {
+	ctx, span := t.start(ctx, "Task.Find")
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Find", time.Now(), &err)
+
+	val, err := uuid.Parse(id)
+	if err != nil {
+		return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid uuid")
+	}
+
+	row := t.db.QueryRowContext(ctx,
+		`SELECT `+taskColumns+` FROM tasks t WHERE t.id = ? AND t.deleted_at IS NULL`,
+		val.String())
+
+	res, err := scanTask(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeNotFound, "task not found")
+		}
+
+		return internal.Task{}, internal.WrapErrorf(err, internal.Code(err), "select task")
+	}
+
+	return res, nil
+}
+
+// FindMany returns the requested tasks using one query, the ones not found are not included.
+func (t *Task) FindMany(ctx context.Context, ids []string) (_ []internal.Task, err error) {
+	ctx, span := t.start(ctx, "Task.FindMany")
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.FindMany", time.Now(), &err)
+
+	params := make([]interface{}, len(ids))
+
+	for i, id := range ids {
+		val, err := uuid.Parse(id)
+		if err != nil {
+			return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid uuid")
+		}
+
+		params[i] = val.String()
+	}
+
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	rows, err := t.db.QueryContext(ctx,
+		`SELECT `+taskColumns+` FROM tasks t WHERE t.id IN (?`+strings.Repeat(", ?", len(params)-1)+`) AND t.deleted_at IS NULL`,
+		params...)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, ErrorCode(err), "select tasks")
+	}
+
+	defer rows.Close()
+
+	var tasks []internal.Task
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, internal.WrapErrorf(err, internal.Code(err), "scan task")
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, internal.WrapErrorf(err, ErrorCode(err), "rows")
+	}
+
+	return tasks, nil
+}
+
+// Purge permanently deletes the records moved to the trash before the received time, it returns the number of
+// deleted records.
+func (t *Task) Purge(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := t.start(ctx, "Task.Purge")
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Purge", time.Now(), &err)
+
+	res, err := t.db.ExecContext(ctx,
+		`DELETE FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?`,
+		before.UnixNano())
+	if err != nil {
+		return 0, internal.WrapErrorf(err, ErrorCode(err), "purge deleted tasks")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, internal.WrapErrorf(err, ErrorCode(err), "rows affected")
+	}
+
+	return count, nil
+}
+
+// Restore moves the existing record matching the id out of the trash.
+func (t *Task) Restore(ctx context.Context, id string) (err error) {
+	ctx, span := t.start(ctx, "Task.Restore")
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Restore", time.Now(), &err)
+
+	val, err := uuid.Parse(id)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid uuid")
+	}
+
+	return t.exec(ctx, "restore task", "task not found in trash",
+		`UPDATE tasks SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`,
+		val.String())
+}
+
+// Search returns the tasks matching the params that are not in the trash. The description is matched using
+// full-text search: every word must be present, words are matched by prefix and results are sorted by
+// relevance; otherwise by creation.
+func (t *Task) Search(ctx context.Context, args internal.SearchParams) (_ internal.SearchResults, err error) {
+	ctx, span := t.start(ctx, "Task.Search")
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Search", time.Now(), &err)
+
+	var (
+		from    = "tasks t"
+		where   = []string{"t.deleted_at IS NULL"}
+		orderBy = "t.seq"
+		params  []interface{}
+	)
+
+	if args.Description != nil {
+		if query := ftsQuery(*args.Description); query != "" {
+			from = "tasks_fts JOIN tasks t ON t.seq = tasks_fts.rowid"
+			where = append(where, "tasks_fts MATCH ?")
+			orderBy = "tasks_fts.rank"
+			params = append(params, query)
+		}
+	}
+
+	if args.Priority != nil {
+		where = append(where, "t.priority = ?")
+		params = append(params, newPriority(*args.Priority))
+	}
+
+	if args.IsDone != nil {
+		where = append(where, "t.done = ?")
+		params = append(params, *args.IsDone)
+	}
+
+	filter := " FROM " + from + " WHERE " + strings.Join(where, " AND ")
+
+	var total int64
+
+	if err := t.db.QueryRowContext(ctx, "SELECT COUNT(*)"+filter, params...).Scan(&total); err != nil {
+		return internal.SearchResults{}, internal.WrapErrorf(err, ErrorCode(err), "count tasks")
+	}
+
+	rows, err := t.db.QueryContext(ctx,
+		"SELECT "+taskColumns+filter+" ORDER BY "+orderBy+" LIMIT ? OFFSET ?",
+		append(params, args.Size, args.From)...)
+	if err != nil {
+		return internal.SearchResults{}, internal.WrapErrorf(err, ErrorCode(err), "select tasks")
+	}
+
+	defer rows.Close()
+
+	tasks := []internal.Task{}
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return internal.SearchResults{}, internal.WrapErrorf(err, internal.Code(err), "scan task")
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return internal.SearchResults{}, internal.WrapErrorf(err, ErrorCode(err), "rows")
+	}
+
+	return internal.SearchResults{
+		Tasks: tasks,
+		Total: total,
+	}, nil
+}
+
+// Trash returns the records moved to the trash, most recently deleted first.
+func (t *Task) Trash(ctx context.Context, args internal.TrashParams) (_ internal.TrashResults, err error) {
+	ctx, span := t.start(ctx, "Task.Trash")
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Trash", time.Now(), &err)
+
+	rows, err := t.db.QueryContext(ctx,
+		`SELECT `+taskColumns+`, t.deleted_at FROM tasks t
+		  WHERE t.deleted_at IS NOT NULL
+		  ORDER BY t.deleted_at DESC
+		  LIMIT ? OFFSET ?`,
+		args.Size, args.From)
+	if err != nil {
+		return internal.TrashResults{}, internal.WrapErrorf(err, ErrorCode(err), "select deleted tasks")
+	}
+
+	defer rows.Close()
+
+	tasks := []internal.TrashedTask{}
+
+	for rows.Next() {
+		var deletedAt sql.NullInt64
+
+		task, err := scanTask(rows, &deletedAt)
+		if err != nil {
+			return internal.TrashResults{}, internal.WrapErrorf(err, internal.Code(err), "scan task")
+		}
+
+		tasks = append(tasks, internal.TrashedTask{
+			Task:      task,
+			DeletedAt: convertNullTime(deletedAt),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return internal.TrashResults{}, internal.WrapErrorf(err, ErrorCode(err), "rows")
+	}
+
+	var total int64
+
+	if err := t.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM tasks WHERE deleted_at IS NOT NULL`).Scan(&total); err != nil {
+		return internal.TrashResults{}, internal.WrapErrorf(err, ErrorCode(err), "count deleted tasks")
+	}
+
+	return internal.TrashResults{
+		Tasks: tasks,
+		Total: total,
+	}, nil
+}
+
+// Update updates the existing record with new values.
+func (t *Task) Update(ctx context.Context, id string, description string, priority internal.Priority, dates internal.Dates, isDone bool) (err error) {
+	ctx, span := t.start(ctx, "Task.Update")
+	defer span.End()
+	defer t.metrics.Record(ctx, "Task.Update", time.Now(), &err)
+
+	val, err := uuid.Parse(id)
+	if err != nil {
+		return internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "invalid uuid")
+	}
+
+	return t.exec(ctx, "update task", "task not found",
+		`UPDATE tasks SET
+		   description = ?,
+		   priority    = ?,
+		   start_date  = ?,
+		   due_date    = ?,
+		   done        = ?
+		 WHERE id = ? AND deleted_at IS NULL`,
+		description,
+		newPriority(priority),
+		newNullTime(dates.Start),
+		newNullTime(dates.Due),
+		isDone,
+		val.String())
+}
+
+func (t *Task) start(ctx context.Context, name string) (context.Context, trace.Span) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("sqlite").Start(ctx, name)
+	span.SetAttributes(attribute.String("db.system", "sqlite"))
+
+	return ctx, span
+}
+
+// exec runs the statement, it fails with ErrCodeNotFound when no rows are affected.
+func (t *Task) exec(ctx context.Context, op, notFound, query string, args ...interface{}) error {
+	res, err := t.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return internal.WrapErrorf(err, ErrorCode(err), op)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return internal.WrapErrorf(err, ErrorCode(err), "rows affected")
+	}
+
+	if count == 0 {
+		return internal.NewErrorf(internal.ErrCodeNotFound, "%s", notFound)
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask scans the columns in taskColumns followed by the extra ones.
+func scanTask(row scanner, extra ...interface{}) (internal.Task, error) {
+	var (
+		res       internal.Task
+		priority  string
+		startDate sql.NullInt64
+		dueDate   sql.NullInt64
+	)
+
+	dest := append([]interface{}{&res.ID, &res.Description, &priority, &startDate, &dueDate, &res.IsDone}, extra...)
+
+	if err := row.Scan(dest...); err != nil {
+		return internal.Task{}, internal.WrapErrorf(err, ErrorCode(err), "row.Scan")
+	}
+
+	p, err := convertPriority(priority)
+	if err != nil {
+		return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "convert priority")
+	}
+
+	res.Priority = p
+	res.Dates = internal.Dates{
+		Start: convertNullTime(startDate),
+		Due:   convertNullTime(dueDate),
+	}
+
+	return res, nil
+}
+
+// ftsQuery converts the description into a FTS5 query matching all its words by prefix, each word is quoted
+// so characters with a special meaning in the query syntax are matched literally.
+func ftsQuery(description string) string {
+	words := strings.Fields(description)
+
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"*`
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,378 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/repository/sqlite"
+)
+
+func TestTask_Create(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Create: OK", func(t *testing.T) {
+		t.Parallel()
+
+		task, err := sqlite.NewTask(newDB(t)).Create(context.Background(),
+			internal.CreateParams{
+				Description: "test",
+				Priority:    internal.PriorityNone,
+				Dates:       internal.Dates{},
+			})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if task.ID == "" {
+			t.Fatalf("expected valid record, got empty value")
+		}
+	})
+
+	t.Run("Create: ERR", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := sqlite.NewTask(newDB(t)).Create(context.Background(),
+			internal.CreateParams{
+				Description: "",
+				Priority:    internal.Priority(-1),
+				Dates:       internal.Dates{},
+			})
+		if err == nil { // because of invalid priority
+			t.Fatalf("expected error, got no value")
+		}
+
+		if internal.Code(err) != internal.ErrCodeInvalidArgument {
+			t.Fatalf("expected invalid argument error, got %v", err)
+		}
+	})
+}
+
+func TestTask_Find(t *testing.T) {
+	t.Parallel()
+
+	store := sqlite.NewTask(newDB(t))
+
+	expected, err := store.Create(context.Background(), internal.CreateParams{
+		Description: "test",
+		Priority:    internal.PriorityHigh,
+		Dates: internal.Dates{
+			Start: time.Date(2021, 11, 27, 12, 0, 0, 0, time.UTC),
+			Due:   time.Date(2021, 11, 28, 12, 0, 0, 0, time.UTC),
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	actual, err := store.Find(context.Background(), expected.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Fatalf("expected result does not match: %s", diff)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		code  internal.ErrorCode
+	}{
+		{
+			"ERR: uuid",
+			"x",
+			internal.ErrCodeInvalidArgument,
+		},
+		{
+			"ERR: not found",
+			"44633fe3-b039-4fb3-a35f-a57fe3c906c7",
+			internal.ErrCodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := store.Find(context.Background(), tt.input)
+			if internal.Code(err) != tt.code {
+				t.Fatalf("expected %s error, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestTask_FindMany(t *testing.T) {
+	t.Parallel()
+
+	store := sqlite.NewTask(newDB(t))
+
+	first := createTask(t, store, "first")
+	second := createTask(t, store, "second")
+
+	// Missing tasks are not included.
+
+	actual, err := store.FindMany(context.Background(),
+		[]string{first.ID, "44633fe3-b039-4fb3-a35f-a57fe3c906c7", second.ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]internal.Task{first, second}, actual,
+		cmpopts.SortSlices(func(a, b internal.Task) bool { return a.ID < b.ID })); diff != "" {
+		t.Fatalf("expected result does not match: %s", diff)
+	}
+
+	if _, err := store.FindMany(context.Background(), []string{"x"}); internal.Code(err) != internal.ErrCodeInvalidArgument {
+		t.Fatalf("expected invalid argument error, got %v", err)
+	}
+}
+
+func TestTask_Update(t *testing.T) {
+	t.Parallel()
+
+	store := sqlite.NewTask(newDB(t))
+
+	created := createTask(t, store, "test")
+
+	due := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := store.Update(context.Background(), created.ID, "changed", internal.PriorityLow,
+		internal.Dates{Due: due}, true); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	actual, err := store.Find(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := internal.Task{
+		ID:          created.ID,
+		Description: "changed",
+		Priority:    internal.PriorityLow,
+		Dates:       internal.Dates{Due: due},
+		IsDone:      true,
+	}
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Fatalf("expected result does not match: %s", diff)
+	}
+
+	err = store.Update(context.Background(), "44633fe3-b039-4fb3-a35f-a57fe3c906c7", "x", internal.PriorityLow,
+		internal.Dates{}, false)
+	if internal.Code(err) != internal.ErrCodeNotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestTask_Trash(t *testing.T) {
+	t.Parallel()
+
+	store := sqlite.NewTask(newDB(t))
+
+	first := createTask(t, store, "first")
+	second := createTask(t, store, "second")
+
+	for _, task := range []internal.Task{first, second} {
+		if err := store.Delete(context.Background(), task.ID); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+	}
+
+	if err := store.Delete(context.Background(), first.ID); internal.Code(err) != internal.ErrCodeNotFound {
+		t.Fatalf("expected not found error deleting twice, got %v", err)
+	}
+
+	if _, err := store.Find(context.Background(), first.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected no rows error, got %v", err)
+	}
+
+	res, err := store.Trash(context.Background(), internal.TrashParams{Size: 10})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if res.Total != 2 || len(res.Tasks) != 2 || res.Tasks[0].Task.ID != second.ID || res.Tasks[0].DeletedAt.IsZero() {
+		t.Fatalf("expected most recently deleted first, got %+v", res)
+	}
+
+	if err := store.Restore(context.Background(), first.ID); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if err := store.Restore(context.Background(), first.ID); internal.Code(err) != internal.ErrCodeNotFound {
+		t.Fatalf("expected not found error restoring twice, got %v", err)
+	}
+
+	if _, err := store.Find(context.Background(), first.ID); err != nil {
+		t.Fatalf("expected restored task, got %s", err)
+	}
+
+	count, err := store.Purge(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected 1 purged task, got %d", count)
+	}
+
+	res, err = store.Trash(context.Background(), internal.TrashParams{Size: 10})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if res.Total != 0 || len(res.Tasks) != 0 {
+		t.Fatalf("expected empty trash, got %+v", res)
+	}
+}
+
+func TestTask_Search(t *testing.T) {
+	t.Parallel()
+
+	store := sqlite.NewTask(newDB(t))
+
+	groceries := createTask(t, store, "Buy groceries for the week")
+	report := createTask(t, store, "Write quarterly report")
+	_ = createTask(t, store, `Call "Bob" about the groceries`)
+	deleted := createTask(t, store, "Buy a new phone")
+
+	if err := store.Update(context.Background(), report.ID, report.Description, internal.PriorityHigh,
+		internal.Dates{}, true); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if err := store.Delete(context.Background(), deleted.ID); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	str := func(s string) *string { return &s }
+	priority := internal.PriorityHigh
+	done := false
+
+	tests := []struct {
+		name   string
+		input  internal.SearchParams
+		output []string
+		total  int64
+	}{
+		{
+			"OK: all",
+			internal.SearchParams{Size: 10},
+			[]string{"Buy groceries for the week", "Write quarterly report", `Call "Bob" about the groceries`},
+			3,
+		},
+		{
+			"OK: description prefix, case insensitive",
+			internal.SearchParams{Description: str("BUY groc"), Size: 10},
+			[]string{groceries.Description},
+			1,
+		},
+		{
+			"OK: description with quotes",
+			internal.SearchParams{Description: str(`"bob"`), Size: 10},
+			[]string{`Call "Bob" about the groceries`},
+			1,
+		},
+		{
+			"OK: priority",
+			internal.SearchParams{Priority: &priority, Size: 10},
+			[]string{report.Description},
+			1,
+		},
+		{
+			"OK: not done, second page",
+			internal.SearchParams{IsDone: &done, Size: 1, From: 1},
+			[]string{`Call "Bob" about the groceries`},
+			2,
+		},
+		{
+			"OK: no match",
+			internal.SearchParams{Description: str("phone"), Size: 10},
+			[]string{},
+			0,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := store.Search(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if res.Total != tt.total {
+				t.Fatalf("expected total %d, got %d", tt.total, res.Total)
+			}
+
+			actual := make([]string, len(res.Tasks))
+
+			for i, task := range res.Tasks {
+				actual[i] = task.Description
+			}
+
+			if diff := cmp.Diff(tt.output, actual); diff != "" {
+				t.Fatalf("expected result does not match: %s", diff)
+			}
+		})
+	}
+}
+
+func TestOpen_Migrated(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "todo.db")
+
+	for i := 0; i < 2; i++ {
+		db, err := sqlite.Open(context.Background(), filename)
+		if err != nil {
+			t.Fatalf("expected no error opening %d times, got %s", i+1, err)
+		}
+
+		_ = db.Close()
+	}
+}
+
+func createTask(t *testing.T, store *sqlite.Task, description string) internal.Task {
+	t.Helper()
+
+	task, err := store.Create(context.Background(), internal.CreateParams{
+		Description: description,
+		Priority:    internal.PriorityNone,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	return task
+}
+
+func newDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	db, err := sqlite.Open(context.Background(), filepath.Join(tb.TempDir(), "todo.db"))
+	if err != nil {
+		tb.Fatalf("Couldn't open DB: %s", err)
+	}
+
+	tb.Cleanup(func() {
+		db.Close()
+	})
+
+	return db
+}
@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	router "github.com/gorilla/mux"
+)
+
+//counterfeiter:generate -o resttesting/breaker_reporter.gen.go . BreakerReporter
+
+// BreakerReporter reports the state of the circuit breakers protecting the service dependencies.
+type BreakerReporter interface {
+	BreakerStates() map[string]string
+}
+
+//counterfeiter:generate -o resttesting/config_reporter.gen.go . ConfigReporter
+
+// ConfigReporter reports the effective configuration, with secrets redacted, and where each value comes from.
+type ConfigReporter interface {
+	Values() (map[string]string, error)
+	Sources() map[string]string
+}
+
+// AdminHandler exposes the internal state of the service for operators.
+type AdminHandler struct {
+	breakers BreakerReporter
+	config   ConfigReporter
+	checker  HealthChecker
+}
+
+// AdminOption defines the options used by AdminHandler.
+type AdminOption func(*AdminHandler)
+
+// WithConfigReporter exposes the effective configuration in "/admin/config".
+func WithConfigReporter(config ConfigReporter) AdminOption {
+	return func(a *AdminHandler) {
+		a.config = config
+	}
+}
+
+// WithHealthChecker exposes the detailed readiness of the dependencies, including errors, in "/admin/health".
+func WithHealthChecker(checker HealthChecker) AdminOption {
+	return func(a *AdminHandler) {
+		a.checker = checker
+	}
+}
+
+// NewAdminHandler ...
+func NewAdminHandler(breakers BreakerReporter, opts ...AdminOption) *AdminHandler {
+	a := &AdminHandler{
+		breakers: breakers,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// Register connects the handlers to the router.
+func (a *AdminHandler) Register(r *router.Router) {
+	r.HandleFunc("/admin/breakers", a.breakerStates).Methods(http.MethodGet)
+
+	if a.config != nil {
+		r.HandleFunc("/admin/config", a.effectiveConfig).Methods(http.MethodGet)
+	}
+
+	if a.checker != nil {
+		r.HandleFunc("/admin/health", a.health).Methods(http.MethodGet)
+	}
+}
+
+// BreakerStatesResponse defines the response returned back after reading the circuit breakers states.
+type BreakerStatesResponse struct {
+	Breakers map[string]string `json:"breakers"`
+}
+
+func (a *AdminHandler) breakerStates(w http.ResponseWriter, r *http.Request) {
+	renderResponse(r.Context(),
+		w,
+		&BreakerStatesResponse{
+			Breakers: a.breakers.BreakerStates(),
+		},
+		http.StatusOK)
+}
+
+// ConfigValue is a configuration value together with where it comes from.
+type ConfigValue struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// ConfigResponse defines the response returned back after reading the effective configuration.
+type ConfigResponse struct {
+	Config map[string]ConfigValue `json:"config"`
+}
+
+func (a *AdminHandler) effectiveConfig(w http.ResponseWriter, r *http.Request) {
+	values, err := a.config.Values()
+	if err != nil {
+		renderErrorResponse(w, r, "reading configuration failed", err)
+
+		return
+	}
+
+	sources := a.config.Sources()
+
+	res := ConfigResponse{
+		Config: make(map[string]ConfigValue, len(values)),
+	}
+
+	for key, val := range values {
+		res.Config[key] = ConfigValue{
+			Value:  val,
+			Source: sources[key],
+		}
+	}
+
+	renderResponse(r.Context(), w, &res, http.StatusOK)
+}
+
+// DependencyReport represents the detailed result of checking a dependency.
+type DependencyReport struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthReportResponse defines the response returned back after checking the dependencies in detail.
+type HealthReportResponse struct {
+	Status       string             `json:"status"`
+	Draining     bool               `json:"draining,omitempty"`
+	Dependencies []DependencyReport `json:"dependencies"`
+}
+
+func (a *AdminHandler) health(w http.ResponseWriter, r *http.Request) {
+	report := a.checker.Check(r.Context())
+
+	res := HealthReportResponse{
+		Status:       statusUp,
+		Draining:     report.Draining,
+		Dependencies: make([]DependencyReport, len(report.Dependencies)),
+	}
+
+	for i, dep := range report.Dependencies {
+		status := DependencyReport{
+			Name:      dep.Name,
+			Status:    statusUp,
+			LatencyMS: float64(dep.Latency) / float64(time.Millisecond),
+		}
+
+		if !dep.Healthy() {
+			status.Status = statusDown
+			status.Error = dep.Err.Error()
+		}
+
+		res.Dependencies[i] = status
+	}
+
+	if !report.Ready() {
+		res.Status = statusDown
+	}
+
+	renderResponse(r.Context(), w, &res, http.StatusOK)
+}
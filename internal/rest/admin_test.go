@@ -0,0 +1,133 @@
+package rest_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal/health"
+	"github.com/lrweck/todo/internal/rest"
+	"github.com/lrweck/todo/internal/rest/resttesting"
+)
+
+func TestAdmin_BreakerStates(t *testing.T) {
+	t.Parallel()
+
+	router := mux.NewRouter()
+
+	breakers := &resttesting.FakeBreakerReporter{}
+	breakers.BreakerStatesReturns(map[string]string{
+		"repo":   "closed",
+		"broker": "open",
+	})
+
+	rest.NewAdminHandler(breakers).Register(router)
+
+	res := doRequest(router, httptest.NewRequest(http.MethodGet, "/admin/breakers", nil))
+
+	assertResponse(t, res, test{
+		&rest.BreakerStatesResponse{
+			Breakers: map[string]string{
+				"repo":   "closed",
+				"broker": "open",
+			},
+		},
+		&rest.BreakerStatesResponse{},
+	})
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected code %d, actual %d", http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestAdmin_Config(t *testing.T) {
+	t.Parallel()
+
+	config := &resttesting.FakeConfigReporter{}
+	config.ValuesReturns(map[string]string{
+		"LOG_LEVEL":        "debug",
+		"CACHE_EXPIRATION": "10m0s",
+	}, nil)
+	config.SourcesReturns(map[string]string{
+		"LOG_LEVEL":        "file:/etc/todo/runtime.env",
+		"CACHE_EXPIRATION": "default",
+	})
+
+	router := mux.NewRouter()
+
+	rest.NewAdminHandler(&resttesting.FakeBreakerReporter{}, rest.WithConfigReporter(config)).Register(router)
+
+	res := doRequest(router, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+
+	assertResponse(t, res, test{
+		&rest.ConfigResponse{
+			Config: map[string]rest.ConfigValue{
+				"LOG_LEVEL": {
+					Value:  "debug",
+					Source: "file:/etc/todo/runtime.env",
+				},
+				"CACHE_EXPIRATION": {
+					Value:  "10m0s",
+					Source: "default",
+				},
+			},
+		},
+		&rest.ConfigResponse{},
+	})
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected code %d, actual %d", http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestAdmin_Config_NotRegistered(t *testing.T) {
+	t.Parallel()
+
+	router := mux.NewRouter()
+
+	rest.NewAdminHandler(&resttesting.FakeBreakerReporter{}).Register(router)
+
+	res := doRequest(router, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected code %d, actual %d", http.StatusNotFound, res.StatusCode)
+	}
+}
+
+func TestAdmin_Health(t *testing.T) {
+	t.Parallel()
+
+	checker := &resttesting.FakeHealthChecker{}
+	checker.CheckReturns(health.Report{
+		Dependencies: []health.Status{
+			{Name: "postgresql", Latency: time.Millisecond},
+			{Name: "redis", Latency: 500 * time.Microsecond, Err: errors.New("connection refused")},
+		},
+	})
+
+	router := mux.NewRouter()
+
+	rest.NewAdminHandler(&resttesting.FakeBreakerReporter{}, rest.WithHealthChecker(checker)).Register(router)
+
+	res := doRequest(router, httptest.NewRequest(http.MethodGet, "/admin/health", nil))
+
+	assertResponse(t, res, test{
+		&rest.HealthReportResponse{
+			Status: "down",
+			Dependencies: []rest.DependencyReport{
+				{Name: "postgresql", Status: "up", LatencyMS: 1},
+				{Name: "redis", Status: "down", LatencyMS: 0.5, Error: "connection refused"},
+			},
+		},
+		&rest.HealthReportResponse{},
+	})
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected code %d, actual %d", http.StatusOK, res.StatusCode)
+	}
+}
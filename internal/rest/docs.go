@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"embed"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+//go:embed docs
+var docsFS embed.FS
+
+// registerDocs connects the handlers serving the interactive documentation, the assets are embedded because some
+// environments have no access to CDNs.
+func registerDocs(r *mux.Router) {
+	r.HandleFunc("/docs", docsPage("docs/swagger.html")).Methods(http.MethodGet)
+	r.HandleFunc("/redoc", docsPage("docs/redoc.html")).Methods(http.MethodGet)
+
+	r.PathPrefix("/docs/static/").Handler(http.FileServer(http.FS(docsFS))).Methods(http.MethodGet)
+}
+
+func docsPage(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := docsFS.ReadFile(name)
+		if err != nil {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		_, _ = w.Write(data)
+	}
+}
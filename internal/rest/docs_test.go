@@ -0,0 +1,152 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal/rest"
+)
+
+func TestRegisterOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	router := mux.NewRouter()
+
+	rest.RegisterOpenAPI(router,
+		rest.WithContact(openapi3.Contact{Name: "ToDo Team", Email: "todo@example.com"}),
+		rest.WithServers(&openapi3.Server{URL: "https://todo.example.com"}),
+		rest.WithSecurityScheme("bearerAuth", openapi3.NewJWTSecurityScheme()))
+
+	tests := []struct {
+		name                string
+		target              string
+		expectedStatus      int
+		expectedContentType string
+	}{
+		{
+			"OK: swagger ui",
+			"/docs",
+			http.StatusOK,
+			"text/html; charset=utf-8",
+		},
+		{
+			"OK: swagger ui assets",
+			"/docs/static/swagger-ui.css",
+			http.StatusOK,
+			"text/css; charset=utf-8",
+		},
+		{
+			"OK: redoc",
+			"/redoc",
+			http.StatusOK,
+			"text/html; charset=utf-8",
+		},
+		{
+			"OK: redoc assets",
+			"/docs/static/redoc.standalone.js",
+			http.StatusOK,
+			"text/javascript; charset=utf-8",
+		},
+		{
+			"OK: json",
+			"/openapi3.json",
+			http.StatusOK,
+			"application/json",
+		},
+		{
+			"OK: v2 yaml",
+			"/v2/openapi3.yaml",
+			http.StatusOK,
+			"application/x-yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := doRequest(router, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			defer res.Body.Close()
+
+			if tt.expectedStatus != res.StatusCode {
+				t.Fatalf("expected code %d, actual %d", tt.expectedStatus, res.StatusCode)
+			}
+
+			if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, tt.expectedContentType) {
+				t.Fatalf("expected content type %s, actual %s", tt.expectedContentType, ct)
+			}
+		})
+	}
+
+	t.Run("OK: options", func(t *testing.T) {
+		t.Parallel()
+
+		res := doRequest(router, httptest.NewRequest(http.MethodGet, "/openapi3.json", nil))
+		defer res.Body.Close()
+
+		var swagger openapi3.T
+		if err := json.NewDecoder(res.Body).Decode(&swagger); err != nil {
+			t.Fatalf("couldn't decode %s", err)
+		}
+
+		if swagger.Info.Contact.Email != "todo@example.com" {
+			t.Fatalf("expected contact, got %#v", swagger.Info.Contact)
+		}
+
+		if len(swagger.Servers) != 1 || swagger.Servers[0].URL != "https://todo.example.com" {
+			t.Fatalf("expected servers, got %#v", swagger.Servers)
+		}
+
+		if _, ok := swagger.Components.SecuritySchemes["bearerAuth"]; !ok || len(swagger.Security) != 1 {
+			t.Fatalf("expected security scheme, got %#v", swagger.Components.SecuritySchemes)
+		}
+	})
+}
+
+func TestWithAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"OK: port only",
+			":9234",
+			"http://127.0.0.1:9234",
+		},
+		{
+			"OK: unspecified",
+			"0.0.0.0:8080",
+			"http://127.0.0.1:8080",
+		},
+		{
+			"OK: host",
+			"todo.internal:80",
+			"http://todo.internal:80",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			swagger := rest.NewOpenAPI3(rest.WithAddress(tt.input))
+
+			if len(swagger.Servers) != 1 || swagger.Servers[0].URL != tt.expected {
+				t.Fatalf("expected server %s, got %#v", tt.expected, swagger.Servers)
+			}
+		})
+	}
+}
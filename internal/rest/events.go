@@ -0,0 +1,349 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	router "github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/events"
+)
+
+const (
+	lastEventIDHeader = "Last-Event-ID"
+	lastEventIDQuery  = "last_event_id"
+	eventReset        = "reset"
+	wsWriteTimeout    = 10 * time.Second
+)
+
+// TaskEventsHub defines the methods used for receiving the Task events.
+type TaskEventsHub interface {
+	Subscribe(lastID string, buffer int) (*events.Subscription, []internal.TaskEvent, bool)
+}
+
+// EventAuthorizer indicates whether the caller making the request is allowed to receive the event.
+type EventAuthorizer func(r *http.Request, evt internal.TaskEvent) bool
+
+// EventsHandler streams the Task events using Server-Sent Events and WebSockets.
+type EventsHandler struct {
+	hub       TaskEventsHub
+	heartbeat time.Duration
+	buffer    int
+	authorize EventAuthorizer
+	upgrader  websocket.Upgrader
+}
+
+// EventsHandlerOption defines the options used by EventsHandler.
+type EventsHandlerOption func(*EventsHandler)
+
+// WithHeartbeat indicates how often idle streams send a heartbeat, defaults to 15 seconds.
+func WithHeartbeat(d time.Duration) EventsHandlerOption {
+	return func(e *EventsHandler) {
+		e.heartbeat = d
+	}
+}
+
+// WithEventsBuffer indicates how many events can be pending to be sent to a client, clients falling further
+// behind are disconnected and expected to resume their stream. Defaults to 64.
+func WithEventsBuffer(size int) EventsHandlerOption {
+	return func(e *EventsHandler) {
+		e.buffer = size
+	}
+}
+
+// WithEventAuthorizer filters the events sent to each caller, by default all events are sent.
+//
+// XXX: Requests are not authenticated yet, this is the hook for enforcing the caller's permissions once they are.
+func WithEventAuthorizer(authorize EventAuthorizer) EventsHandlerOption {
+	return func(e *EventsHandler) {
+		e.authorize = authorize
+	}
+}
+
+// WithCheckOrigin defines the origins allowed to open WebSockets, by default only same-origin requests are.
+func WithCheckOrigin(check func(r *http.Request) bool) EventsHandlerOption {
+	return func(e *EventsHandler) {
+		e.upgrader.CheckOrigin = check
+	}
+}
+
+// NewEventsHandler ...
+func NewEventsHandler(hub TaskEventsHub, opts ...EventsHandlerOption) *EventsHandler {
+	res := &EventsHandler{
+		hub:       hub,
+		heartbeat: 15 * time.Second,
+		buffer:    64,
+		authorize: func(*http.Request, internal.TaskEvent) bool { return true },
+	}
+
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	return res
+}
+
+// Register connects the handlers to the router.
+func (e *EventsHandler) Register(r *router.Router) {
+	r.HandleFunc("/events", e.sse).Methods(http.MethodGet)
+	r.HandleFunc("/events/ws", e.websocket).Methods(http.MethodGet)
+}
+
+// TaskEvent defines the event sent when a Task changes, deleted events only include the Task ID.
+type TaskEvent struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	TaskID string `json:"task_id"`
+	Task   *Task  `json:"task,omitempty"`
+}
+
+// NewTaskEvent converts the received domain type to a rest type.
+func NewTaskEvent(evt internal.TaskEvent) TaskEvent {
+	res := TaskEvent{
+		ID:     evt.ID,
+		Type:   "task." + string(evt.Type),
+		TaskID: evt.Task.ID,
+	}
+
+	if evt.Type != internal.TaskEventDeleted {
+		res.Task = &Task{
+			ID:          evt.Task.ID,
+			Description: evt.Task.Description,
+			Priority:    NewPriority(evt.Task.Priority),
+			Dates:       NewDates(evt.Task.Dates),
+			IsDone:      evt.Task.IsDone,
+		}
+	}
+
+	return res
+}
+
+// stream holds the state shared by both transports.
+type stream struct {
+	sub      *events.Subscription
+	replay   []internal.TaskEvent
+	complete bool
+	filter   func(internal.TaskEvent) bool
+}
+
+// subscribe parses the filters and subscribes to the hub, when the returned bool is false the error response
+// was already rendered.
+func (e *EventsHandler) subscribe(w http.ResponseWriter, r *http.Request, lastID string) (stream, bool) {
+	params, err := eventsSearchParams(r)
+	if err != nil {
+		renderErrorResponse(w, r, "invalid request", err)
+
+		return stream{}, false
+	}
+
+	sub, replay, complete := e.hub.Subscribe(lastID, e.buffer)
+
+	return stream{
+		sub:      sub,
+		replay:   replay,
+		complete: complete,
+		filter: func(evt internal.TaskEvent) bool {
+			// XXX: Deleted events can't be matched against the params, clients discard unknown IDs.
+			if evt.Type != internal.TaskEventDeleted && !params.Matches(evt.Task) {
+				return false
+			}
+
+			return e.authorize(r, evt)
+		},
+	}, true
+}
+
+// sse streams the events using Server-Sent Events, clients resume their stream using the "Last-Event-ID"
+// header sent automatically by browsers when reconnecting.
+func (e *EventsHandler) sse(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		renderErrorResponse(w, r, "streaming unsupported",
+			internal.NewErrorf(internal.ErrCodeUnknown, "http.Flusher not implemented"))
+
+		return
+	}
+
+	lastID := r.Header.Get(lastEventIDHeader)
+	if lastID == "" {
+		lastID = r.URL.Query().Get(lastEventIDQuery)
+	}
+
+	s, ok := e.subscribe(w, r, lastID)
+	if !ok {
+		return
+	}
+
+	defer s.sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !s.complete {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", eventReset)
+	}
+
+	for _, evt := range s.replay {
+		if s.filter(evt) {
+			writeSSE(w, evt)
+		}
+	}
+
+	flusher.Flush()
+
+	ticker := time.NewTicker(e.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case evt, ok := <-s.sub.Events():
+			if !ok {
+				// XXX: Dropped for falling behind, the client reconnects and resumes from the last event.
+				return
+			}
+
+			if !s.filter(evt) {
+				continue
+			}
+
+			writeSSE(w, evt)
+		}
+
+		flusher.Flush()
+	}
+}
+
+func writeSSE(w http.ResponseWriter, evt internal.TaskEvent) {
+	res := NewTaskEvent(evt)
+
+	data, _ := json.Marshal(res)
+
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", res.ID, res.Type, data)
+}
+
+// websocket streams the events using WebSockets, clients resume their stream using the "last_event_id" query
+// parameter because browsers can't set headers when opening WebSockets.
+func (e *EventsHandler) websocket(w http.ResponseWriter, r *http.Request) {
+	s, ok := e.subscribe(w, r, r.URL.Query().Get(lastEventIDQuery))
+	if !ok {
+		return
+	}
+
+	defer s.sub.Close()
+
+	conn, err := e.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // XXX: Upgrade already replied with an error.
+	}
+
+	defer conn.Close()
+
+	// XXX: Messages sent by clients are ignored, reading is needed for processing control messages and
+	// detecting closed connections.
+	closed := make(chan struct{})
+
+	go func() {
+		defer close(closed)
+
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	write := func(v interface{}) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+
+		return conn.WriteJSON(v)
+	}
+
+	if !s.complete {
+		if err := write(TaskEvent{Type: eventReset}); err != nil {
+			return
+		}
+	}
+
+	for _, evt := range s.replay {
+		if !s.filter(evt) {
+			continue
+		}
+
+		if err := write(NewTaskEvent(evt)); err != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(e.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		case evt, ok := <-s.sub.Events():
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"),
+					time.Now().Add(wsWriteTimeout))
+
+				return
+			}
+
+			if !s.filter(evt) {
+				continue
+			}
+
+			if err := write(NewTaskEvent(evt)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// eventsSearchParams parses the optional query parameters used for filtering the events.
+func eventsSearchParams(r *http.Request) (internal.SearchParams, error) {
+	var res internal.SearchParams
+
+	query := r.URL.Query()
+
+	if val := query.Get("description"); val != "" {
+		res.Description = &val
+	}
+
+	if val := query.Get("priority"); val != "" {
+		priority := Priority(val)
+		if err := priority.Validate(); err != nil {
+			return internal.SearchParams{}, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "priority")
+		}
+
+		converted := priority.Convert()
+		res.Priority = &converted
+	}
+
+	if val := query.Get("is_done"); val != "" {
+		isDone, err := strconv.ParseBool(val)
+		if err != nil {
+			return internal.SearchParams{}, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "is_done")
+		}
+
+		res.IsDone = &isDone
+	}
+
+	return res, nil
+}
@@ -0,0 +1,174 @@
+package rest_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/events"
+	"github.com/lrweck/todo/internal/rest"
+)
+
+func TestEventsHandler_SSE(t *testing.T) {
+	t.Parallel()
+
+	hub := events.NewHub(10)
+
+	created := hub.Publish(internal.TaskEvent{
+		Type: internal.TaskEventCreated,
+		Task: internal.Task{ID: "1", Description: "groceries", Priority: internal.PriorityHigh},
+	})
+
+	srv := newEventsServer(hub)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/events?priority=high", nil)
+	req.Header.Set("Last-Event-ID", created.ID[:len(created.ID)-1]+"0")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	defer res.Body.Close()
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %s", ct)
+	}
+
+	// Filtered out by priority.
+	hub.Publish(internal.TaskEvent{
+		Type: internal.TaskEventUpdated,
+		Task: internal.Task{ID: "2", Priority: internal.PriorityLow},
+	})
+
+	deleted := hub.Publish(internal.TaskEvent{
+		Type: internal.TaskEventDeleted,
+		Task: internal.Task{ID: "2"},
+	})
+
+	expected := []string{
+		"id: " + created.ID,
+		"event: task.created",
+		`data: {"id":"` + created.ID + `","type":"task.created","task_id":"1","task":{"id":"1","description":"groceries","priority":"high","dates":{"start":"0001-01-01T00:00:00Z","due":"0001-01-01T00:00:00Z"},"is_done":false}}`,
+		"",
+		"id: " + deleted.ID,
+		"event: task.deleted",
+		`data: {"id":"` + deleted.ID + `","type":"task.deleted","task_id":"2"}`,
+		"",
+	}
+
+	actual := readLines(t, bufio.NewReader(res.Body), len(expected))
+
+	if !cmp.Equal(expected, actual) {
+		t.Fatalf("expected results don't match: %s", cmp.Diff(expected, actual))
+	}
+}
+
+func TestEventsHandler_SSE_Reset(t *testing.T) {
+	t.Parallel()
+
+	srv := newEventsServer(events.NewHub(10))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/events?last_event_id=unknown-1") //nolint:noctx
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	defer res.Body.Close()
+
+	expected := []string{"event: reset", "data: {}", ""}
+
+	if actual := readLines(t, bufio.NewReader(res.Body), len(expected)); !cmp.Equal(expected, actual) {
+		t.Fatalf("expected results don't match: %s", cmp.Diff(expected, actual))
+	}
+}
+
+func TestEventsHandler_SSE_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	router := mux.NewRouter()
+	rest.NewEventsHandler(events.NewHub(10)).Register(router)
+
+	res := doRequest(router, httptest.NewRequest(http.MethodGet, "/events?is_done=maybe", nil))
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected code %d, actual %d", http.StatusBadRequest, res.StatusCode)
+	}
+}
+
+func TestEventsHandler_WebSocket(t *testing.T) {
+	t.Parallel()
+
+	hub := events.NewHub(10)
+
+	srv := newEventsServer(hub, rest.WithEventAuthorizer(func(_ *http.Request, evt internal.TaskEvent) bool {
+		return evt.Task.ID != "forbidden"
+	}))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/events/ws", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	defer conn.Close()
+
+	// The subscription happens before upgrading, publishing once connected is not racy.
+	hub.Publish(internal.TaskEvent{Type: internal.TaskEventDeleted, Task: internal.Task{ID: "forbidden"}})
+	restored := hub.Publish(internal.TaskEvent{Type: internal.TaskEventRestored, Task: internal.Task{ID: "1"}})
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var actual rest.TaskEvent
+	if err := conn.ReadJSON(&actual); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := rest.TaskEvent{
+		ID:     restored.ID,
+		Type:   "task.restored",
+		TaskID: "1",
+		Task: &rest.Task{
+			ID:       "1",
+			Priority: "none",
+		},
+	}
+
+	if !cmp.Equal(expected, actual) {
+		t.Fatalf("expected results don't match: %s", cmp.Diff(expected, actual))
+	}
+}
+
+func newEventsServer(hub *events.Hub, opts ...rest.EventsHandlerOption) *httptest.Server {
+	router := mux.NewRouter()
+	router.Use(rest.NewMetricsMiddleware())
+
+	rest.NewEventsHandler(hub, opts...).Register(router)
+
+	return httptest.NewServer(router)
+}
+
+func readLines(t *testing.T, r *bufio.Reader, n int) []string {
+	t.Helper()
+
+	res := make([]string, 0, n)
+
+	for len(res) < n {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		res = append(res, strings.TrimSuffix(line, "\n"))
+	}
+
+	return res
+}
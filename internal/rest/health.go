@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	router "github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal/health"
+)
+
+//counterfeiter:generate -o resttesting/health_checker.gen.go . HealthChecker
+
+// HealthChecker defines the methods used for determining the readiness of the service.
+type HealthChecker interface {
+	Check(ctx context.Context) health.Report
+}
+
+// HealthHandler exposes the liveness and readiness of the service, used by orchestrators.
+type HealthHandler struct {
+	checker  HealthChecker
+	breakers BreakerReporter
+}
+
+// NewHealthHandler instantiates the HealthHandler, breakers is optional.
+func NewHealthHandler(checker HealthChecker, breakers BreakerReporter) *HealthHandler {
+	return &HealthHandler{
+		checker:  checker,
+		breakers: breakers,
+	}
+}
+
+// Register connects the handlers to the router.
+func (h *HealthHandler) Register(r *router.Router) {
+	r.HandleFunc("/healthz", h.live).Methods(http.MethodGet)
+	r.HandleFunc("/readyz", h.ready).Methods(http.MethodGet)
+}
+
+// HealthResponse defines the response returned back after checking the liveness of the service.
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// DependencyStatus represents the result of checking a dependency. The error is not included because the
+// endpoint is public, see AdminHandler for the details.
+type DependencyStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// ReadinessResponse defines the response returned back after checking the readiness of the service.
+type ReadinessResponse struct {
+	Status       string             `json:"status"`
+	Draining     bool               `json:"draining,omitempty"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+	Breakers     map[string]string  `json:"breakers,omitempty"`
+}
+
+const (
+	statusUp   = "up"
+	statusDown = "down"
+)
+
+func (h *HealthHandler) live(w http.ResponseWriter, r *http.Request) {
+	renderResponse(r.Context(), w, &HealthResponse{Status: statusUp}, http.StatusOK)
+}
+
+func (h *HealthHandler) ready(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Check(r.Context())
+
+	resp := ReadinessResponse{
+		Status:       statusUp,
+		Draining:     report.Draining,
+		Dependencies: make([]DependencyStatus, len(report.Dependencies)),
+	}
+
+	for i, dep := range report.Dependencies {
+		status := DependencyStatus{
+			Name:   dep.Name,
+			Status: statusUp,
+		}
+
+		if !dep.Healthy() {
+			status.Status = statusDown
+		}
+
+		resp.Dependencies[i] = status
+	}
+
+	if h.breakers != nil {
+		resp.Breakers = h.breakers.BreakerStates()
+	}
+
+	code := http.StatusOK
+
+	if !report.Ready() {
+		resp.Status = statusDown
+		code = http.StatusServiceUnavailable
+	}
+
+	renderResponse(r.Context(), w, &resp, code)
+}
@@ -0,0 +1,138 @@
+package rest_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal/health"
+	"github.com/lrweck/todo/internal/rest"
+	"github.com/lrweck/todo/internal/rest/resttesting"
+)
+
+func TestHealth_Live(t *testing.T) {
+	t.Parallel()
+
+	router := mux.NewRouter()
+
+	rest.NewHealthHandler(&resttesting.FakeHealthChecker{}, nil).Register(router)
+
+	res := doRequest(router, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assertResponse(t, res, test{
+		&rest.HealthResponse{Status: "up"},
+		&rest.HealthResponse{},
+	})
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected code %d, actual %d", http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestHealth_Ready(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		expectedStatus int
+		expected       interface{}
+		target         interface{}
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(*resttesting.FakeHealthChecker, *resttesting.FakeBreakerReporter)
+		output output
+	}{
+		{
+			"OK: 200",
+			func(c *resttesting.FakeHealthChecker, b *resttesting.FakeBreakerReporter) {
+				c.CheckReturns(health.Report{
+					Dependencies: []health.Status{
+						{Name: "postgresql", Latency: 2 * time.Millisecond},
+					},
+				})
+
+				b.BreakerStatesReturns(map[string]string{"repo": "closed"})
+			},
+			output{
+				http.StatusOK,
+				&rest.ReadinessResponse{
+					Status: "up",
+					Dependencies: []rest.DependencyStatus{
+						{Name: "postgresql", Status: "up"},
+					},
+					Breakers: map[string]string{"repo": "closed"},
+				},
+				&rest.ReadinessResponse{},
+			},
+		},
+		{
+			"ERR: 503 dependency down",
+			func(c *resttesting.FakeHealthChecker, _ *resttesting.FakeBreakerReporter) {
+				c.CheckReturns(health.Report{
+					Dependencies: []health.Status{
+						{Name: "postgresql", Latency: time.Millisecond},
+						{Name: "redis", Latency: 500 * time.Microsecond, Err: errors.New("connection refused")},
+					},
+				})
+			},
+			output{
+				http.StatusServiceUnavailable,
+				&rest.ReadinessResponse{
+					Status: "down",
+					Dependencies: []rest.DependencyStatus{
+						{Name: "postgresql", Status: "up"},
+						{Name: "redis", Status: "down"}, // Errors are only reported to operators.
+					},
+				},
+				&rest.ReadinessResponse{},
+			},
+		},
+		{
+			"ERR: 503 draining",
+			func(c *resttesting.FakeHealthChecker, _ *resttesting.FakeBreakerReporter) {
+				c.CheckReturns(health.Report{
+					Draining:     true,
+					Dependencies: []health.Status{},
+				})
+			},
+			output{
+				http.StatusServiceUnavailable,
+				&rest.ReadinessResponse{
+					Status:       "down",
+					Draining:     true,
+					Dependencies: []rest.DependencyStatus{},
+				},
+				&rest.ReadinessResponse{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := mux.NewRouter()
+
+			checker := &resttesting.FakeHealthChecker{}
+			breakers := &resttesting.FakeBreakerReporter{}
+			tt.setup(checker, breakers)
+
+			rest.NewHealthHandler(checker, breakers).Register(router)
+
+			res := doRequest(router, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assertResponse(t, res, test{tt.output.expected, tt.output.target})
+
+			if tt.output.expectedStatus != res.StatusCode {
+				t.Fatalf("expected code %d, actual %d", tt.output.expectedStatus, res.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyMaxLength   = 255
+)
+
+//counterfeiter:generate -o resttesting/idempotency_store.gen.go . IdempotencyStore
+
+// IdempotencyStore defines the methods used for storing idempotency keys.
+type IdempotencyStore interface {
+	Lock(ctx context.Context, key, fingerprint string, until time.Time) (internal.IdempotencyRecord, bool, error)
+	Save(ctx context.Context, key, owner string, resp internal.IdempotentResponse, until time.Time) error
+	Release(ctx context.Context, key, owner string) error
+}
+
+type idempotency struct {
+	store       IdempotencyStore
+	ttl         time.Duration
+	lockTimeout time.Duration
+	principal   func(r *http.Request) string
+}
+
+// IdempotencyOption defines the options used by the idempotency middleware.
+type IdempotencyOption func(*idempotency)
+
+// WithIdempotencyTTL indicates how long responses are replayed, defaults to 24 hours.
+func WithIdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(i *idempotency) {
+		i.ttl = ttl
+	}
+}
+
+// WithIdempotencyLockTimeout indicates how long a key is held by a request still in flight, after that the key
+// can be claimed again; it is meant to recover from crashes. Defaults to 1 minute.
+func WithIdempotencyLockTimeout(timeout time.Duration) IdempotencyOption {
+	return func(i *idempotency) {
+		i.lockTimeout = timeout
+	}
+}
+
+// WithIdempotencyPrincipal indicates how the client sending the request is identified, like the authenticated
+// user, keys are scoped by it so clients can't replay the responses of others. By default keys are only scoped
+// by route.
+func WithIdempotencyPrincipal(fn func(r *http.Request) string) IdempotencyOption {
+	return func(i *idempotency) {
+		i.principal = fn
+	}
+}
+
+// NewIdempotencyMiddleware returns the middleware replaying the responses of POST, PATCH and DELETE requests
+// retried with the same "Idempotency-Key" header. Using the key with a different request is rejected with 422,
+// and using it while the original request is in flight is rejected with 409.
+func NewIdempotencyMiddleware(store IdempotencyStore, opts ...IdempotencyOption) mux.MiddlewareFunc {
+	i := &idempotency{
+		store:       store,
+		ttl:         24 * time.Hour,
+		lockTimeout: time.Minute,
+		principal:   func(*http.Request) string { return "" },
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i.middleware
+}
+
+func (i *idempotency) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+
+		switch r.Method {
+		case http.MethodPost, http.MethodPatch, http.MethodDelete:
+		default:
+			key = ""
+		}
+
+		if key == "" {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if len(key) > idempotencyKeyMaxLength {
+			renderErrorResponse(w, r, "invalid idempotency key",
+				internal.NewErrorf(internal.ErrCodeInvalidArgument, "key longer than %d", idempotencyKeyMaxLength))
+
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			renderErrorResponse(w, r, "invalid request",
+				internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "io.ReadAll"))
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := fingerprint(r, body)
+
+		key = scopedKey(r, i.principal(r), key)
+
+		record, acquired, err := i.store.Lock(r.Context(), key, fingerprint, time.Now().Add(i.lockTimeout))
+		if err != nil {
+			renderErrorResponse(w, r, "idempotency key failed", err)
+
+			return
+		}
+
+		if !acquired {
+			i.replay(w, r, fingerprint, record)
+
+			return
+		}
+
+		bw := &bufferedWriter{header: http.Header{}, status: http.StatusOK}
+
+		next.ServeHTTP(bw, r)
+
+		// XXX: The original context may be canceled already, the key must be saved or released regardless.
+		ctx := context.Background()
+
+		// XXX: Server errors are not stored so clients can retry them, the request may succeed next time. Saving
+		// fails when the lock expired and another request claimed the key, its result is kept instead.
+		if bw.status >= http.StatusInternalServerError {
+			_ = i.store.Release(ctx, key, record.Owner)
+		} else {
+			_ = i.store.Save(ctx, key, record.Owner, internal.IdempotentResponse{
+				StatusCode: bw.status,
+				Header:     bw.header.Clone(),
+				Body:       bw.body.Bytes(),
+			}, time.Now().Add(i.ttl))
+		}
+
+		bw.flush(w)
+	})
+}
+
+func (i *idempotency) replay(w http.ResponseWriter, r *http.Request, fingerprint string, record internal.IdempotencyRecord) {
+	if record.Fingerprint != fingerprint {
+		renderErrorResponse(w, r, "idempotency key already used with a different request",
+			internal.NewErrorf(internal.ErrCodeFailedPrecondition, "fingerprint mismatch"))
+
+		return
+	}
+
+	if record.Response == nil {
+		w.Header().Set("Retry-After", "1")
+
+		renderErrorResponse(w, r, "request with the same idempotency key in progress",
+			internal.NewErrorf(internal.ErrCodeConflict, "request in flight"))
+
+		return
+	}
+
+	for k, v := range record.Response.Header {
+		if _, ok := w.Header()[k]; !ok {
+			w.Header()[k] = v
+		}
+	}
+
+	w.Header().Set(idempotencyReplayedHeader, "true")
+	w.WriteHeader(record.Response.StatusCode)
+
+	_, _ = w.Write(record.Response.Body)
+}
+
+// scopedKey returns the key used for storing the received one, keys sent to different routes or by different
+// principals don't collide.
+func scopedKey(r *http.Request, principal, key string) string {
+	route := r.URL.Path
+
+	if cur := mux.CurrentRoute(r); cur != nil {
+		if tpl, err := cur.GetPathTemplate(); err == nil {
+			route = tpl
+		}
+	}
+
+	h := sha256.New()
+
+	_, _ = io.WriteString(h, r.Method+"\n"+route+"\n"+principal+"\n"+key)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fingerprint identifies the request, the same key can't be used for a different operation or payload.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+
+	_, _ = io.WriteString(h, r.Method+"\n"+r.URL.Path+"\n")
+	_, _ = h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
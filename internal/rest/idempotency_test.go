@@ -0,0 +1,319 @@
+package rest_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/rest"
+	"github.com/lrweck/todo/internal/rest/resttesting"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		expectedStatus   int
+		expectedBody     string
+		expectedReplayed string
+		expectedCalls    int
+		expectedSaves    int
+		expectedReleases int
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(*resttesting.FakeIdempotencyStore)
+		req    func() *http.Request
+		status int
+		output output
+	}{
+		{
+			"OK: without key",
+			func(*resttesting.FakeIdempotencyStore) {},
+			func() *http.Request {
+				return newJSONRequest(http.MethodPost, "/tasks", `{}`)
+			},
+			http.StatusCreated,
+			output{
+				expectedStatus: http.StatusCreated,
+				expectedBody:   "handled",
+				expectedCalls:  1,
+			},
+		},
+		{
+			"OK: safe method",
+			func(*resttesting.FakeIdempotencyStore) {},
+			func() *http.Request {
+				return newIdempotentRequest(http.MethodGet, "/tasks", ``, "key-1")
+			},
+			http.StatusOK,
+			output{
+				expectedStatus: http.StatusOK,
+				expectedBody:   "handled",
+				expectedCalls:  1,
+			},
+		},
+		{
+			"OK: first request",
+			func(s *resttesting.FakeIdempotencyStore) {
+				s.LockReturns(internal.IdempotencyRecord{}, true, nil)
+			},
+			func() *http.Request {
+				return newIdempotentRequest(http.MethodPost, "/tasks", `{}`, "key-1")
+			},
+			http.StatusCreated,
+			output{
+				expectedStatus: http.StatusCreated,
+				expectedBody:   "handled",
+				expectedCalls:  1,
+				expectedSaves:  1,
+			},
+		},
+		{
+			"OK: server error releases key",
+			func(s *resttesting.FakeIdempotencyStore) {
+				s.LockReturns(internal.IdempotencyRecord{}, true, nil)
+			},
+			func() *http.Request {
+				return newIdempotentRequest(http.MethodDelete, "/tasks", ``, "key-1")
+			},
+			http.StatusInternalServerError,
+			output{
+				expectedStatus:   http.StatusInternalServerError,
+				expectedBody:     "handled",
+				expectedCalls:    1,
+				expectedReleases: 1,
+			},
+		},
+		{
+			"OK: replayed",
+			func(s *resttesting.FakeIdempotencyStore) {
+				s.LockReturns(internal.IdempotencyRecord{
+					Fingerprint: fingerprintOf(http.MethodPost, "/tasks", `{}`),
+					Response: &internal.IdempotentResponse{
+						StatusCode: http.StatusCreated,
+						Body:       []byte("original"),
+					},
+				}, false, nil)
+			},
+			func() *http.Request {
+				return newIdempotentRequest(http.MethodPost, "/tasks", `{}`, "key-1")
+			},
+			http.StatusCreated,
+			output{
+				expectedStatus:   http.StatusCreated,
+				expectedBody:     "original",
+				expectedReplayed: "true",
+			},
+		},
+		{
+			"ERR: different request",
+			func(s *resttesting.FakeIdempotencyStore) {
+				s.LockReturns(internal.IdempotencyRecord{
+					Fingerprint: fingerprintOf(http.MethodPost, "/tasks", `{"description":"other"}`),
+					Response:    &internal.IdempotentResponse{StatusCode: http.StatusCreated},
+				}, false, nil)
+			},
+			func() *http.Request {
+				return newIdempotentRequest(http.MethodPost, "/tasks", `{}`, "key-1")
+			},
+			http.StatusCreated,
+			output{
+				expectedStatus: http.StatusUnprocessableEntity,
+				expectedBody:   `{"error":"idempotency key already used with a different request"}`,
+			},
+		},
+		{
+			"ERR: in flight",
+			func(s *resttesting.FakeIdempotencyStore) {
+				s.LockReturns(internal.IdempotencyRecord{
+					Fingerprint: fingerprintOf(http.MethodPost, "/tasks", `{}`),
+				}, false, nil)
+			},
+			func() *http.Request {
+				return newIdempotentRequest(http.MethodPost, "/tasks", `{}`, "key-1")
+			},
+			http.StatusCreated,
+			output{
+				expectedStatus: http.StatusConflict,
+				expectedBody:   `{"error":"request with the same idempotency key in progress"}`,
+			},
+		},
+		{
+			"ERR: store",
+			func(s *resttesting.FakeIdempotencyStore) {
+				s.LockReturns(internal.IdempotencyRecord{}, false,
+					internal.WrapErrorf(errors.New("connection refused"), internal.ErrCodeUnavailable, "insert"))
+			},
+			func() *http.Request {
+				return newIdempotentRequest(http.MethodPatch, "/tasks", `{}`, "key-1")
+			},
+			http.StatusOK,
+			output{
+				expectedStatus: http.StatusServiceUnavailable,
+				expectedBody:   `{"error":"idempotency key failed"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &resttesting.FakeIdempotencyStore{}
+			tt.setup(store)
+
+			calls := 0
+
+			router := mux.NewRouter()
+			router.Use(rest.NewIdempotencyMiddleware(store))
+			router.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
+				calls++
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("handled"))
+			})
+
+			res := doRequest(router, tt.req())
+			defer res.Body.Close()
+
+			body, _ := io.ReadAll(res.Body)
+
+			if tt.output.expectedStatus != res.StatusCode {
+				t.Fatalf("expected code %d, actual %d", tt.output.expectedStatus, res.StatusCode)
+			}
+
+			if tt.output.expectedBody != string(body) {
+				t.Fatalf("expected body %q, actual %q", tt.output.expectedBody, body)
+			}
+
+			if actual := res.Header.Get("Idempotent-Replayed"); tt.output.expectedReplayed != actual {
+				t.Fatalf("expected replayed %q, actual %q", tt.output.expectedReplayed, actual)
+			}
+
+			if tt.output.expectedCalls != calls {
+				t.Fatalf("expected %d calls, actual %d", tt.output.expectedCalls, calls)
+			}
+
+			if tt.output.expectedSaves != store.SaveCallCount() {
+				t.Fatalf("expected %d saves, actual %d", tt.output.expectedSaves, store.SaveCallCount())
+			}
+
+			if tt.output.expectedReleases != store.ReleaseCallCount() {
+				t.Fatalf("expected %d releases, actual %d", tt.output.expectedReleases, store.ReleaseCallCount())
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	store := &resttesting.FakeIdempotencyStore{}
+	store.LockReturns(internal.IdempotencyRecord{}, true, nil)
+
+	router := mux.NewRouter()
+	router.Use(rest.NewIdempotencyMiddleware(store))
+	router.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	})
+
+	res := doRequest(router, newIdempotentRequest(http.MethodPost, "/tasks", `{"description":"a"}`, "key-1"))
+	defer res.Body.Close()
+
+	if body, _ := io.ReadAll(res.Body); string(body) != `{"description":"a"}` {
+		t.Fatalf("expected the body to be readable by the handler, actual %q", body)
+	}
+
+	_, key, fingerprint, _ := store.LockArgsForCall(0)
+	if key == "key-1" {
+		t.Fatalf("expected scoped key, actual %s", key)
+	}
+
+	if expected := fingerprintOf(http.MethodPost, "/tasks", `{"description":"a"}`); fingerprint != expected {
+		t.Fatalf("expected fingerprint %s, actual %s", expected, fingerprint)
+	}
+
+	_, _, _, resp, _ := store.SaveArgsForCall(0)
+	if resp.StatusCode != http.StatusOK || string(resp.Body) != `{"description":"a"}` {
+		t.Fatalf("unexpected saved response %+v", resp)
+	}
+}
+
+func TestIdempotencyMiddleware_Scope(t *testing.T) {
+	t.Parallel()
+
+	store := &resttesting.FakeIdempotencyStore{}
+	store.LockReturns(internal.IdempotencyRecord{Owner: "owner-1"}, true, nil)
+
+	router := mux.NewRouter()
+	router.Use(rest.NewIdempotencyMiddleware(store, rest.WithIdempotencyPrincipal(func(r *http.Request) string {
+		return r.Header.Get("X-User")
+	})))
+	router.HandleFunc("/tasks", func(http.ResponseWriter, *http.Request) {})
+	router.HandleFunc("/tasks/{id}", func(http.ResponseWriter, *http.Request) {})
+
+	send := func(target, user string) string {
+		req := newIdempotentRequest(http.MethodPost, target, `{}`, "key-1")
+		req.Header.Set("X-User", user)
+
+		res := doRequest(router, req)
+		res.Body.Close()
+
+		_, key, _, _ := store.LockArgsForCall(store.LockCallCount() - 1)
+
+		return key
+	}
+
+	first := send("/tasks", "user-1")
+
+	if key := send("/tasks", "user-2"); key == first {
+		t.Fatalf("expected keys scoped by principal")
+	}
+
+	if key := send("/tasks/1", "user-1"); key == first {
+		t.Fatalf("expected keys scoped by route")
+	}
+
+	if key := send("/tasks", "user-1"); key != first {
+		t.Fatalf("expected the same key, actual %s", key)
+	}
+
+	// Only the request holding the key can save its response.
+
+	if _, _, owner, _, _ := store.SaveArgsForCall(0); owner != "owner-1" {
+		t.Fatalf("expected owner-1, actual %s", owner)
+	}
+}
+
+func newIdempotentRequest(method, target, body, key string) *http.Request {
+	req := newJSONRequest(method, target, body)
+	req.Header.Set("Idempotency-Key", key)
+
+	return req
+}
+
+// fingerprintOf captures the fingerprint computed by the middleware for the request.
+func fingerprintOf(method, target, body string) string {
+	store := &resttesting.FakeIdempotencyStore{}
+	store.LockReturns(internal.IdempotencyRecord{}, true, nil)
+
+	router := mux.NewRouter()
+	router.Use(rest.NewIdempotencyMiddleware(store))
+	router.HandleFunc(target, func(http.ResponseWriter, *http.Request) {})
+
+	res := doRequest(router, newIdempotentRequest(method, target, body, "key"))
+	res.Body.Close()
+
+	_, _, fingerprint, _ := store.LockArgsForCall(0)
+
+	return fingerprint
+}
@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// RegisterMetrics serves the metrics using the received handler, usually the Prometheus exporter.
+func RegisterMetrics(r *mux.Router, handler http.Handler) {
+	r.Handle("/metrics", handler).Methods(http.MethodGet)
+}
+
+// NewMetricsMiddleware returns the middleware measuring the duration and number of requests, using the route
+// template and the response status as attributes.
+func NewMetricsMiddleware() mux.MiddlewareFunc {
+	meter := metric.Must(global.Meter("todo.rest"))
+
+	duration := meter.NewFloat64Histogram("http.server.duration",
+		metric.WithDescription("Duration of the HTTP requests"),
+		metric.WithUnit("ms"))
+	requests := meter.NewInt64Counter("http.server.requests",
+		metric.WithDescription("Number of HTTP requests"))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(sw, r)
+
+			route := r.URL.Path
+			if current := mux.CurrentRoute(r); current != nil {
+				if tmpl, err := current.GetPathTemplate(); err == nil {
+					route = routeName(tmpl)
+				}
+			}
+
+			attrs := []attribute.KeyValue{
+				semconv.HTTPRouteKey.String(route),
+				semconv.HTTPMethodKey.String(r.Method),
+				semconv.HTTPStatusCodeKey.String(strconv.Itoa(sw.status)),
+			}
+
+			duration.Record(r.Context(), float64(time.Since(start))/float64(time.Millisecond), attrs...)
+			requests.Add(r.Context(), 1, attrs...)
+		})
+	}
+}
+
+// routeName removes the regular expressions from the route template variables, for example
+// "/task/{id:[0-9]+}" becomes "/task/{id}".
+func routeName(tmpl string) string {
+	var (
+		b     strings.Builder
+		depth int
+		skip  bool
+	)
+
+	for _, c := range tmpl {
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				skip = false
+			}
+		case c == ':' && depth == 1:
+			skip = true
+		}
+
+		if !skip || (c == '}' && depth == 0) {
+			b.WriteRune(c)
+		}
+	}
+
+	return b.String()
+}
+
+// statusWriter records the status code written by the handler.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusWriter) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Flush sends any buffered data to the client, required for streaming events.
+func (s *statusWriter) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, required for upgrading to WebSockets.
+func (s *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	s.status = http.StatusSwitchingProtocols
+
+	return h.Hijack()
+}
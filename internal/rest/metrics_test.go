@@ -0,0 +1,45 @@
+package rest_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal/rest"
+	"github.com/lrweck/todo/internal/rest/resttesting"
+	"github.com/lrweck/todo/internal/telemetry"
+)
+
+//nolint:paralleltest // The Prometheus exporter is installed globally.
+func TestMetrics(t *testing.T) {
+	exporter, err := telemetry.NewPrometheusExporter()
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(rest.NewMetricsMiddleware())
+
+	rest.NewTaskHandler(&resttesting.FakeTaskService{}).Register(router)
+	rest.RegisterMetrics(router, exporter)
+
+	_ = doRequest(router,
+		httptest.NewRequest(http.MethodDelete, "/task/aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee", nil))
+
+	res := doRequest(router, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("couldn't read body %s", err)
+	}
+
+	expected := `http_server_requests{http_method="DELETE",http_route="/task/{id}",http_status_code="200",`
+	if !strings.Contains(string(b), expected) {
+		t.Fatalf("expected metric %s, got %s", expected, b)
+	}
+}
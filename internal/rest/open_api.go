@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"math"
+	"net"
 	"net/http"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -11,15 +13,115 @@ import (
 //go:generate go run ../../cmd/openapi-gen/main.go -path .
 //go:generate oapi-codegen -package openapi3 -generate types  -o ../../pkg/openapi3/task_types.gen.go openapi3.yaml
 //go:generate oapi-codegen -package openapi3 -generate client -o ../../pkg/openapi3/client.gen.go     openapi3.yaml
+//go:generate oapi-codegen -package openapi3v2 -generate types  -o ../../pkg/openapi3v2/task_types.gen.go openapi3.v2.yaml
+//go:generate oapi-codegen -package openapi3v2 -generate client -o ../../pkg/openapi3v2/client.gen.go     openapi3.v2.yaml
+
+// OpenAPIOption defines the options used for customizing the OpenAPI specification.
+type OpenAPIOption func(*openapi3.T)
+
+// WithContact sets the contact information.
+func WithContact(contact openapi3.Contact) OpenAPIOption {
+	return func(t *openapi3.T) {
+		t.Info.Contact = &contact
+	}
+}
+
+// WithServers replaces the servers, by default none is listed and the requests are sent to the host serving the
+// specification.
+func WithServers(servers ...*openapi3.Server) OpenAPIOption {
+	return func(t *openapi3.T) {
+		t.Servers = servers
+	}
+}
+
+// WithAddress lists the server listening on addr, for example ":9234", unspecified hosts are reached via loopback.
+func WithAddress(addr string) OpenAPIOption {
+	return func(t *openapi3.T) {
+		t.Servers = openapi3.Servers{
+			&openapi3.Server{
+				Description: "Local development",
+				URL:         serverURL(addr),
+			},
+		}
+	}
+}
+
+func serverURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr
+	}
+
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// WithSecurityScheme adds a security scheme required by all the operations, it's used by the interactive
+// documentation for authorizing the requests.
+func WithSecurityScheme(name string, scheme *openapi3.SecurityScheme) OpenAPIOption {
+	return func(t *openapi3.T) {
+		if t.Components.SecuritySchemes == nil {
+			t.Components.SecuritySchemes = openapi3.SecuritySchemes{}
+		}
+
+		t.Components.SecuritySchemes[name] = &openapi3.SecuritySchemeRef{Value: scheme}
+		t.Security = append(t.Security, openapi3.NewSecurityRequirement().Authenticate(name))
+	}
+}
 
 // NewOpenAPI3 instantiates the OpenAPI specification for this service.
-func NewOpenAPI3() openapi3.T {
+// NewOpenAPI3 instantiates the OpenAPI specification for version 1 of this service, it's the one used for generating
+// the client in pkg/openapi3.
+func NewOpenAPI3(opts ...OpenAPIOption) openapi3.T {
+	swagger := newOpenAPI3("1.0.0")
+	swagger.Paths = openAPI3PathsV1()
+
+	for _, opt := range opts {
+		opt(&swagger)
+	}
+
+	return swagger
+}
+
+// NewOpenAPI3V2 instantiates the OpenAPI specification for version 2 of this service.
+func NewOpenAPI3V2(opts ...OpenAPIOption) openapi3.T {
+	swagger := newOpenAPI3("2.0.0")
+	swagger.Components.RequestBodies["PatchTasksRequest"] = &openapi3.RequestBodyRef{
+		Value: openapi3.NewRequestBody().
+			WithDescription("Request used for partially updating a task, only the included fields are updated.").
+			WithRequired(true).
+			WithJSONSchema(withExample(openapi3.NewSchema().
+				WithProperty("description", openapi3.NewStringSchema().
+					WithMinLength(1)).
+				WithProperty("is_done", openapi3.NewBoolSchema()).
+				WithPropertyRef("priority", &openapi3.SchemaRef{
+					Ref: "#/components/schemas/Priority",
+				}).
+				WithPropertyRef("dates", &openapi3.SchemaRef{
+					Ref: "#/components/schemas/Dates",
+				}), map[string]interface{}{
+				"is_done": true,
+			})),
+	}
+	swagger.Paths = openAPI3PathsV2()
+
+	for _, opt := range opts {
+		opt(&swagger)
+	}
+
+	return swagger
+}
+
+func newOpenAPI3(version string) openapi3.T {
 	swagger := openapi3.T{
 		OpenAPI: "3.0.0",
 		Info: &openapi3.Info{
 			Title:       "ToDo API",
 			Description: "REST APIs used for interacting with the ToDo Service",
-			Version:     "0.0.0",
+			Version:     version,
 			License: &openapi3.License{
 				Name: "MIT",
 				URL:  "https://opensource.org/licenses/MIT",
@@ -28,29 +130,45 @@ func NewOpenAPI3() openapi3.T {
 				URL: "https://github.com/MarioCarrion/todo-api-microservice-example",
 			},
 		},
-		Servers: openapi3.Servers{
-			&openapi3.Server{
-				Description: "Local development",
-				URL:         "http://127.0.0.1:9234",
-			},
-		},
+	}
+
+	datesExample := map[string]interface{}{
+		"start": "2021-11-06T09:00:00Z",
+		"due":   "2021-11-07T18:00:00Z",
+	}
+
+	taskExample := map[string]interface{}{
+		"id":          "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+		"description": "Buy groceries",
+		"is_done":     false,
+		"priority":    "medium",
+		"dates":       datesExample,
+	}
+
+	trashedTaskExample := map[string]interface{}{
+		"id":          "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+		"description": "Buy groceries",
+		"is_done":     false,
+		"priority":    "medium",
+		"dates":       datesExample,
+		"deleted_at":  "2021-11-08T10:30:00Z",
 	}
 
 	swagger.Components.Schemas = openapi3.Schemas{
 		"Priority": openapi3.NewSchemaRef("",
-			openapi3.NewStringSchema().
+			withExample(openapi3.NewStringSchema().
 				WithEnum("none", "low", "medium", "high").
-				WithDefault("none")),
+				WithDefault("none"), "medium")),
 		"Dates": openapi3.NewSchemaRef("",
-			openapi3.NewObjectSchema().
+			withExample(openapi3.NewObjectSchema().
 				WithProperty("start", openapi3.NewStringSchema().
 					WithFormat("date-time").
 					WithNullable()).
 				WithProperty("due", openapi3.NewStringSchema().
 					WithFormat("date-time").
-					WithNullable())),
+					WithNullable()), datesExample)),
 		"Task": openapi3.NewSchemaRef("",
-			openapi3.NewObjectSchema().
+			withExample(openapi3.NewObjectSchema().
 				WithProperty("id", openapi3.NewUUIDSchema()).
 				WithProperty("description", openapi3.NewStringSchema()).
 				WithProperty("is_done", openapi3.NewBoolSchema()).
@@ -59,7 +177,45 @@ func NewOpenAPI3() openapi3.T {
 				}).
 				WithPropertyRef("dates", &openapi3.SchemaRef{
 					Ref: "#/components/schemas/Dates",
-				})),
+				}), taskExample)),
+		"ProblemDetails": openapi3.NewSchemaRef("",
+			withExample(openapi3.NewObjectSchema().
+				WithProperty("type", openapi3.NewStringSchema()).
+				WithProperty("title", openapi3.NewStringSchema()).
+				WithProperty("status", openapi3.NewIntegerSchema()).
+				WithProperty("detail", openapi3.NewStringSchema()).
+				WithProperty("instance", openapi3.NewStringSchema()).
+				WithProperty("code", openapi3.NewStringSchema()).
+				WithProperty("request_id", openapi3.NewStringSchema()).
+				WithProperty("trace_id", openapi3.NewStringSchema()).
+				WithProperty("errors", openapi3.NewObjectSchema().
+					WithAnyAdditionalProperties()), map[string]interface{}{
+				"type":       "urn:todo:problem:invalid_argument",
+				"title":      "Invalid Argument",
+				"status":     400,
+				"detail":     "invalid request",
+				"instance":   "/v2/tasks",
+				"code":       "invalid_argument",
+				"request_id": "8f14e45f-ceea-467f-a0e6-a3f1c2b1a1d5",
+				"errors": map[string]interface{}{
+					"dates": map[string]interface{}{
+						"start": "must be before due",
+					},
+				},
+			})),
+		"TrashedTask": openapi3.NewSchemaRef("",
+			withExample(openapi3.NewObjectSchema().
+				WithProperty("id", openapi3.NewUUIDSchema()).
+				WithProperty("description", openapi3.NewStringSchema()).
+				WithProperty("is_done", openapi3.NewBoolSchema()).
+				WithPropertyRef("priority", &openapi3.SchemaRef{
+					Ref: "#/components/schemas/Priority",
+				}).
+				WithPropertyRef("dates", &openapi3.SchemaRef{
+					Ref: "#/components/schemas/Dates",
+				}).
+				WithProperty("deleted_at", openapi3.NewStringSchema().
+					WithFormat("date-time")), trashedTaskExample)),
 	}
 
 	swagger.Components.RequestBodies = openapi3.RequestBodies{
@@ -67,7 +223,7 @@ func NewOpenAPI3() openapi3.T {
 			Value: openapi3.NewRequestBody().
 				WithDescription("Request used for creating a task.").
 				WithRequired(true).
-				WithJSONSchema(openapi3.NewSchema().
+				WithJSONSchema(withExample(openapi3.NewSchema().
 					WithProperty("description", openapi3.NewStringSchema().
 						WithMinLength(1)).
 					WithPropertyRef("priority", &openapi3.SchemaRef{
@@ -75,13 +231,17 @@ func NewOpenAPI3() openapi3.T {
 					}).
 					WithPropertyRef("dates", &openapi3.SchemaRef{
 						Ref: "#/components/schemas/Dates",
-					})),
+					}), map[string]interface{}{
+					"description": "Buy groceries",
+					"priority":    "medium",
+					"dates":       datesExample,
+				})),
 		},
 		"UpdateTasksRequest": &openapi3.RequestBodyRef{
 			Value: openapi3.NewRequestBody().
 				WithDescription("Request used for updating a task.").
 				WithRequired(true).
-				WithJSONSchema(openapi3.NewSchema().
+				WithJSONSchema(withExample(openapi3.NewSchema().
 					WithProperty("description", openapi3.NewStringSchema().
 						WithMinLength(1)).
 					WithProperty("is_done", openapi3.NewBoolSchema().
@@ -91,13 +251,18 @@ func NewOpenAPI3() openapi3.T {
 					}).
 					WithPropertyRef("dates", &openapi3.SchemaRef{
 						Ref: "#/components/schemas/Dates",
-					})),
+					}), map[string]interface{}{
+					"description": "Buy groceries",
+					"is_done":     true,
+					"priority":    "medium",
+					"dates":       datesExample,
+				})),
 		},
 		"SearchTasksRequest": &openapi3.RequestBodyRef{
 			Value: openapi3.NewRequestBody().
 				WithDescription("Request used for searching a task.").
 				WithRequired(true).
-				WithJSONSchema(openapi3.NewSchema().
+				WithJSONSchema(withExample(openapi3.NewSchema().
 					WithProperty("description", openapi3.NewStringSchema().
 						WithMinLength(1).
 						WithNullable()).
@@ -110,37 +275,57 @@ func NewOpenAPI3() openapi3.T {
 					WithProperty("from", openapi3.NewInt64Schema().
 						WithDefault(0)).
 					WithProperty("size", openapi3.NewInt64Schema().
-						WithDefault(10))),
+						WithDefault(10)), map[string]interface{}{
+					"description": "groceries",
+					"is_done":     false,
+					"priority":    "medium",
+					"from":        0,
+					"size":        10,
+				})),
+		},
+	}
+
+	swagger.Components.Parameters = openapi3.ParametersMap{
+		"IdempotencyKey": &openapi3.ParameterRef{
+			Value: openapi3.NewHeaderParameter("Idempotency-Key").
+				WithDescription("Retrying the request with the same key replays the original response.").
+				WithSchema(openapi3.NewStringSchema().WithMaxLength(255)),
 		},
 	}
 
 	swagger.Components.Responses = openapi3.Responses{
 		"ErrorResponse": &openapi3.ResponseRef{
 			Value: openapi3.NewResponse().
-				WithDescription("Response when errors happen.").
-				WithContent(openapi3.NewContentWithJSONSchema(openapi3.NewSchema().
-					WithProperty("error", openapi3.NewStringSchema()))),
+				WithDescription("Response when errors happen, RFC 7807 is used when accepting application/problem+json.").
+				WithContent(withProblemDetails(openapi3.NewContentWithJSONSchema(withExample(openapi3.NewSchema().
+					WithProperty("error", openapi3.NewStringSchema()), map[string]interface{}{
+					"error": "invalid request",
+				})))),
 		},
 		"CreateTasksResponse": &openapi3.ResponseRef{
 			Value: openapi3.NewResponse().
 				WithDescription("Response returned back after creating tasks.").
-				WithContent(openapi3.NewContentWithJSONSchema(openapi3.NewSchema().
+				WithContent(openapi3.NewContentWithJSONSchema(withExample(openapi3.NewSchema().
 					WithPropertyRef("task", &openapi3.SchemaRef{
 						Ref: "#/components/schemas/Task",
-					}))),
+					}), map[string]interface{}{
+					"task": taskExample,
+				}))),
 		},
 		"ReadTasksResponse": &openapi3.ResponseRef{
 			Value: openapi3.NewResponse().
 				WithDescription("Response returned back after searching one task.").
-				WithContent(openapi3.NewContentWithJSONSchema(openapi3.NewSchema().
+				WithContent(openapi3.NewContentWithJSONSchema(withExample(openapi3.NewSchema().
 					WithPropertyRef("task", &openapi3.SchemaRef{
 						Ref: "#/components/schemas/Task",
-					}))),
+					}), map[string]interface{}{
+					"task": taskExample,
+				}))),
 		},
 		"SearchTasksResponse": &openapi3.ResponseRef{
 			Value: openapi3.NewResponse().
 				WithDescription("Response returned back after searching for any task.").
-				WithContent(openapi3.NewContentWithJSONSchema(openapi3.NewSchema().
+				WithContent(openapi3.NewContentWithJSONSchema(withExample(openapi3.NewSchema().
 					WithPropertyRef("tasks", &openapi3.SchemaRef{
 						Value: &openapi3.Schema{
 							Type: "array",
@@ -149,13 +334,37 @@ func NewOpenAPI3() openapi3.T {
 							},
 						},
 					}).
-					WithProperty("total", openapi3.NewInt64Schema()))),
+					WithProperty("total", openapi3.NewInt64Schema()), map[string]interface{}{
+					"tasks": []interface{}{taskExample},
+					"total": 1,
+				}))),
+		},
+		"ListTrashResponse": &openapi3.ResponseRef{
+			Value: openapi3.NewResponse().
+				WithDescription("Response returned back after listing the trash.").
+				WithContent(openapi3.NewContentWithJSONSchema(withExample(openapi3.NewSchema().
+					WithPropertyRef("tasks", &openapi3.SchemaRef{
+						Value: &openapi3.Schema{
+							Type: "array",
+							Items: &openapi3.SchemaRef{
+								Ref: "#/components/schemas/TrashedTask",
+							},
+						},
+					}).
+					WithProperty("total", openapi3.NewInt64Schema()), map[string]interface{}{
+					"tasks": []interface{}{trashedTaskExample},
+					"total": 1,
+				}))),
 		},
 	}
 
-	swagger.Paths = openapi3.Paths{
-		"/tasks": &openapi3.PathItem{
-			Post: &openapi3.Operation{
+	return swagger
+}
+
+func openAPI3PathsV1() openapi3.Paths {
+	return openapi3.Paths{
+		"/v1/tasks": &openapi3.PathItem{
+			Post: withIdempotencyKey(&openapi3.Operation{
 				OperationID: "CreateTask",
 				RequestBody: &openapi3.RequestBodyRef{
 					Ref: "#/components/requestBodies/CreateTasksRequest",
@@ -171,10 +380,10 @@ func NewOpenAPI3() openapi3.T {
 						Ref: "#/components/responses/CreateTasksResponse",
 					},
 				},
-			},
+			}),
 		},
-		"/task/{taskId}": &openapi3.PathItem{
-			Delete: &openapi3.Operation{
+		"/v1/task/{taskId}": &openapi3.PathItem{
+			Delete: withIdempotencyKey(&openapi3.Operation{
 				OperationID: "DeleteTask",
 				Parameters: []*openapi3.ParameterRef{
 					{
@@ -193,7 +402,7 @@ func NewOpenAPI3() openapi3.T {
 						Ref: "#/components/responses/ErrorResponse",
 					},
 				},
-			},
+			}),
 			Get: &openapi3.Operation{
 				OperationID: "ReadTask",
 				Parameters: []*openapi3.ParameterRef{
@@ -241,7 +450,61 @@ func NewOpenAPI3() openapi3.T {
 				},
 			},
 		},
-		"/search/tasks": &openapi3.PathItem{
+		"/v1/task/{taskId}/restore": &openapi3.PathItem{
+			Post: withIdempotencyKey(&openapi3.Operation{
+				OperationID: "RestoreTask",
+				Parameters: []*openapi3.ParameterRef{
+					{
+						Value: openapi3.NewPathParameter("taskId").
+							WithSchema(openapi3.NewUUIDSchema()),
+					},
+				},
+				Responses: openapi3.Responses{
+					"200": &openapi3.ResponseRef{
+						Value: openapi3.NewResponse().WithDescription("Task restored"),
+					},
+					"404": &openapi3.ResponseRef{
+						Value: openapi3.NewResponse().WithDescription("Task not found in trash"),
+					},
+					"500": &openapi3.ResponseRef{
+						Ref: "#/components/responses/ErrorResponse",
+					},
+				},
+			}),
+		},
+		"/v1/trash": &openapi3.PathItem{
+			Get: &openapi3.Operation{
+				OperationID: "ListTrashedTasks",
+				Parameters: []*openapi3.ParameterRef{
+					{
+						Value: openapi3.NewQueryParameter("from").
+							WithSchema(openapi3.NewInt64Schema().
+								WithMin(0).
+								WithMax(math.MaxInt32).
+								WithDefault(0)),
+					},
+					{
+						Value: openapi3.NewQueryParameter("size").
+							WithSchema(openapi3.NewInt64Schema().
+								WithMin(0).
+								WithMax(maxTrashSize).
+								WithDefault(10)),
+					},
+				},
+				Responses: openapi3.Responses{
+					"200": &openapi3.ResponseRef{
+						Ref: "#/components/responses/ListTrashResponse",
+					},
+					"400": &openapi3.ResponseRef{
+						Ref: "#/components/responses/ErrorResponse",
+					},
+					"500": &openapi3.ResponseRef{
+						Ref: "#/components/responses/ErrorResponse",
+					},
+				},
+			},
+		},
+		"/v1/search/tasks": &openapi3.PathItem{
 			Post: &openapi3.Operation{
 				OperationID: "SearchTask",
 				RequestBody: &openapi3.RequestBodyRef{
@@ -262,20 +525,192 @@ func NewOpenAPI3() openapi3.T {
 		},
 	}
 
-	return swagger
 }
 
-func RegisterOpenAPI(r *mux.Router) {
-	swagger := NewOpenAPI3()
+func openAPI3PathsV2() openapi3.Paths {
+	taskID := &openapi3.ParameterRef{
+		Value: openapi3.NewPathParameter("taskId").
+			WithSchema(openapi3.NewUUIDSchema()),
+	}
+
+	errorResponse := &openapi3.ResponseRef{
+		Ref: "#/components/responses/ErrorResponse",
+	}
+
+	notFoundResponse := &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().WithDescription("Task not found"),
+	}
+
+	return openapi3.Paths{
+		"/v2/tasks": &openapi3.PathItem{
+			Post: withIdempotencyKey(&openapi3.Operation{
+				OperationID: "CreateTask",
+				RequestBody: &openapi3.RequestBodyRef{
+					Ref: "#/components/requestBodies/CreateTasksRequest",
+				},
+				Responses: openapi3.Responses{
+					"201": &openapi3.ResponseRef{
+						Ref: "#/components/responses/CreateTasksResponse",
+					},
+					"400": errorResponse,
+					"500": errorResponse,
+				},
+			}),
+		},
+		"/v2/tasks/{taskId}": &openapi3.PathItem{
+			Delete: withIdempotencyKey(&openapi3.Operation{
+				OperationID: "DeleteTask",
+				Parameters:  openapi3.Parameters{taskID},
+				Responses: openapi3.Responses{
+					"200": &openapi3.ResponseRef{
+						Value: openapi3.NewResponse().WithDescription("Task deleted"),
+					},
+					"404": notFoundResponse,
+					"500": errorResponse,
+				},
+			}),
+			Get: &openapi3.Operation{
+				OperationID: "ReadTask",
+				Parameters:  openapi3.Parameters{taskID},
+				Responses: openapi3.Responses{
+					"200": &openapi3.ResponseRef{
+						Ref: "#/components/responses/ReadTasksResponse",
+					},
+					"404": notFoundResponse,
+					"500": errorResponse,
+				},
+			},
+			Patch: withIdempotencyKey(&openapi3.Operation{
+				OperationID: "PatchTask",
+				Parameters:  openapi3.Parameters{taskID},
+				RequestBody: &openapi3.RequestBodyRef{
+					Ref: "#/components/requestBodies/PatchTasksRequest",
+				},
+				Responses: openapi3.Responses{
+					"200": &openapi3.ResponseRef{
+						Ref: "#/components/responses/ReadTasksResponse",
+					},
+					"400": errorResponse,
+					"404": notFoundResponse,
+					"500": errorResponse,
+				},
+			}),
+		},
+		"/v2/tasks/{taskId}/restore": &openapi3.PathItem{
+			Post: withIdempotencyKey(&openapi3.Operation{
+				OperationID: "RestoreTask",
+				Parameters:  openapi3.Parameters{taskID},
+				Responses: openapi3.Responses{
+					"200": &openapi3.ResponseRef{
+						Value: openapi3.NewResponse().WithDescription("Task restored"),
+					},
+					"404": &openapi3.ResponseRef{
+						Value: openapi3.NewResponse().WithDescription("Task not found in trash"),
+					},
+					"500": errorResponse,
+				},
+			}),
+		},
+		"/v2/trash": &openapi3.PathItem{
+			Get: &openapi3.Operation{
+				OperationID: "ListTrashedTasks",
+				Parameters: openapi3.Parameters{
+					{
+						Value: openapi3.NewQueryParameter("from").
+							WithSchema(openapi3.NewInt64Schema().
+								WithMin(0).
+								WithMax(math.MaxInt32).
+								WithDefault(0)),
+					},
+					{
+						Value: openapi3.NewQueryParameter("size").
+							WithSchema(openapi3.NewInt64Schema().
+								WithMin(0).
+								WithMax(maxTrashSize).
+								WithDefault(10)),
+					},
+				},
+				Responses: openapi3.Responses{
+					"200": &openapi3.ResponseRef{
+						Ref: "#/components/responses/ListTrashResponse",
+					},
+					"400": errorResponse,
+					"500": errorResponse,
+				},
+			},
+		},
+		"/v2/search/tasks": &openapi3.PathItem{
+			Post: &openapi3.Operation{
+				OperationID: "SearchTask",
+				RequestBody: &openapi3.RequestBodyRef{
+					Ref: "#/components/requestBodies/SearchTasksRequest",
+				},
+				Responses: openapi3.Responses{
+					"200": &openapi3.ResponseRef{
+						Ref: "#/components/responses/SearchTasksResponse",
+					},
+					"400": errorResponse,
+					"500": errorResponse,
+				},
+			},
+		},
+	}
+}
+
+// withIdempotencyKey documents the "Idempotency-Key" header, and the responses returned when misusing it.
+func withIdempotencyKey(op *openapi3.Operation) *openapi3.Operation {
+	op.Parameters = append(op.Parameters, &openapi3.ParameterRef{
+		Ref: "#/components/parameters/IdempotencyKey",
+	})
+
+	op.Responses["409"] = &openapi3.ResponseRef{
+		Ref: "#/components/responses/ErrorResponse",
+	}
+
+	op.Responses["422"] = &openapi3.ResponseRef{
+		Ref: "#/components/responses/ErrorResponse",
+	}
+
+	return op
+}
+
+func withProblemDetails(content openapi3.Content) openapi3.Content {
+	content[problemContentType] = openapi3.NewMediaType().
+		WithSchemaRef(&openapi3.SchemaRef{
+			Ref: "#/components/schemas/ProblemDetails",
+		})
+
+	return content
+}
+
+func withExample(schema *openapi3.Schema, example interface{}) *openapi3.Schema {
+	schema.Example = example
+
+	return schema
+}
+
+// RegisterOpenAPI connects the handlers serving the OpenAPI specification of each version and the interactive
+// documentation. The unversioned paths serve version 1 for existing integrations.
+func RegisterOpenAPI(r *mux.Router, opts ...OpenAPIOption) {
+	v1 := NewOpenAPI3(opts...)
+	v2 := NewOpenAPI3V2(opts...)
+
+	registerDocs(r)
+
+	registerSpec(r, "", &v1)
+	registerSpec(r, "/"+APIVersion1, &v1)
+	registerSpec(r, "/"+APIVersion2, &v2)
+}
 
-	r.HandleFunc("/openapi3.json", func(w http.ResponseWriter, r *http.Request) {
-		renderResponse(r.Context(), w, &swagger, http.StatusOK)
+func registerSpec(r *mux.Router, prefix string, swagger *openapi3.T) {
+	r.HandleFunc(prefix+"/openapi3.json", func(w http.ResponseWriter, r *http.Request) {
+		renderResponse(r.Context(), w, swagger, http.StatusOK)
 	}).Methods(http.MethodGet)
 
-	r.HandleFunc("/openapi3.yaml", func(w http.ResponseWriter, r *http.Request) {
+	r.HandleFunc(prefix+"/openapi3.yaml", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/x-yaml")
 
-		data, _ := yaml.Marshal(&swagger)
+		data, _ := yaml.Marshal(swagger)
 
 		_, _ = w.Write(data)
 
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// RateLimiter rejects requests exceeding the configured rate with 429, the limit can be changed at runtime.
+type RateLimiter struct {
+	mu      sync.RWMutex
+	limiter *rate.Limiter
+}
+
+// NewRateLimiter instantiates the RateLimiter, zero requests per second disables it.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	l := &RateLimiter{}
+
+	l.SetLimit(rps, burst)
+
+	return l
+}
+
+// SetLimit changes the number of requests per second and the burst allowed, zero requests per second disables
+// the limiter.
+func (l *RateLimiter) SetLimit(rps float64, burst int) {
+	var limiter *rate.Limiter
+
+	if rps > 0 {
+		limiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limiter = limiter
+}
+
+// Middleware returns the middleware enforcing the limit.
+//
+// XXX: The limit is global for the instance, not per client.
+func (l *RateLimiter) Middleware() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l.mu.RLock()
+			limiter := l.limiter
+			l.mu.RUnlock()
+
+			if limiter != nil && !limiter.Allow() {
+				renderErrorResponse(w, r, "too many requests",
+					internal.NewErrorf(internal.ErrCodeResourceExhausted, "rate limit exceeded"))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal/rest"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := rest.NewRateLimiter(0, 0)
+
+	router := mux.NewRouter()
+	router.Use(limiter.Middleware())
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	status := func() int {
+		res := doRequest(router, httptest.NewRequest(http.MethodGet, "/", nil))
+		defer res.Body.Close()
+
+		return res.StatusCode
+	}
+
+	for i := 0; i < 5; i++ {
+		if code := status(); code != http.StatusNoContent {
+			t.Fatalf("expected code %d while disabled, actual %d", http.StatusNoContent, code)
+		}
+	}
+
+	limiter.SetLimit(0.001, 2)
+
+	for i := 0; i < 2; i++ {
+		if code := status(); code != http.StatusNoContent {
+			t.Fatalf("expected code %d within burst, actual %d", http.StatusNoContent, code)
+		}
+	}
+
+	if code := status(); code != http.StatusTooManyRequests {
+		t.Fatalf("expected code %d, actual %d", http.StatusTooManyRequests, code)
+	}
+
+	limiter.SetLimit(0, 0)
+
+	if code := status(); code != http.StatusNoContent {
+		t.Fatalf("expected code %d after disabling, actual %d", http.StatusNoContent, code)
+	}
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// NewRequestIDMiddleware returns the middleware assigning an ID to each request, the one received in the
+// "X-Request-Id" header is used when present. The ID is included in the response headers and error responses.
+func NewRequestIDMiddleware() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestIDHeader)
+			if id == "" || len(id) > 128 {
+				id = uuid.NewString()
+			}
+
+			w.Header().Set(requestIDHeader, id)
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+		})
+	}
+}
+
+// RequestIDFromContext returns the ID assigned to the request by the middleware, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
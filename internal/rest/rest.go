@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"go.opentelemetry.io/otel/trace"
@@ -18,29 +21,68 @@ type ErrorResponse struct {
 	Validations validation.Errors `json:"validations,omitempty"`
 }
 
-func renderErrorResponse(ctx context.Context, w http.ResponseWriter, msg string, err error) {
-	resp := ErrorResponse{Error: msg}
-	status := http.StatusInternalServerError
-
-	var ierr *internal.Error
-	if !errors.As(err, &ierr) {
-		resp.Error = "internal error"
-	} else {
-		switch ierr.Code() {
-		case internal.ErrCodeNotFound:
-			status = http.StatusNotFound
-		case internal.ErrCodeInvalidArgument:
-			status = http.StatusBadRequest
-
-			var verrors validation.Errors
-			if errors.As(ierr, &verrors) {
-				resp.Validations = verrors
-			}
-		case internal.ErrCodeUnknown:
-			fallthrough
-		default:
-			status = http.StatusInternalServerError
-		}
+const problemContentType = "application/problem+json"
+
+// ProblemDetails represents an error response following RFC 7807, it's returned when the request accepts
+// "application/problem+json".
+type ProblemDetails struct {
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Status    int                    `json:"status"`
+	Detail    string                 `json:"detail,omitempty"`
+	Instance  string                 `json:"instance,omitempty"`
+	Code      string                 `json:"code"`
+	RequestID string                 `json:"request_id,omitempty"`
+	TraceID   string                 `json:"trace_id,omitempty"`
+	Errors    map[string]interface{} `json:"errors,omitempty"`
+}
+
+// problem defines the stable values used for each error code, clients branch on code. Existing values must not
+// change, new error codes add their own.
+type problem struct {
+	code  string
+	title string
+}
+
+func (p problem) typeURI() string {
+	return "urn:todo:problem:" + p.code
+}
+
+//nolint:gochecknoglobals
+var problems = map[internal.ErrorCode]problem{
+	internal.ErrCodeUnknown:            {"internal", "Internal Server Error"},
+	internal.ErrCodeNotFound:           {"not_found", "Not Found"},
+	internal.ErrCodeInvalidArgument:    {"invalid_argument", "Invalid Argument"},
+	internal.ErrCodeConflict:           {"conflict", "Conflict"},
+	internal.ErrCodeAlreadyExists:      {"already_exists", "Already Exists"},
+	internal.ErrCodeUnavailable:        {"unavailable", "Unavailable"},
+	internal.ErrCodeDeadlineExceeded:   {"deadline_exceeded", "Deadline Exceeded"},
+	internal.ErrCodeCanceled:           {"canceled", "Canceled"},
+	internal.ErrCodeUnauthenticated:    {"unauthenticated", "Unauthenticated"},
+	internal.ErrCodePermissionDenied:   {"permission_denied", "Permission Denied"},
+	internal.ErrCodeResourceExhausted:  {"resource_exhausted", "Resource Exhausted"},
+	internal.ErrCodeFailedPrecondition: {"failed_precondition", "Failed Precondition"},
+}
+
+func renderErrorResponse(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	ctx := r.Context()
+
+	// XXX: Context errors not wrapped by an *internal.Error are classified as well, see internal.ContextCode.
+	code := internal.Code(err)
+	if code == internal.ErrCodeUnknown {
+		msg = "internal error"
+	}
+
+	p, ok := problems[code]
+	if !ok {
+		p = problems[internal.ErrCodeUnknown]
+	}
+
+	status := code.HTTPStatus()
+
+	var verrors validation.Errors
+	if code == internal.ErrCodeInvalidArgument {
+		_ = errors.As(err, &verrors)
 	}
 
 	if err != nil {
@@ -50,12 +92,97 @@ func renderErrorResponse(ctx context.Context, w http.ResponseWriter, msg string,
 		span.RecordError(err)
 	}
 
-	renderResponse(ctx, w, resp, status)
+	if !acceptsProblem(r) {
+		renderResponse(ctx, w, ErrorResponse{Error: msg, Validations: verrors}, status)
+
+		return
+	}
+
+	resp := ProblemDetails{
+		Type:      p.typeURI(),
+		Title:     p.title,
+		Status:    status,
+		Detail:    msg,
+		Instance:  r.URL.Path,
+		Code:      p.code,
+		RequestID: RequestIDFromContext(ctx),
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		resp.TraceID = sc.TraceID().String()
+	}
+
+	if len(verrors) > 0 {
+		resp.Errors = nestErrors(verrors)
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+
+	renderJSON(ctx, w, resp, status)
+}
+
+// acceptsProblem indicates whether the client accepts RFC 7807 responses.
+func acceptsProblem(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, val := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(val))
+			if err != nil || mediaType != problemContentType {
+				continue
+			}
+
+			if q, ok := params["q"]; ok {
+				if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+					continue
+				}
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// nestErrors converts the validation errors into nested objects, keys using dots, like "dates.start", are nested
+// as well.
+func nestErrors(verrors validation.Errors) map[string]interface{} {
+	res := map[string]interface{}{}
+
+	for field, err := range verrors {
+		parts := strings.Split(field, ".")
+
+		parent := res
+
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := parent[part].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[part] = child
+			}
+
+			parent = child
+		}
+
+		key := parts[len(parts)-1]
+
+		var nested validation.Errors
+		if errors.As(err, &nested) {
+			parent[key] = nestErrors(nested)
+		} else {
+			parent[key] = err.Error()
+		}
+	}
+
+	return res
 }
 
 func renderResponse(ctx context.Context, w http.ResponseWriter, res interface{}, status int) {
 	w.Header().Set("Content-Type", "application/json")
 
+	renderJSON(ctx, w, res, status)
+}
+
+func renderJSON(ctx context.Context, w http.ResponseWriter, res interface{}, status int) {
 	content, err := json.Marshal(res)
 	if err != nil {
 		// XXX Do something with the error ;)
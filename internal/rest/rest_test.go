@@ -0,0 +1,214 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/rest"
+	"github.com/lrweck/todo/internal/rest/resttesting"
+)
+
+func TestProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		expectedStatus      int
+		expectedContentType string
+		expected            interface{}
+		target              interface{}
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(*resttesting.FakeTaskService)
+		accept string
+		output output
+	}{
+		{
+			"OK: problem+json with nested validations",
+			func(s *resttesting.FakeTaskService) {
+				s.CreateReturns(internal.Task{},
+					internal.WrapErrorf(validation.Errors{
+						"description": errors.New("cannot be blank"),
+						"dates": validation.Errors{
+							"start": errors.New("must be before due"),
+						},
+					}, internal.ErrCodeInvalidArgument, "params.Validate"))
+			},
+			"application/json, application/problem+json",
+			output{
+				http.StatusBadRequest,
+				"application/problem+json",
+				&rest.ProblemDetails{
+					Type:      "urn:todo:problem:invalid_argument",
+					Title:     "Invalid Argument",
+					Status:    http.StatusBadRequest,
+					Detail:    "create failed",
+					Instance:  "/v1/tasks",
+					Code:      "invalid_argument",
+					RequestID: "request-1",
+					Errors: map[string]interface{}{
+						"description": "cannot be blank",
+						"dates": map[string]interface{}{
+							"start": "must be before due",
+						},
+					},
+				},
+				&rest.ProblemDetails{},
+			},
+		},
+		{
+			"OK: problem+json unknown error",
+			func(s *resttesting.FakeTaskService) {
+				s.CreateReturns(internal.Task{}, errors.New("connection refused"))
+			},
+			"application/problem+json",
+			output{
+				http.StatusInternalServerError,
+				"application/problem+json",
+				&rest.ProblemDetails{
+					Type:      "urn:todo:problem:internal",
+					Title:     "Internal Server Error",
+					Status:    http.StatusInternalServerError,
+					Detail:    "internal error",
+					Instance:  "/v1/tasks",
+					Code:      "internal",
+					RequestID: "request-1",
+				},
+				&rest.ProblemDetails{},
+			},
+		},
+		{
+			"OK: problem+json unavailable",
+			func(s *resttesting.FakeTaskService) {
+				s.CreateReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeUnavailable, "repo not ready"))
+			},
+			"application/problem+json",
+			output{
+				http.StatusServiceUnavailable,
+				"application/problem+json",
+				&rest.ProblemDetails{
+					Type:      "urn:todo:problem:unavailable",
+					Title:     "Unavailable",
+					Status:    http.StatusServiceUnavailable,
+					Detail:    "create failed",
+					Instance:  "/v1/tasks",
+					Code:      "unavailable",
+					RequestID: "request-1",
+				},
+				&rest.ProblemDetails{},
+			},
+		},
+		{
+			"OK: problem+json context deadline",
+			func(s *resttesting.FakeTaskService) {
+				s.CreateReturns(internal.Task{}, fmt.Errorf("insert: %w", context.DeadlineExceeded))
+			},
+			"application/problem+json",
+			output{
+				http.StatusGatewayTimeout,
+				"application/problem+json",
+				&rest.ProblemDetails{
+					Type:      "urn:todo:problem:deadline_exceeded",
+					Title:     "Deadline Exceeded",
+					Status:    http.StatusGatewayTimeout,
+					Detail:    "create failed",
+					Instance:  "/v1/tasks",
+					Code:      "deadline_exceeded",
+					RequestID: "request-1",
+				},
+				&rest.ProblemDetails{},
+			},
+		},
+		{
+			"OK: legacy shape context canceled",
+			func(s *resttesting.FakeTaskService) {
+				s.CreateReturns(internal.Task{}, context.Canceled)
+			},
+			"application/json",
+			output{
+				499,
+				"application/json",
+				&rest.ErrorResponse{
+					Error: "create failed",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+		{
+			"OK: legacy shape already exists",
+			func(s *resttesting.FakeTaskService) {
+				s.CreateReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeAlreadyExists, "duplicated"))
+			},
+			"application/json",
+			output{
+				http.StatusConflict,
+				"application/json",
+				&rest.ErrorResponse{
+					Error: "create failed",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+		{
+			"OK: legacy shape",
+			func(s *resttesting.FakeTaskService) {
+				s.CreateReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeNotFound, "not found"))
+			},
+			"application/json, application/problem+json;q=0",
+			output{
+				http.StatusNotFound,
+				"application/json",
+				&rest.ErrorResponse{
+					Error: "create failed",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := mux.NewRouter()
+			router.Use(rest.NewRequestIDMiddleware())
+
+			svc := &resttesting.FakeTaskService{}
+			tt.setup(svc)
+
+			rest.NewTaskHandler(svc).Register(router)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/tasks", bytes.NewBufferString(`{"description":"x"}`))
+			req.Header.Set("Accept", tt.accept)
+			req.Header.Set("X-Request-Id", "request-1")
+
+			res := doRequest(router, req)
+
+			if ct := res.Header.Get("Content-Type"); ct != tt.output.expectedContentType {
+				t.Fatalf("expected content type %s, actual %s", tt.output.expectedContentType, ct)
+			}
+
+			if id := res.Header.Get("X-Request-Id"); id != "request-1" {
+				t.Fatalf("expected request id, actual %s", id)
+			}
+
+			assertResponse(t, res, test{tt.output.expected, tt.output.target})
+
+			if tt.output.expectedStatus != res.StatusCode {
+				t.Fatalf("expected code %d, actual %d", tt.output.expectedStatus, res.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package resttesting
+
+import (
+	"sync"
+
+	"github.com/lrweck/todo/internal/rest"
+)
+
+type FakeBreakerReporter struct {
+	BreakerStatesStub        func() map[string]string
+	breakerStatesMutex       sync.RWMutex
+	breakerStatesArgsForCall []struct {
+	}
+	breakerStatesReturns struct {
+		result1 map[string]string
+	}
+	breakerStatesReturnsOnCall map[int]struct {
+		result1 map[string]string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBreakerReporter) BreakerStates() map[string]string {
+	fake.breakerStatesMutex.Lock()
+	ret, specificReturn := fake.breakerStatesReturnsOnCall[len(fake.breakerStatesArgsForCall)]
+	fake.breakerStatesArgsForCall = append(fake.breakerStatesArgsForCall, struct {
+	}{})
+	stub := fake.BreakerStatesStub
+	fakeReturns := fake.breakerStatesReturns
+	fake.recordInvocation("BreakerStates", []interface{}{})
+	fake.breakerStatesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBreakerReporter) BreakerStatesCallCount() int {
+	fake.breakerStatesMutex.RLock()
+	defer fake.breakerStatesMutex.RUnlock()
+	return len(fake.breakerStatesArgsForCall)
+}
+
+func (fake *FakeBreakerReporter) BreakerStatesCalls(stub func() map[string]string) {
+	fake.breakerStatesMutex.Lock()
+	defer fake.breakerStatesMutex.Unlock()
+	fake.BreakerStatesStub = stub
+}
+
+func (fake *FakeBreakerReporter) BreakerStatesReturns(result1 map[string]string) {
+	fake.breakerStatesMutex.Lock()
+	defer fake.breakerStatesMutex.Unlock()
+	fake.BreakerStatesStub = nil
+	fake.breakerStatesReturns = struct {
+		result1 map[string]string
+	}{result1}
+}
+
+func (fake *FakeBreakerReporter) BreakerStatesReturnsOnCall(i int, result1 map[string]string) {
+	fake.breakerStatesMutex.Lock()
+	defer fake.breakerStatesMutex.Unlock()
+	fake.BreakerStatesStub = nil
+	if fake.breakerStatesReturnsOnCall == nil {
+		fake.breakerStatesReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+		})
+	}
+	fake.breakerStatesReturnsOnCall[i] = struct {
+		result1 map[string]string
+	}{result1}
+}
+
+func (fake *FakeBreakerReporter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.breakerStatesMutex.RLock()
+	defer fake.breakerStatesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeBreakerReporter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ rest.BreakerReporter = new(FakeBreakerReporter)
@@ -0,0 +1,172 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package resttesting
+
+import (
+	"sync"
+
+	"github.com/lrweck/todo/internal/rest"
+)
+
+type FakeConfigReporter struct {
+	SourcesStub        func() map[string]string
+	sourcesMutex       sync.RWMutex
+	sourcesArgsForCall []struct {
+	}
+	sourcesReturns struct {
+		result1 map[string]string
+	}
+	sourcesReturnsOnCall map[int]struct {
+		result1 map[string]string
+	}
+	ValuesStub        func() (map[string]string, error)
+	valuesMutex       sync.RWMutex
+	valuesArgsForCall []struct {
+	}
+	valuesReturns struct {
+		result1 map[string]string
+		result2 error
+	}
+	valuesReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeConfigReporter) Sources() map[string]string {
+	fake.sourcesMutex.Lock()
+	ret, specificReturn := fake.sourcesReturnsOnCall[len(fake.sourcesArgsForCall)]
+	fake.sourcesArgsForCall = append(fake.sourcesArgsForCall, struct {
+	}{})
+	stub := fake.SourcesStub
+	fakeReturns := fake.sourcesReturns
+	fake.recordInvocation("Sources", []interface{}{})
+	fake.sourcesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConfigReporter) SourcesCallCount() int {
+	fake.sourcesMutex.RLock()
+	defer fake.sourcesMutex.RUnlock()
+	return len(fake.sourcesArgsForCall)
+}
+
+func (fake *FakeConfigReporter) SourcesCalls(stub func() map[string]string) {
+	fake.sourcesMutex.Lock()
+	defer fake.sourcesMutex.Unlock()
+	fake.SourcesStub = stub
+}
+
+func (fake *FakeConfigReporter) SourcesReturns(result1 map[string]string) {
+	fake.sourcesMutex.Lock()
+	defer fake.sourcesMutex.Unlock()
+	fake.SourcesStub = nil
+	fake.sourcesReturns = struct {
+		result1 map[string]string
+	}{result1}
+}
+
+func (fake *FakeConfigReporter) SourcesReturnsOnCall(i int, result1 map[string]string) {
+	fake.sourcesMutex.Lock()
+	defer fake.sourcesMutex.Unlock()
+	fake.SourcesStub = nil
+	if fake.sourcesReturnsOnCall == nil {
+		fake.sourcesReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+		})
+	}
+	fake.sourcesReturnsOnCall[i] = struct {
+		result1 map[string]string
+	}{result1}
+}
+
+func (fake *FakeConfigReporter) Values() (map[string]string, error) {
+	fake.valuesMutex.Lock()
+	ret, specificReturn := fake.valuesReturnsOnCall[len(fake.valuesArgsForCall)]
+	fake.valuesArgsForCall = append(fake.valuesArgsForCall, struct {
+	}{})
+	stub := fake.ValuesStub
+	fakeReturns := fake.valuesReturns
+	fake.recordInvocation("Values", []interface{}{})
+	fake.valuesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeConfigReporter) ValuesCallCount() int {
+	fake.valuesMutex.RLock()
+	defer fake.valuesMutex.RUnlock()
+	return len(fake.valuesArgsForCall)
+}
+
+func (fake *FakeConfigReporter) ValuesCalls(stub func() (map[string]string, error)) {
+	fake.valuesMutex.Lock()
+	defer fake.valuesMutex.Unlock()
+	fake.ValuesStub = stub
+}
+
+func (fake *FakeConfigReporter) ValuesReturns(result1 map[string]string, result2 error) {
+	fake.valuesMutex.Lock()
+	defer fake.valuesMutex.Unlock()
+	fake.ValuesStub = nil
+	fake.valuesReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConfigReporter) ValuesReturnsOnCall(i int, result1 map[string]string, result2 error) {
+	fake.valuesMutex.Lock()
+	defer fake.valuesMutex.Unlock()
+	fake.ValuesStub = nil
+	if fake.valuesReturnsOnCall == nil {
+		fake.valuesReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 error
+		})
+	}
+	fake.valuesReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConfigReporter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.sourcesMutex.RLock()
+	defer fake.sourcesMutex.RUnlock()
+	fake.valuesMutex.RLock()
+	defer fake.valuesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeConfigReporter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ rest.ConfigReporter = new(FakeConfigReporter)
@@ -0,0 +1,290 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package resttesting
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/rest"
+)
+
+type FakeIdempotencyStore struct {
+	LockStub        func(context.Context, string, string, time.Time) (internal.IdempotencyRecord, bool, error)
+	lockMutex       sync.RWMutex
+	lockArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+	}
+	lockReturns struct {
+		result1 internal.IdempotencyRecord
+		result2 bool
+		result3 error
+	}
+	lockReturnsOnCall map[int]struct {
+		result1 internal.IdempotencyRecord
+		result2 bool
+		result3 error
+	}
+	ReleaseStub        func(context.Context, string, string) error
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	releaseReturns struct {
+		result1 error
+	}
+	releaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SaveStub        func(context.Context, string, string, internal.IdempotentResponse, time.Time) error
+	saveMutex       sync.RWMutex
+	saveArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 internal.IdempotentResponse
+		arg5 time.Time
+	}
+	saveReturns struct {
+		result1 error
+	}
+	saveReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeIdempotencyStore) Lock(arg1 context.Context, arg2 string, arg3 string, arg4 time.Time) (internal.IdempotencyRecord, bool, error) {
+	fake.lockMutex.Lock()
+	ret, specificReturn := fake.lockReturnsOnCall[len(fake.lockArgsForCall)]
+	fake.lockArgsForCall = append(fake.lockArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.LockStub
+	fakeReturns := fake.lockReturns
+	fake.recordInvocation("Lock", []interface{}{arg1, arg2, arg3, arg4})
+	fake.lockMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeIdempotencyStore) LockCallCount() int {
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	return len(fake.lockArgsForCall)
+}
+
+func (fake *FakeIdempotencyStore) LockCalls(stub func(context.Context, string, string, time.Time) (internal.IdempotencyRecord, bool, error)) {
+	fake.lockMutex.Lock()
+	defer fake.lockMutex.Unlock()
+	fake.LockStub = stub
+}
+
+func (fake *FakeIdempotencyStore) LockArgsForCall(i int) (context.Context, string, string, time.Time) {
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	argsForCall := fake.lockArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeIdempotencyStore) LockReturns(result1 internal.IdempotencyRecord, result2 bool, result3 error) {
+	fake.lockMutex.Lock()
+	defer fake.lockMutex.Unlock()
+	fake.LockStub = nil
+	fake.lockReturns = struct {
+		result1 internal.IdempotencyRecord
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeIdempotencyStore) LockReturnsOnCall(i int, result1 internal.IdempotencyRecord, result2 bool, result3 error) {
+	fake.lockMutex.Lock()
+	defer fake.lockMutex.Unlock()
+	fake.LockStub = nil
+	if fake.lockReturnsOnCall == nil {
+		fake.lockReturnsOnCall = make(map[int]struct {
+			result1 internal.IdempotencyRecord
+			result2 bool
+			result3 error
+		})
+	}
+	fake.lockReturnsOnCall[i] = struct {
+		result1 internal.IdempotencyRecord
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeIdempotencyStore) Release(arg1 context.Context, arg2 string, arg3 string) error {
+	fake.releaseMutex.Lock()
+	ret, specificReturn := fake.releaseReturnsOnCall[len(fake.releaseArgsForCall)]
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ReleaseStub
+	fakeReturns := fake.releaseReturns
+	fake.recordInvocation("Release", []interface{}{arg1, arg2, arg3})
+	fake.releaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeIdempotencyStore) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *FakeIdempotencyStore) ReleaseCalls(stub func(context.Context, string, string) error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = stub
+}
+
+func (fake *FakeIdempotencyStore) ReleaseArgsForCall(i int) (context.Context, string, string) {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	argsForCall := fake.releaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeIdempotencyStore) ReleaseReturns(result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIdempotencyStore) ReleaseReturnsOnCall(i int, result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	if fake.releaseReturnsOnCall == nil {
+		fake.releaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIdempotencyStore) Save(arg1 context.Context, arg2 string, arg3 string, arg4 internal.IdempotentResponse, arg5 time.Time) error {
+	fake.saveMutex.Lock()
+	ret, specificReturn := fake.saveReturnsOnCall[len(fake.saveArgsForCall)]
+	fake.saveArgsForCall = append(fake.saveArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 internal.IdempotentResponse
+		arg5 time.Time
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.SaveStub
+	fakeReturns := fake.saveReturns
+	fake.recordInvocation("Save", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.saveMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeIdempotencyStore) SaveCallCount() int {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return len(fake.saveArgsForCall)
+}
+
+func (fake *FakeIdempotencyStore) SaveCalls(stub func(context.Context, string, string, internal.IdempotentResponse, time.Time) error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = stub
+}
+
+func (fake *FakeIdempotencyStore) SaveArgsForCall(i int) (context.Context, string, string, internal.IdempotentResponse, time.Time) {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	argsForCall := fake.saveArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeIdempotencyStore) SaveReturns(result1 error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = nil
+	fake.saveReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIdempotencyStore) SaveReturnsOnCall(i int, result1 error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = nil
+	if fake.saveReturnsOnCall == nil {
+		fake.saveReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeIdempotencyStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeIdempotencyStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ rest.IdempotencyStore = new(FakeIdempotencyStore)
@@ -50,6 +50,18 @@ type FakeTaskService struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RestoreStub        func(context.Context, string) error
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	restoreReturns struct {
+		result1 error
+	}
+	restoreReturnsOnCall map[int]struct {
+		result1 error
+	}
 	TaskStub        func(context.Context, string) (internal.Task, error)
 	taskMutex       sync.RWMutex
 	taskArgsForCall []struct {
@@ -64,6 +76,20 @@ type FakeTaskService struct {
 		result1 internal.Task
 		result2 error
 	}
+	TrashStub        func(context.Context, internal.TrashParams) (internal.TrashResults, error)
+	trashMutex       sync.RWMutex
+	trashArgsForCall []struct {
+		arg1 context.Context
+		arg2 internal.TrashParams
+	}
+	trashReturns struct {
+		result1 internal.TrashResults
+		result2 error
+	}
+	trashReturnsOnCall map[int]struct {
+		result1 internal.TrashResults
+		result2 error
+	}
 	UpdateStub        func(context.Context, string, string, internal.Priority, internal.Dates, bool) error
 	updateMutex       sync.RWMutex
 	updateArgsForCall []struct {
@@ -276,6 +302,68 @@ func (fake *FakeTaskService) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeTaskService) Restore(arg1 context.Context, arg2 string) error {
+	fake.restoreMutex.Lock()
+	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RestoreStub
+	fakeReturns := fake.restoreReturns
+	fake.recordInvocation("Restore", []interface{}{arg1, arg2})
+	fake.restoreMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeTaskService) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeTaskService) RestoreCalls(stub func(context.Context, string) error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = stub
+}
+
+func (fake *FakeTaskService) RestoreArgsForCall(i int) (context.Context, string) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskService) RestoreReturns(result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskService) RestoreReturnsOnCall(i int, result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	if fake.restoreReturnsOnCall == nil {
+		fake.restoreReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeTaskService) Task(arg1 context.Context, arg2 string) (internal.Task, error) {
 	fake.taskMutex.Lock()
 	ret, specificReturn := fake.taskReturnsOnCall[len(fake.taskArgsForCall)]
@@ -341,6 +429,71 @@ func (fake *FakeTaskService) TaskReturnsOnCall(i int, result1 internal.Task, res
 	}{result1, result2}
 }
 
+func (fake *FakeTaskService) Trash(arg1 context.Context, arg2 internal.TrashParams) (internal.TrashResults, error) {
+	fake.trashMutex.Lock()
+	ret, specificReturn := fake.trashReturnsOnCall[len(fake.trashArgsForCall)]
+	fake.trashArgsForCall = append(fake.trashArgsForCall, struct {
+		arg1 context.Context
+		arg2 internal.TrashParams
+	}{arg1, arg2})
+	stub := fake.TrashStub
+	fakeReturns := fake.trashReturns
+	fake.recordInvocation("Trash", []interface{}{arg1, arg2})
+	fake.trashMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTaskService) TrashCallCount() int {
+	fake.trashMutex.RLock()
+	defer fake.trashMutex.RUnlock()
+	return len(fake.trashArgsForCall)
+}
+
+func (fake *FakeTaskService) TrashCalls(stub func(context.Context, internal.TrashParams) (internal.TrashResults, error)) {
+	fake.trashMutex.Lock()
+	defer fake.trashMutex.Unlock()
+	fake.TrashStub = stub
+}
+
+func (fake *FakeTaskService) TrashArgsForCall(i int) (context.Context, internal.TrashParams) {
+	fake.trashMutex.RLock()
+	defer fake.trashMutex.RUnlock()
+	argsForCall := fake.trashArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskService) TrashReturns(result1 internal.TrashResults, result2 error) {
+	fake.trashMutex.Lock()
+	defer fake.trashMutex.Unlock()
+	fake.TrashStub = nil
+	fake.trashReturns = struct {
+		result1 internal.TrashResults
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskService) TrashReturnsOnCall(i int, result1 internal.TrashResults, result2 error) {
+	fake.trashMutex.Lock()
+	defer fake.trashMutex.Unlock()
+	fake.TrashStub = nil
+	if fake.trashReturnsOnCall == nil {
+		fake.trashReturnsOnCall = make(map[int]struct {
+			result1 internal.TrashResults
+			result2 error
+		})
+	}
+	fake.trashReturnsOnCall[i] = struct {
+		result1 internal.TrashResults
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTaskService) Update(arg1 context.Context, arg2 string, arg3 string, arg4 internal.Priority, arg5 internal.Dates, arg6 bool) error {
 	fake.updateMutex.Lock()
 	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
@@ -416,8 +569,12 @@ func (fake *FakeTaskService) Invocations() map[string][][]interface{} {
 	defer fake.createMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
 	fake.taskMutex.RLock()
 	defer fake.taskMutex.RUnlock()
+	fake.trashMutex.RLock()
+	defer fake.trashMutex.RUnlock()
 	fake.updateMutex.RLock()
 	defer fake.updateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
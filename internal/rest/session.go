@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal/repository/postgresql"
+)
+
+// NewSessionMiddleware returns the middleware using the request ID as the postgresql.Router session, so reads
+// following a write in the same request use the primary instead of a replica that may be lagging. It must be
+// used after the one returned by NewRequestIDMiddleware.
+func NewSessionMiddleware() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := RequestIDFromContext(r.Context())
+			if id == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(postgresql.WithSession(r.Context(), id)))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal/repository/postgresql"
+	"github.com/lrweck/todo/internal/repository/postgresql/postgresqltesting"
+	"github.com/lrweck/todo/internal/rest"
+)
+
+func TestSessionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		primary int
+		replica int
+	}
+
+	tests := []struct {
+		name   string
+		write  bool
+		output output
+	}{
+		{
+			"OK: read",
+			false,
+			output{
+				replica: 1,
+			},
+		},
+		{
+			"OK: read after write",
+			true,
+			output{
+				primary: 2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			primary := &postgresqltesting.FakeConn{}
+			replica := &postgresqltesting.FakeConn{}
+
+			db := postgresql.NewRouter(zap.NewNop(), primary, []postgresql.Replica{
+				{Name: "replica", Conn: replica, Weight: 1},
+			})
+
+			router := mux.NewRouter()
+			router.Use(rest.NewRequestIDMiddleware(), rest.NewSessionMiddleware())
+			router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				if tt.write {
+					_, _ = db.Exec(r.Context(), "UPDATE tasks SET done = true")
+				}
+
+				_ = db.QueryRow(r.Context(), "SELECT 1")
+
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			res := doRequest(router, httptest.NewRequest(http.MethodGet, "/", nil))
+			defer res.Body.Close()
+
+			actual := output{
+				primary: primary.ExecCallCount() + primary.QueryRowCallCount(),
+				replica: replica.QueryRowCallCount(),
+			}
+
+			if actual != tt.output {
+				t.Fatalf("expected %+v calls, got %+v", tt.output, actual)
+			}
+		})
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	router "github.com/gorilla/mux"
 	"github.com/lrweck/todo/internal"
@@ -12,6 +15,9 @@ import (
 
 const uuidRegEx string = `[0-9a-fA-F]{8}\-[0-9a-fA-F]{4}\-[0-9a-fA-F]{4}\-[0-9a-fA-F]{4}\-[0-9a-fA-F]{12}`
 
+// maxTrashSize is the maximum number of trashed tasks returned at once.
+const maxTrashSize = 100
+
 //go:generate counterfeiter -generate
 
 //counterfeiter:generate -o resttesting/task_service.gen.go . TaskService
@@ -21,30 +27,86 @@ type TaskService interface {
 	By(ctx context.Context, args internal.SearchParams) (internal.SearchResults, error)
 	Create(ctx context.Context, params internal.CreateParams) (internal.Task, error)
 	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
 	Task(ctx context.Context, id string) (internal.Task, error)
+	Trash(ctx context.Context, args internal.TrashParams) (internal.TrashResults, error)
 	Update(ctx context.Context, id, description string, priority internal.Priority, dates internal.Dates, isDone bool) error
 }
 
 // TaskHandler ...
 type TaskHandler struct {
-	svc TaskService
+	svc          TaskService
+	deprecations map[string]Deprecation
+}
+
+// TaskHandlerOption defines the options used by TaskHandler.
+type TaskHandlerOption func(*TaskHandler)
+
+// WithDeprecation marks all the operations of the API version as deprecated, by default only the unversioned
+// routes are.
+func WithDeprecation(version string, deprecation Deprecation) TaskHandlerOption {
+	return func(t *TaskHandler) {
+		t.deprecations[version] = deprecation
+	}
 }
 
 // NewTaskHandler ...
-func NewTaskHandler(svc TaskService) *TaskHandler {
-	return &TaskHandler{
+func NewTaskHandler(svc TaskService, opts ...TaskHandlerOption) *TaskHandler {
+	res := &TaskHandler{
 		svc: svc,
+		deprecations: map[string]Deprecation{
+			APIUnversioned: {
+				Successor: "/" + APIVersion1 + "{path}",
+			},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(res)
 	}
+
+	return res
 }
 
-// Register connects the handlers to the router.
+// Register connects the handlers to the router, each API version is mounted in its own subrouter sharing the
+// same service.
 func (t *TaskHandler) Register(r *router.Router) {
+	t.registerV1(r.PathPrefix("/"+APIVersion1).Subrouter(), t.handlerFunc(APIVersion1))
+	t.registerV2(r.PathPrefix("/"+APIVersion2).Subrouter(), t.handlerFunc(APIVersion2))
 
-	r.HandleFunc("/tasks", t.create).Methods(http.MethodPost)
-	r.HandleFunc(fmt.Sprintf("/task/{id:%s}", uuidRegEx), t.task).Methods(http.MethodGet)
-	r.HandleFunc(fmt.Sprintf("/task/{id:%s}", uuidRegEx), t.update).Methods(http.MethodPut)
-	r.HandleFunc(fmt.Sprintf("/task/{id:%s}", uuidRegEx), t.delete).Methods(http.MethodDelete)
-	r.HandleFunc("/search/tasks", t.search).Methods(http.MethodPost)
+	// XXX: Unversioned routes are kept for existing integrations, they behave like v1.
+	t.registerV1(r, t.handlerFunc(APIUnversioned))
+}
+
+func (t *TaskHandler) registerV1(r *router.Router, h func(http.HandlerFunc) http.HandlerFunc) {
+	r.HandleFunc("/tasks", h(t.create)).Methods(http.MethodPost)
+	r.HandleFunc(fmt.Sprintf("/task/{id:%s}", uuidRegEx), h(t.task)).Methods(http.MethodGet)
+	r.HandleFunc(fmt.Sprintf("/task/{id:%s}", uuidRegEx), h(t.update)).Methods(http.MethodPut)
+	r.HandleFunc(fmt.Sprintf("/task/{id:%s}", uuidRegEx), h(t.delete)).Methods(http.MethodDelete)
+	r.HandleFunc(fmt.Sprintf("/task/{id:%s}/restore", uuidRegEx), h(t.restore)).Methods(http.MethodPost)
+	r.HandleFunc("/trash", h(t.trash)).Methods(http.MethodGet)
+	r.HandleFunc("/search/tasks", h(t.search)).Methods(http.MethodPost)
+}
+
+func (t *TaskHandler) registerV2(r *router.Router, h func(http.HandlerFunc) http.HandlerFunc) {
+	r.HandleFunc("/tasks", h(t.create)).Methods(http.MethodPost)
+	r.HandleFunc(fmt.Sprintf("/tasks/{id:%s}", uuidRegEx), h(t.task)).Methods(http.MethodGet)
+	r.HandleFunc(fmt.Sprintf("/tasks/{id:%s}", uuidRegEx), h(t.patch)).Methods(http.MethodPatch)
+	r.HandleFunc(fmt.Sprintf("/tasks/{id:%s}", uuidRegEx), h(t.delete)).Methods(http.MethodDelete)
+	r.HandleFunc(fmt.Sprintf("/tasks/{id:%s}/restore", uuidRegEx), h(t.restore)).Methods(http.MethodPost)
+	r.HandleFunc("/trash", h(t.trash)).Methods(http.MethodGet)
+	r.HandleFunc("/search/tasks", h(t.searchV2)).Methods(http.MethodPost)
+}
+
+// handlerFunc returns the function decorating the handlers of the API version, deprecated versions include the
+// deprecation headers.
+func (t *TaskHandler) handlerFunc(version string) func(http.HandlerFunc) http.HandlerFunc {
+	deprecation, ok := t.deprecations[version]
+	if !ok {
+		return func(h http.HandlerFunc) http.HandlerFunc { return h }
+	}
+
+	return deprecation.wrap
 }
 
 // Task is an activity that needs to be completed within a period of time.
@@ -71,7 +133,7 @@ type CreateTasksResponse struct {
 func (t *TaskHandler) create(w http.ResponseWriter, r *http.Request) {
 	var req CreateTasksRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		renderErrorResponse(r.Context(), w, "invalid request",
+		renderErrorResponse(w, r, "invalid request",
 			internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "json decoder"))
 
 		return
@@ -85,7 +147,7 @@ func (t *TaskHandler) create(w http.ResponseWriter, r *http.Request) {
 		Dates:       req.Dates.Convert(),
 	})
 	if err != nil {
-		renderErrorResponse(r.Context(), w, "create failed", err)
+		renderErrorResponse(w, r, "create failed", err)
 
 		return
 	}
@@ -108,7 +170,20 @@ func (t *TaskHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := router.Vars(r)["id"]
 
 	if err := t.svc.Delete(r.Context(), id); err != nil {
-		renderErrorResponse(r.Context(), w, "delete failed", err)
+		renderErrorResponse(w, r, "delete failed", err)
+
+		return
+	}
+
+	renderResponse(r.Context(), w, struct{}{}, http.StatusOK)
+}
+
+func (t *TaskHandler) restore(w http.ResponseWriter, r *http.Request) {
+	// NOTE: Safe to ignore error, because it's always defined.
+	id := router.Vars(r)["id"]
+
+	if err := t.svc.Restore(r.Context(), id); err != nil {
+		renderErrorResponse(w, r, "restore failed", err)
 
 		return
 	}
@@ -127,7 +202,7 @@ func (t *TaskHandler) task(w http.ResponseWriter, r *http.Request) {
 
 	task, err := t.svc.Task(r.Context(), id)
 	if err != nil {
-		renderErrorResponse(r.Context(), w, "find failed", err)
+		renderErrorResponse(w, r, "find failed", err)
 
 		return
 	}
@@ -157,7 +232,7 @@ type UpdateTasksRequest struct {
 func (t *TaskHandler) update(w http.ResponseWriter, r *http.Request) {
 	var req UpdateTasksRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		renderErrorResponse(r.Context(), w, "invalid request",
+		renderErrorResponse(w, r, "invalid request",
 			internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "json decoder"))
 
 		return
@@ -170,7 +245,7 @@ func (t *TaskHandler) update(w http.ResponseWriter, r *http.Request) {
 
 	err := t.svc.Update(r.Context(), id, req.Description, req.Priority.Convert(), req.Dates.Convert(), req.IsDone)
 	if err != nil {
-		renderErrorResponse(r.Context(), w, "update failed", err)
+		renderErrorResponse(w, r, "update failed", err)
 
 		return
 	}
@@ -194,12 +269,37 @@ type SearchTasksResponse struct {
 }
 
 func (t *TaskHandler) search(w http.ResponseWriter, r *http.Request) {
+	res, ok := t.by(w, r)
+	if !ok {
+		return
+	}
+
+	tasks := make([]Task, len(res.Tasks))
+
+	for i, task := range res.Tasks {
+		tasks[i].ID = task.ID
+		tasks[i].Description = task.Description
+		tasks[i].Priority = NewPriority(task.Priority)
+		tasks[i].Dates = NewDates(task.Dates)
+	}
+
+	renderResponse(r.Context(),
+		w,
+		&SearchTasksResponse{
+			Tasks: tasks,
+			Total: res.Total,
+		}, http.StatusOK)
+}
+
+// by decodes the search request and searches the tasks, when the returned bool is false the error response was
+// already rendered.
+func (t *TaskHandler) by(w http.ResponseWriter, r *http.Request) (internal.SearchResults, bool) {
 	var req SearchTasksRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		renderErrorResponse(r.Context(), w, "invalid request",
+		renderErrorResponse(w, r, "invalid request",
 			internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "json decoder"))
 
-		return
+		return internal.SearchResults{}, false
 	}
 
 	defer r.Body.Close()
@@ -219,24 +319,81 @@ func (t *TaskHandler) search(w http.ResponseWriter, r *http.Request) {
 		Size:        req.Size,
 	})
 	if err != nil {
-		renderErrorResponse(r.Context(), w, "search failed", err)
+		renderErrorResponse(w, r, "search failed", err)
+
+		return internal.SearchResults{}, false
+	}
+
+	return res, true
+}
+
+// TrashedTask is a Task that was deleted but can still be restored.
+type TrashedTask struct {
+	Task
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// ListTrashResponse defines the response returned back after listing the trash.
+type ListTrashResponse struct {
+	Tasks []TrashedTask `json:"tasks"`
+	Total int64         `json:"total"`
+}
+
+func (t *TaskHandler) trash(w http.ResponseWriter, r *http.Request) {
+	from, err := queryInt64(r, "from", 0, math.MaxInt32)
+	if err != nil {
+		renderErrorResponse(w, r, "invalid request", err)
 
 		return
 	}
 
-	tasks := make([]Task, len(res.Tasks))
+	size, err := queryInt64(r, "size", 10, maxTrashSize)
+	if err != nil {
+		renderErrorResponse(w, r, "invalid request", err)
+
+		return
+	}
+
+	res, err := t.svc.Trash(r.Context(), internal.TrashParams{
+		From: from,
+		Size: size,
+	})
+	if err != nil {
+		renderErrorResponse(w, r, "trash failed", err)
+
+		return
+	}
+
+	tasks := make([]TrashedTask, len(res.Tasks))
 
 	for i, task := range res.Tasks {
-		tasks[i].ID = task.ID
-		tasks[i].Description = task.Description
-		tasks[i].Priority = NewPriority(task.Priority)
-		tasks[i].Dates = NewDates(task.Dates)
+		tasks[i].ID = task.Task.ID
+		tasks[i].Description = task.Task.Description
+		tasks[i].Priority = NewPriority(task.Task.Priority)
+		tasks[i].Dates = NewDates(task.Task.Dates)
+		tasks[i].IsDone = task.Task.IsDone
+		tasks[i].DeletedAt = task.DeletedAt
 	}
 
 	renderResponse(r.Context(),
 		w,
-		&SearchTasksResponse{
+		&ListTrashResponse{
 			Tasks: tasks,
 			Total: res.Total,
 		}, http.StatusOK)
 }
+
+// queryInt64 returns the query parameter, it must be between 0 and max.
+func queryInt64(r *http.Request, name string, def, max int64) (int64, error) {
+	val := r.URL.Query().Get(name)
+	if val == "" {
+		return def, nil
+	}
+
+	res, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || res < 0 || res > max {
+		return 0, internal.NewErrorf(internal.ErrCodeInvalidArgument, "%s must be between 0 and %d", name, max)
+	}
+
+	return res, nil
+}
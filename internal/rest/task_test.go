@@ -415,6 +415,225 @@ func TestTasks_Update(t *testing.T) {
 	}
 }
 
+func TestTasks_Restore(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		expectedStatus int
+		expected       interface{}
+		target         interface{}
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(*resttesting.FakeTaskService)
+		output output
+	}{
+		{
+			"OK: 200",
+			func(s *resttesting.FakeTaskService) {},
+			output{
+				http.StatusOK,
+				&struct{}{},
+				&struct{}{},
+			},
+		},
+		{
+			"ERR: 404",
+			func(s *resttesting.FakeTaskService) {
+				s.RestoreReturns(internal.NewErrorf(internal.ErrCodeNotFound, "not found"))
+			},
+			output{
+				http.StatusNotFound,
+				&rest.ErrorResponse{
+					Error: "restore failed",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+		{
+			"ERR: 500",
+			func(s *resttesting.FakeTaskService) {
+				s.RestoreReturns(errors.New("service failed"))
+			},
+			output{
+				http.StatusInternalServerError,
+				&rest.ErrorResponse{
+					Error: "internal error",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+	}
+
+	//-
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := mux.NewRouter()
+			svc := &resttesting.FakeTaskService{}
+			tt.setup(svc)
+
+			rest.NewTaskHandler(svc).Register(router)
+
+			//-
+
+			res := doRequest(router,
+				httptest.NewRequest(http.MethodPost, "/task/aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee/restore", nil))
+
+			//-
+
+			assertResponse(t, res, test{tt.output.expected, tt.output.target})
+
+			if tt.output.expectedStatus != res.StatusCode {
+				t.Fatalf("expected code %d, actual %d", tt.output.expectedStatus, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestTasks_Trash(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		expectedStatus int
+		expected       interface{}
+		target         interface{}
+	}
+
+	deletedAt := time.Date(2021, 11, 6, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		setup  func(*resttesting.FakeTaskService)
+		input  string
+		output output
+	}{
+		{
+			"OK: 200",
+			func(s *resttesting.FakeTaskService) {
+				s.TrashReturns(
+					internal.TrashResults{
+						Tasks: []internal.TrashedTask{
+							{
+								Task: internal.Task{
+									ID:          "a-b-c",
+									Description: "deleted task",
+									Priority:    internal.PriorityLow,
+								},
+								DeletedAt: deletedAt,
+							},
+						},
+						Total: 1,
+					},
+					nil)
+			},
+			"/trash?from=0&size=5",
+			output{
+				http.StatusOK,
+				&rest.ListTrashResponse{
+					Tasks: []rest.TrashedTask{
+						{
+							Task: rest.Task{
+								ID:          "a-b-c",
+								Description: "deleted task",
+								Priority:    "low",
+							},
+							DeletedAt: deletedAt,
+						},
+					},
+					Total: 1,
+				},
+				&rest.ListTrashResponse{},
+			},
+		},
+		{
+			"ERR: 400",
+			func(*resttesting.FakeTaskService) {},
+			"/trash?size=x",
+			output{
+				http.StatusBadRequest,
+				&rest.ErrorResponse{
+					Error: "invalid request",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+		{
+			"ERR: 400, size too large",
+			func(*resttesting.FakeTaskService) {},
+			"/trash?size=101",
+			output{
+				http.StatusBadRequest,
+				&rest.ErrorResponse{
+					Error: "invalid request",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+		{
+			"ERR: 400, from overflows",
+			func(*resttesting.FakeTaskService) {},
+			"/trash?from=2147483648",
+			output{
+				http.StatusBadRequest,
+				&rest.ErrorResponse{
+					Error: "invalid request",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+		{
+			"ERR: 500",
+			func(s *resttesting.FakeTaskService) {
+				s.TrashReturns(internal.TrashResults{},
+					errors.New("service error"))
+			},
+			"/trash",
+			output{
+				http.StatusInternalServerError,
+				&rest.ErrorResponse{
+					Error: "internal error",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+	}
+
+	//-
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := mux.NewRouter()
+			svc := &resttesting.FakeTaskService{}
+			tt.setup(svc)
+
+			rest.NewTaskHandler(svc).Register(router)
+
+			//-
+
+			res := doRequest(router,
+				httptest.NewRequest(http.MethodGet, tt.input, nil))
+
+			//-
+
+			assertResponse(t, res, test{tt.output.expected, tt.output.target})
+
+			if tt.output.expectedStatus != res.StatusCode {
+				t.Fatalf("expected code %d, actual %d", tt.output.expectedStatus, res.StatusCode)
+			}
+		})
+	}
+}
+
 type test struct {
 	expected interface{}
 	target   interface{}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	router "github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// PatchTasksRequest defines the request used for partially updating a task, only non-nil fields are updated.
+type PatchTasksRequest struct {
+	Description *string   `json:"description"`
+	IsDone      *bool     `json:"is_done"`
+	Priority    *Priority `json:"priority"`
+	Dates       *Dates    `json:"dates"`
+}
+
+func (t *TaskHandler) patch(w http.ResponseWriter, r *http.Request) {
+	var req PatchTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		renderErrorResponse(w, r, "invalid request",
+			internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "json decoder"))
+
+		return
+	}
+
+	defer r.Body.Close()
+
+	// NOTE: Safe to ignore error, because it's always defined.
+	id := router.Vars(r)["id"]
+
+	// XXX: Reading and updating is not atomic, concurrent patches to different fields may overwrite each other.
+	task, err := t.svc.Task(r.Context(), id)
+	if err != nil {
+		renderErrorResponse(w, r, "find failed", err)
+
+		return
+	}
+
+	if req.Description != nil {
+		task.Description = *req.Description
+	}
+
+	if req.IsDone != nil {
+		task.IsDone = *req.IsDone
+	}
+
+	if req.Priority != nil {
+		task.Priority = req.Priority.Convert()
+	}
+
+	if req.Dates != nil {
+		task.Dates = req.Dates.Convert()
+	}
+
+	if err := t.svc.Update(r.Context(), id, task.Description, task.Priority, task.Dates, task.IsDone); err != nil {
+		renderErrorResponse(w, r, "update failed", err)
+
+		return
+	}
+
+	renderResponse(r.Context(),
+		w,
+		&ReadTasksResponse{
+			Task: Task{
+				ID:          task.ID,
+				Description: task.Description,
+				Priority:    NewPriority(task.Priority),
+				Dates:       NewDates(task.Dates),
+				IsDone:      task.IsDone,
+			},
+		},
+		http.StatusOK)
+}
+
+func (t *TaskHandler) searchV2(w http.ResponseWriter, r *http.Request) {
+	res, ok := t.by(w, r)
+	if !ok {
+		return
+	}
+
+	tasks := make([]Task, len(res.Tasks))
+
+	for i, task := range res.Tasks {
+		tasks[i].ID = task.ID
+		tasks[i].Description = task.Description
+		tasks[i].Priority = NewPriority(task.Priority)
+		tasks[i].Dates = NewDates(task.Dates)
+		tasks[i].IsDone = task.IsDone
+	}
+
+	renderResponse(r.Context(),
+		w,
+		&SearchTasksResponse{
+			Tasks: tasks,
+			Total: res.Total,
+		}, http.StatusOK)
+}
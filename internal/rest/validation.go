@@ -0,0 +1,208 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// ValidationOption defines the options used by the validation middleware.
+type ValidationOption func(*validator)
+
+// WithResponseValidation enables validating the responses, meant to be used in development and testing
+// environments; responses not matching the specification are replaced with an internal error.
+func WithResponseValidation() ValidationOption {
+	return func(v *validator) {
+		v.responses = true
+	}
+}
+
+var defineFormats sync.Once
+
+type validator struct {
+	router    routers.Router
+	responses bool
+}
+
+// NewValidationMiddleware returns the middleware validating the requests against the OpenAPI 3 specification,
+// requests to routes not included in the specification are not validated.
+func NewValidationMiddleware(swagger openapi3.T, opts ...ValidationOption) (mux.MiddlewareFunc, error) {
+	defineFormats.Do(func() {
+		openapi3.DefineStringFormat("uuid", openapi3.FormatOfStringForUUIDOfRFC4122)
+	})
+
+	// XXX: The specification is loaded from its JSON representation to resolve the references and to get a
+	// copy that can be modified safely.
+	data, err := json.Marshal(&swagger)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "json.Marshal")
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "loader.LoadFromData")
+	}
+
+	// XXX: Servers are ignored to match any host serving the API.
+	doc.Servers = nil
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "doc.Validate")
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "gorillamux.NewRouter")
+	}
+
+	v := validator{router: router}
+
+	for _, opt := range opts {
+		opt(&v)
+	}
+
+	return v.middleware, nil
+}
+
+func (v *validator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, params, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		reqInput := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: params,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:         true,
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+
+		if err := openapi3filter.ValidateRequest(r.Context(), reqInput); err != nil {
+			renderErrorResponse(w, r, "invalid request",
+				internal.WrapErrorf(toValidationErrors(err), internal.ErrCodeInvalidArgument, "openapi3filter.ValidateRequest"))
+
+			return
+		}
+
+		if !v.responses {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		bw := &bufferedWriter{header: http.Header{}, status: http.StatusOK}
+
+		next.ServeHTTP(bw, r)
+
+		resInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: reqInput,
+			Status:                 bw.status,
+			Header:                 bw.header,
+			Body:                   io.NopCloser(bytes.NewReader(bw.body.Bytes())),
+			Options: &openapi3filter.Options{
+				MultiError:            true,
+				IncludeResponseStatus: true,
+			},
+		}
+
+		if err := openapi3filter.ValidateResponse(r.Context(), resInput); err != nil {
+			renderErrorResponse(w, r, "invalid response",
+				internal.WrapErrorf(err, internal.ErrCodeUnknown, "openapi3filter.ValidateResponse"))
+
+			return
+		}
+
+		bw.flush(w)
+	})
+}
+
+// toValidationErrors converts the errors returned by openapi3filter to field-level errors.
+func toValidationErrors(err error) validation.Errors {
+	res := validation.Errors{}
+
+	var addErrors func(field string, err error)
+
+	addErrors = func(field string, err error) {
+		// XXX: A type switch is used instead of errors.As because the errors are nested and the outermost one
+		// indicates the field.
+		switch verr := err.(type) {
+		case openapi3.MultiError:
+			for _, e := range verr {
+				addErrors(field, e)
+			}
+		case *openapi3filter.RequestError:
+			if verr.Parameter != nil {
+				field = verr.Parameter.Name
+			}
+
+			if verr.Err == nil {
+				res[field] = errors.New(verr.Reason)
+
+				return
+			}
+
+			addErrors(field, verr.Err)
+		case *openapi3.SchemaError:
+			if pointer := verr.JSONPointer(); len(pointer) > 0 && field == "body" {
+				field = strings.Join(pointer, ".")
+			}
+
+			res[field] = errors.New(verr.Reason)
+		default:
+			res[field] = err
+		}
+	}
+
+	addErrors("body", err)
+
+	return res
+}
+
+// bufferedWriter holds the response until it's validated.
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *bufferedWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedWriter) flush(w http.ResponseWriter) {
+	for k, v := range b.header {
+		w.Header()[k] = v
+	}
+
+	w.WriteHeader(b.status)
+
+	_, _ = w.Write(b.body.Bytes())
+}
@@ -0,0 +1,159 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/rest"
+	"github.com/lrweck/todo/internal/rest/resttesting"
+)
+
+func TestValidationMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type output struct {
+		expectedStatus int
+		expected       interface{}
+		target         interface{}
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(*resttesting.FakeTaskService)
+		req    func() *http.Request
+		output output
+	}{
+		{
+			"OK: 201",
+			func(s *resttesting.FakeTaskService) {
+				s.CreateReturns(
+					internal.Task{
+						ID:          "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+						Description: "new task",
+					},
+					nil)
+			},
+			func() *http.Request {
+				return newJSONRequest(http.MethodPost, "/v1/tasks", `{"description":"new task"}`)
+			},
+			output{
+				http.StatusCreated,
+				&rest.CreateTasksResponse{
+					Task: rest.Task{
+						ID:          "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+						Description: "new task",
+						Priority:    "none",
+					},
+				},
+				&rest.CreateTasksResponse{},
+			},
+		},
+		{
+			"ERR: 400 invalid fields",
+			func(*resttesting.FakeTaskService) {},
+			func() *http.Request {
+				return newJSONRequest(http.MethodPost, "/v1/tasks", `{"description":"","priority":"urgent"}`)
+			},
+			output{
+				http.StatusBadRequest,
+				map[string]interface{}{
+					"error": "invalid request",
+					"validations": map[string]interface{}{
+						"description": "minimum string length is 1",
+						"priority":    "value is not one of the allowed values",
+					},
+				},
+				&map[string]interface{}{},
+			},
+		},
+		{
+			"ERR: 400 invalid parameter",
+			func(*resttesting.FakeTaskService) {},
+			func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/v1/trash?size=-1", nil)
+			},
+			output{
+				http.StatusBadRequest,
+				map[string]interface{}{
+					"error": "invalid request",
+					"validations": map[string]interface{}{
+						"size": "number must be at least 0",
+					},
+				},
+				&map[string]interface{}{},
+			},
+		},
+		{
+			"ERR: 500 invalid response",
+			func(s *resttesting.FakeTaskService) {
+				s.CreateReturns(internal.Task{ID: "1-2-3", Description: "new task"}, nil)
+			},
+			func() *http.Request {
+				return newJSONRequest(http.MethodPost, "/v1/tasks", `{"description":"new task"}`)
+			},
+			output{
+				http.StatusInternalServerError,
+				map[string]interface{}{
+					"error": "internal error",
+				},
+				&map[string]interface{}{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := mux.NewRouter()
+
+			validator, err := rest.NewValidationMiddleware(rest.NewOpenAPI3(), rest.WithResponseValidation())
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			router.Use(validator)
+
+			svc := &resttesting.FakeTaskService{}
+			tt.setup(svc)
+
+			rest.NewTaskHandler(svc).Register(router)
+
+			res := doRequest(router, tt.req())
+			defer res.Body.Close()
+
+			if err := json.NewDecoder(res.Body).Decode(tt.output.target); err != nil {
+				t.Fatalf("couldn't decode %s", err)
+			}
+
+			actual := tt.output.target
+			if m, ok := actual.(*map[string]interface{}); ok {
+				actual = *m
+			}
+
+			if !cmp.Equal(tt.output.expected, actual) {
+				t.Fatalf("expected results don't match: %s", cmp.Diff(tt.output.expected, actual))
+			}
+
+			if tt.output.expectedStatus != res.StatusCode {
+				t.Fatalf("expected code %d, actual %d", tt.output.expectedStatus, res.StatusCode)
+			}
+		})
+	}
+}
+
+func newJSONRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	return req
+}
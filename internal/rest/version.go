@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// APIVersion1 is the first version of the API, its behavior is frozen.
+	APIVersion1 = "v1"
+
+	// APIVersion2 is the current version of the API.
+	APIVersion2 = "v2"
+
+	// APIUnversioned refers to the original routes without a version prefix, they behave like APIVersion1.
+	APIUnversioned = ""
+)
+
+// Deprecation indicates the operations of an API version are deprecated, it's signaled to clients using the
+// `Deprecation`, `Sunset` and `Link` response headers.
+type Deprecation struct {
+	// Since indicates when the version was deprecated, when zero it's signaled as "true".
+	Since time.Time
+
+	// Sunset indicates when the version will stop being available, optional.
+	Sunset time.Time
+
+	// Successor is the link to the version replacing this one, optional. "{path}" is replaced with the request
+	// path.
+	Successor string
+}
+
+func (d Deprecation) wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deprecation := "true"
+		if !d.Since.IsZero() {
+			deprecation = d.Since.UTC().Format(http.TimeFormat)
+		}
+
+		w.Header().Set("Deprecation", deprecation)
+
+		if !d.Sunset.IsZero() {
+			w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+
+		if d.Successor != "" {
+			successor := strings.ReplaceAll(d.Successor, "{path}", r.URL.Path)
+
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		}
+
+		next(w, r)
+	}
+}
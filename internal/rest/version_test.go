@@ -0,0 +1,202 @@
+package rest_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/rest"
+	"github.com/lrweck/todo/internal/rest/resttesting"
+)
+
+func TestTaskHandler_Deprecation(t *testing.T) {
+	t.Parallel()
+
+	const id = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+
+	sunset := time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	type output struct {
+		deprecation string
+		sunset      string
+		link        string
+	}
+
+	tests := []struct {
+		name   string
+		opts   []rest.TaskHandlerOption
+		target string
+		output output
+	}{
+		{
+			"OK: unversioned",
+			nil,
+			"/task/" + id,
+			output{
+				deprecation: "true",
+				link:        `</v1/task/` + id + `>; rel="successor-version"`,
+			},
+		},
+		{
+			"OK: v1",
+			nil,
+			"/v1/task/" + id,
+			output{},
+		},
+		{
+			"OK: v1 deprecated",
+			[]rest.TaskHandlerOption{
+				rest.WithDeprecation(rest.APIVersion1, rest.Deprecation{
+					Since:     time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC),
+					Sunset:    sunset,
+					Successor: "/v2/openapi3.json",
+				}),
+			},
+			"/v1/task/" + id,
+			output{
+				deprecation: "Wed, 01 Dec 2021 00:00:00 GMT",
+				sunset:      "Thu, 30 Jun 2022 00:00:00 GMT",
+				link:        `</v2/openapi3.json>; rel="successor-version"`,
+			},
+		},
+		{
+			"OK: v2",
+			nil,
+			"/v2/tasks/" + id,
+			output{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := mux.NewRouter()
+
+			rest.NewTaskHandler(&resttesting.FakeTaskService{}, tt.opts...).Register(router)
+
+			res := doRequest(router, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			defer res.Body.Close()
+
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("expected code %d, actual %d", http.StatusOK, res.StatusCode)
+			}
+
+			actual := output{
+				deprecation: res.Header.Get("Deprecation"),
+				sunset:      res.Header.Get("Sunset"),
+				link:        res.Header.Get("Link"),
+			}
+
+			if !cmp.Equal(tt.output, actual, cmp.AllowUnexported(output{})) {
+				t.Fatalf("expected headers don't match: %s", cmp.Diff(tt.output, actual, cmp.AllowUnexported(output{})))
+			}
+		})
+	}
+}
+
+func TestTasks_PatchV2(t *testing.T) {
+	t.Parallel()
+
+	const id = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+
+	type output struct {
+		expectedStatus int
+		expected       interface{}
+		target         interface{}
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(*resttesting.FakeTaskService)
+		body   string
+		verify func(*testing.T, *resttesting.FakeTaskService)
+		output output
+	}{
+		{
+			"OK: 200",
+			func(s *resttesting.FakeTaskService) {
+				s.TaskReturns(internal.Task{
+					ID:          id,
+					Description: "original",
+					Priority:    internal.PriorityLow,
+				}, nil)
+			},
+			`{"is_done":true}`,
+			func(t *testing.T, s *resttesting.FakeTaskService) {
+				t.Helper()
+
+				_, actualID, description, priority, _, isDone := s.UpdateArgsForCall(0)
+				if actualID != id || description != "original" || priority != internal.PriorityLow || !isDone {
+					t.Fatalf("unexpected update arguments: %s %s %d %t", actualID, description, priority, isDone)
+				}
+			},
+			output{
+				http.StatusOK,
+				&rest.ReadTasksResponse{
+					Task: rest.Task{
+						ID:          id,
+						Description: "original",
+						Priority:    "low",
+						IsDone:      true,
+					},
+				},
+				&rest.ReadTasksResponse{},
+			},
+		},
+		{
+			"ERR: 404",
+			func(s *resttesting.FakeTaskService) {
+				s.TaskReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeNotFound, "not found"))
+			},
+			`{"is_done":true}`,
+			func(t *testing.T, s *resttesting.FakeTaskService) {
+				t.Helper()
+
+				if s.UpdateCallCount() != 0 {
+					t.Fatalf("expected no updates")
+				}
+			},
+			output{
+				http.StatusNotFound,
+				&rest.ErrorResponse{
+					Error: "find failed",
+				},
+				&rest.ErrorResponse{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := mux.NewRouter()
+			svc := &resttesting.FakeTaskService{}
+			tt.setup(svc)
+
+			rest.NewTaskHandler(svc).Register(router)
+
+			res := doRequest(router,
+				httptest.NewRequest(http.MethodPatch, "/v2/tasks/"+id, bytes.NewBufferString(tt.body)))
+
+			assertResponse(t, res, test{tt.output.expected, tt.output.target})
+
+			if tt.output.expectedStatus != res.StatusCode {
+				t.Fatalf("expected code %d, actual %d", tt.output.expectedStatus, res.StatusCode)
+			}
+
+			tt.verify(t, svc)
+		})
+	}
+}
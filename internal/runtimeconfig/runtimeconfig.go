@@ -0,0 +1,319 @@
+// Package runtimeconfig implements the configuration that can be changed without redeploying, like log level,
+// cache expiration, circuit breaker thresholds and rate limits.
+package runtimeconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/envvar"
+)
+
+// Config defines the values that can be changed at runtime, they are decoded using envvar.
+type Config struct {
+	LogLevel        zapcore.Level   `env:"LOG_LEVEL" default:"info"`
+	CacheExpiration time.Duration   `env:"CACHE_EXPIRATION" default:"10m"`
+	Breaker         BreakerConfig   `prefix:"BREAKER_"`
+	RateLimit       RateLimitConfig `prefix:"RATE_LIMIT_"`
+}
+
+// BreakerConfig defines when the circuit breakers protecting the service dependencies open.
+type BreakerConfig struct {
+	ConsecutiveFailures int64         `env:"CONSECUTIVE_FAILURES" default:"3"`
+	OpenTimeout         time.Duration `env:"OPEN_TIMEOUT" default:"1m"`
+}
+
+// RateLimitConfig defines the requests allowed by the HTTP server, zero requests per second disables limiting.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `env:"REQUESTS_PER_SECOND" default:"0"`
+	Burst             int     `env:"BURST" default:"1"`
+}
+
+// Validate returns all the invalid values together in a *envvar.DecodeError.
+func (c Config) Validate() error {
+	var derr envvar.DecodeError
+
+	invalid := func(key, format string, args ...interface{}) {
+		derr.Fields = append(derr.Fields, envvar.FieldError{Key: key, Err: fmt.Errorf(format, args...)})
+	}
+
+	if c.CacheExpiration <= 0 {
+		invalid("CACHE_EXPIRATION", "must be positive, got %s", c.CacheExpiration)
+	}
+
+	if c.Breaker.ConsecutiveFailures <= 0 {
+		invalid("BREAKER_CONSECUTIVE_FAILURES", "must be positive, got %d", c.Breaker.ConsecutiveFailures)
+	}
+
+	if c.Breaker.OpenTimeout <= 0 {
+		invalid("BREAKER_OPEN_TIMEOUT", "must be positive, got %s", c.Breaker.OpenTimeout)
+	}
+
+	if c.RateLimit.RequestsPerSecond < 0 {
+		invalid("RATE_LIMIT_REQUESTS_PER_SECOND", "can't be negative, got %g", c.RateLimit.RequestsPerSecond)
+	}
+
+	if c.RateLimit.RequestsPerSecond > 0 && c.RateLimit.Burst <= 0 {
+		invalid("RATE_LIMIT_BURST", "must be positive when limiting, got %d", c.RateLimit.Burst)
+	}
+
+	if len(derr.Fields) > 0 {
+		return internal.WrapErrorf(&derr, internal.ErrCodeInvalidArgument, "invalid configuration")
+	}
+
+	return nil
+}
+
+// Manager keeps the current Config and notifies the subscribers when it changes.
+type Manager struct {
+	conf     *envvar.Configuration
+	filename string
+	interval time.Duration
+	logger   *zap.Logger
+
+	notifyMu sync.Mutex // Serializes notifications so subscribers see the changes in order.
+
+	mu          sync.RWMutex
+	current     Config
+	sources     map[string]string
+	subscribers map[int]func(Config)
+	nextID      int
+}
+
+// Option defines the options used by Manager.
+type Option func(*Manager)
+
+// WithInterval indicates how often the configuration is reloaded by Run when the file didn't change, defaults
+// to 30 seconds.
+func WithInterval(d time.Duration) Option {
+	return func(m *Manager) {
+		m.interval = d
+	}
+}
+
+// NewManager instantiates the Manager and loads the configuration. Values are read from the environment, using
+// conf so "<KEY>_SECURE" values come from its provider, and "filename" overrides them when it exists; it uses
+// the same format as the env files loaded by envvar.Load. An empty filename only uses the environment.
+func NewManager(logger *zap.Logger, conf *envvar.Configuration, filename string, opts ...Option) (*Manager, error) {
+	m := &Manager{
+		conf:        conf,
+		filename:    filename,
+		interval:    30 * time.Second,
+		logger:      logger,
+		subscribers: make(map[int]func(Config)),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	cfg, sources, err := m.load()
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.Code(err), "load")
+	}
+
+	m.current, m.sources = cfg, sources
+
+	return m, nil
+}
+
+// Current returns the configuration in use.
+func (m *Manager) Current() Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.current
+}
+
+// Values returns the configuration in use indexed by key, secrets are redacted.
+func (m *Manager) Values() (map[string]string, error) {
+	cfg := m.Current()
+
+	return envvar.Values(&cfg)
+}
+
+// Sources returns where each value in use comes from indexed by key, like "env", "default", "file:<filename>"
+// or "secure:<provider>".
+func (m *Manager) Sources() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make(map[string]string, len(m.sources))
+
+	for k, v := range m.sources {
+		res[k] = v
+	}
+
+	return res
+}
+
+// Subscribe registers fn to be called with the new configuration every time it changes, it is called right away
+// with the current one. The returned function unsubscribes.
+func (m *Manager) Subscribe(fn func(Config)) func() {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+
+	m.mu.Lock()
+	id := m.nextID
+	m.nextID++
+	m.subscribers[id] = fn
+	cfg := m.current
+	m.mu.Unlock()
+
+	fn(cfg)
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		delete(m.subscribers, id)
+	}
+}
+
+// Reload reads the configuration again, when all the values are valid and at least one changed it replaces the
+// one in use and notifies the subscribers. Invalid values are rejected as a whole, the current configuration is
+// kept.
+func (m *Manager) Reload() error {
+	cfg, sources, err := m.load()
+	if err != nil {
+		return internal.WrapErrorf(err, internal.Code(err), "load")
+	}
+
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+
+	m.mu.Lock()
+
+	prev := m.current
+	m.current, m.sources = cfg, sources
+
+	subscribers := make([]func(Config), 0, len(m.subscribers))
+
+	for _, id := range m.subscriberIDs() {
+		subscribers = append(subscribers, m.subscribers[id])
+	}
+
+	m.mu.Unlock()
+
+	if prev == cfg {
+		return nil
+	}
+
+	m.logger.Info("configuration changed", zap.Any("previous", prev), zap.Any("current", cfg))
+
+	for _, fn := range subscribers {
+		fn(cfg)
+	}
+
+	return nil
+}
+
+// Run reloads the configuration every time the file changes and periodically until the context is canceled.
+// Values coming from a provider, like Vault, are read again periodically as well; the provider decides when to
+// fetch them again. Only polling is used when the file can't be watched.
+//
+// XXX: The directory is watched instead of the file, so replacing it atomically, like Kubernetes does with
+// mounted ConfigMaps, is supported.
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	var events <-chan fsnotify.Event
+
+	if m.filename != "" {
+		watcher, err := m.watch()
+		if err != nil {
+			m.logger.Warn("watching configuration, polling only", zap.Error(err))
+		} else {
+			defer watcher.Close()
+
+			events = watcher.Events
+
+			go func() {
+				for err := range watcher.Errors {
+					m.logger.Warn("watching configuration", zap.Error(err))
+				}
+			}()
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-events:
+		}
+
+		if err := m.Reload(); err != nil {
+			m.logger.Error("reloading configuration", zap.Error(err))
+		}
+	}
+}
+
+// watch returns the watcher notifying about the changes made to the directory containing the file.
+func (m *Manager) watch() (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "fsnotify.NewWatcher")
+	}
+
+	if err := watcher.Add(filepath.Dir(m.filename)); err != nil {
+		_ = watcher.Close()
+
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "watcher.Add")
+	}
+
+	return watcher, nil
+}
+
+func (m *Manager) load() (Config, map[string]string, error) {
+	conf := m.conf
+
+	if m.filename != "" {
+		values, err := godotenv.Read(m.filename)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return Config{}, nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "godotenv.Read")
+		}
+
+		conf = conf.WithValues(envvar.SourceFile+":"+m.filename, values)
+	}
+
+	var cfg Config
+
+	sources, err := conf.DecodeWithSources(&cfg)
+	if err != nil {
+		return Config{}, nil, internal.WrapErrorf(err, internal.Code(err), "conf.DecodeWithSources")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, nil, err
+	}
+
+	return cfg, sources, nil
+}
+
+// subscriberIDs returns the IDs in the order they subscribed, it must be called holding the lock.
+func (m *Manager) subscriberIDs() []int {
+	res := make([]int, 0, len(m.subscribers))
+
+	for id := range m.subscribers {
+		res = append(res, id)
+	}
+
+	sort.Ints(res)
+
+	return res
+}
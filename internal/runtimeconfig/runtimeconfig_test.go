@@ -0,0 +1,233 @@
+package runtimeconfig_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/envvar"
+	"github.com/lrweck/todo/internal/envvar/envvartesting"
+	"github.com/lrweck/todo/internal/runtimeconfig"
+)
+
+func TestNewManager(t *testing.T) {
+	t.Parallel()
+
+	filename := writeFile(t, "", "LOG_LEVEL=debug\nRATE_LIMIT_REQUESTS_PER_SECOND=10\nRATE_LIMIT_BURST=20\n")
+
+	manager, err := runtimeconfig.NewManager(zap.NewNop(), envvar.New(&envvartesting.FakeProvider{}), filename)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := runtimeconfig.Config{
+		LogLevel:        zapcore.DebugLevel,
+		CacheExpiration: 10 * time.Minute,
+		Breaker: runtimeconfig.BreakerConfig{
+			ConsecutiveFailures: 3,
+			OpenTimeout:         time.Minute,
+		},
+		RateLimit: runtimeconfig.RateLimitConfig{
+			RequestsPerSecond: 10,
+			Burst:             20,
+		},
+	}
+
+	if diff := cmp.Diff(expected, manager.Current()); diff != "" {
+		t.Fatalf("expected config do not match: %s", diff)
+	}
+
+	values, err := manager.Values()
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if values["LOG_LEVEL"] != "debug" {
+		t.Fatalf("expected debug level, got %s", values["LOG_LEVEL"])
+	}
+
+	sources := manager.Sources()
+
+	if sources["LOG_LEVEL"] != "file:"+filename {
+		t.Fatalf("expected level from file, got %s", sources["LOG_LEVEL"])
+	}
+
+	if sources["CACHE_EXPIRATION"] != envvar.SourceDefault {
+		t.Fatalf("expected default cache expiration, got %s", sources["CACHE_EXPIRATION"])
+	}
+}
+
+func TestNewManager_Error(t *testing.T) {
+	t.Parallel()
+
+	filename := writeFile(t, "", "CACHE_EXPIRATION=0s\nBREAKER_CONSECUTIVE_FAILURES=-1\n")
+
+	_, err := runtimeconfig.NewManager(zap.NewNop(), envvar.New(&envvartesting.FakeProvider{}), filename)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+
+	var derr *envvar.DecodeError
+	if !errors.As(err, &derr) {
+		t.Fatalf("expected decode error, got %T", err)
+	}
+
+	if len(derr.Fields) != 2 {
+		t.Fatalf("expected 2 invalid fields, got %s", derr)
+	}
+}
+
+func TestManager_Reload(t *testing.T) {
+	t.Parallel()
+
+	filename := writeFile(t, "", "CACHE_EXPIRATION=5m\n")
+
+	manager, err := runtimeconfig.NewManager(zap.NewNop(), envvar.New(&envvartesting.FakeProvider{}), filename)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	var notified []time.Duration
+
+	unsubscribe := manager.Subscribe(func(cfg runtimeconfig.Config) {
+		notified = append(notified, cfg.CacheExpiration)
+	})
+
+	reload := func(content string) error {
+		t.Helper()
+
+		_ = writeFile(t, filename, content)
+
+		return manager.Reload()
+	}
+
+	// Unchanged values don't notify.
+	if err := reload("CACHE_EXPIRATION=5m\n"); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if err := reload("CACHE_EXPIRATION=1m\nBREAKER_OPEN_TIMEOUT=30s\n"); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	// Changes are applied atomically, the valid value is rejected together with the invalid one.
+	err = reload("CACHE_EXPIRATION=2m\nBREAKER_OPEN_TIMEOUT=-1s\n")
+	if internal.Code(err) != internal.ErrCodeInvalidArgument {
+		t.Fatalf("expected invalid argument error, got %v", err)
+	}
+
+	if cfg := manager.Current(); cfg.CacheExpiration != time.Minute || cfg.Breaker.OpenTimeout != 30*time.Second {
+		t.Fatalf("expected previous config to be kept, got %+v", cfg)
+	}
+
+	unsubscribe()
+
+	if err := reload("CACHE_EXPIRATION=3m\n"); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]time.Duration{5 * time.Minute, time.Minute}, notified); diff != "" {
+		t.Fatalf("expected notifications do not match: %s", diff)
+	}
+}
+
+func TestManager_Reload_Provider(t *testing.T) {
+	t.Parallel()
+
+	provider := &envvartesting.FakeProvider{}
+	provider.GetReturns("5m", nil)
+
+	filename := writeFile(t, "", "CACHE_EXPIRATION_SECURE=/cache/expiration\n")
+
+	manager, err := runtimeconfig.NewManager(zap.NewNop(), envvar.New(provider), filename)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if source := manager.Sources()["CACHE_EXPIRATION"]; source != envvar.SourceSecure {
+		t.Fatalf("expected value from provider, got %s", source)
+	}
+
+	var notified time.Duration
+
+	_ = manager.Subscribe(func(cfg runtimeconfig.Config) {
+		notified = cfg.CacheExpiration
+	})
+
+	provider.GetReturns("7m", nil)
+
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if notified != 7*time.Minute {
+		t.Fatalf("expected new value from provider, got %s", notified)
+	}
+}
+
+func TestManager_Run(t *testing.T) {
+	t.Parallel()
+
+	filename := writeFile(t, "", "CACHE_EXPIRATION=5m\n")
+
+	// The interval is long enough to make sure the change is noticed by watching the file.
+	manager, err := runtimeconfig.NewManager(zap.NewNop(), envvar.New(&envvartesting.FakeProvider{}), filename,
+		runtimeconfig.WithInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	notified := make(chan time.Duration, 10)
+
+	manager.Subscribe(func(cfg runtimeconfig.Config) {
+		notified <- cfg.CacheExpiration
+	})
+
+	<-notified
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go manager.Run(ctx)
+
+	deadline := time.After(5 * time.Second)
+
+	for {
+		// XXX: Written again until noticed, Run may not be watching yet.
+		_ = writeFile(t, filename, "CACHE_EXPIRATION=1m\n")
+
+		select {
+		case expiration := <-notified:
+			if expiration != time.Minute {
+				t.Fatalf("expected 1m expiration, got %s", expiration)
+			}
+
+			return
+		case <-deadline:
+			t.Fatalf("expected file change to be noticed")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func writeFile(t *testing.T, filename, content string) string {
+	t.Helper()
+
+	if filename == "" {
+		filename = filepath.Join(t.TempDir(), "runtime.env")
+	}
+
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatalf("couldn't write file: %s", err)
+	}
+
+	return filename
+}
@@ -0,0 +1,73 @@
+package runtimeconfig
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal/rest"
+	"github.com/lrweck/todo/internal/service"
+)
+
+// Policies returns base using the breaker thresholds in the configuration for every dependency, the operation
+// policies are kept.
+func (c Config) Policies(base service.Policies) service.Policies {
+	breaker := service.BreakerPolicy{
+		ConsecutiveFailures: c.Breaker.ConsecutiveFailures,
+		OpenTimeout:         c.Breaker.OpenTimeout,
+	}
+
+	res := service.Policies{
+		Operations: base.Operations,
+		Breakers: map[string]service.BreakerPolicy{
+			service.DependencyRepo:   breaker,
+			service.DependencySearch: breaker,
+			service.DependencyBroker: breaker,
+		},
+	}
+
+	for dependency := range base.Breakers {
+		res.Breakers[dependency] = breaker
+	}
+
+	return res
+}
+
+// PolicySetter defines the type that applies the service policies, like service.Task.
+type PolicySetter interface {
+	SetPolicies(policies service.Policies)
+}
+
+// SetPolicies returns the subscriber applying the breaker thresholds to setter, see Config.Policies.
+func SetPolicies(setter PolicySetter, base service.Policies) func(Config) {
+	return func(cfg Config) {
+		setter.SetPolicies(cfg.Policies(base))
+	}
+}
+
+// ExpirationSetter defines the type that caches values, like memcached.Task and rediscache.Task.
+type ExpirationSetter interface {
+	SetExpiration(d time.Duration)
+}
+
+// SetExpiration returns the subscriber changing how long setter caches values.
+func SetExpiration(setter ExpirationSetter) func(Config) {
+	return func(cfg Config) {
+		setter.SetExpiration(cfg.CacheExpiration)
+	}
+}
+
+// SetRateLimit returns the subscriber changing the requests allowed by limiter.
+func SetRateLimit(limiter *rest.RateLimiter) func(Config) {
+	return func(cfg Config) {
+		limiter.SetLimit(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst)
+	}
+}
+
+// SetLevel returns the subscriber changing level, it must be the one used for building the logger, for example
+// zap.Config.Level.
+func SetLevel(level zap.AtomicLevel) func(Config) {
+	return func(cfg Config) {
+		level.SetLevel(cfg.LogLevel)
+	}
+}
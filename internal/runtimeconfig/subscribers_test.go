@@ -0,0 +1,201 @@
+package runtimeconfig_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/lrweck/todo/internal/envvar"
+	"github.com/lrweck/todo/internal/envvar/envvartesting"
+	"github.com/lrweck/todo/internal/rest"
+	"github.com/lrweck/todo/internal/runtimeconfig"
+	"github.com/lrweck/todo/internal/service"
+)
+
+func TestConfig_Policies(t *testing.T) {
+	t.Parallel()
+
+	cfg := runtimeconfig.Config{
+		Breaker: runtimeconfig.BreakerConfig{
+			ConsecutiveFailures: 5,
+			OpenTimeout:         30 * time.Second,
+		},
+	}
+
+	base := service.DefaultPolicies()
+
+	breaker := service.BreakerPolicy{
+		ConsecutiveFailures: 5,
+		OpenTimeout:         30 * time.Second,
+	}
+
+	expected := service.Policies{
+		Operations: base.Operations,
+		Breakers: map[string]service.BreakerPolicy{
+			service.DependencyRepo:   breaker,
+			service.DependencySearch: breaker,
+			service.DependencyBroker: breaker,
+		},
+	}
+
+	if diff := cmp.Diff(expected, cfg.Policies(base)); diff != "" {
+		t.Fatalf("expected policies do not match: %s", diff)
+	}
+
+	if diff := cmp.Diff(service.DefaultPolicies(), base); diff != "" {
+		t.Fatalf("expected base policies not to be modified: %s", diff)
+	}
+}
+
+func TestSetExpiration(t *testing.T) {
+	t.Parallel()
+
+	filename := writeFile(t, "", "CACHE_EXPIRATION=5m\n")
+
+	manager, err := runtimeconfig.NewManager(zap.NewNop(), envvar.New(&envvartesting.FakeProvider{}), filename)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	setter := &expirationSetter{}
+
+	manager.Subscribe(runtimeconfig.SetExpiration(setter))
+
+	_ = writeFile(t, filename, "CACHE_EXPIRATION=1m\n")
+
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]time.Duration{5 * time.Minute, time.Minute}, setter.expirations); diff != "" {
+		t.Fatalf("expected expirations do not match: %s", diff)
+	}
+}
+
+func TestSetRateLimit(t *testing.T) {
+	t.Parallel()
+
+	filename := writeFile(t, "", "")
+
+	manager, err := runtimeconfig.NewManager(zap.NewNop(), envvar.New(&envvartesting.FakeProvider{}), filename)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	limiter := rest.NewRateLimiter(0, 0)
+
+	manager.Subscribe(runtimeconfig.SetRateLimit(limiter))
+
+	router := mux.NewRouter()
+	router.Use(limiter.Middleware())
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	status := func() int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		return rec.Code
+	}
+
+	if code := status(); code != http.StatusNoContent {
+		t.Fatalf("expected code %d while disabled, actual %d", http.StatusNoContent, code)
+	}
+
+	_ = writeFile(t, filename, "RATE_LIMIT_REQUESTS_PER_SECOND=0.001\nRATE_LIMIT_BURST=1\n")
+
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if code := status(); code != http.StatusNoContent {
+		t.Fatalf("expected code %d within burst, actual %d", http.StatusNoContent, code)
+	}
+
+	if code := status(); code != http.StatusTooManyRequests {
+		t.Fatalf("expected code %d after burst, actual %d", http.StatusTooManyRequests, code)
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	t.Parallel()
+
+	filename := writeFile(t, "", "LOG_LEVEL=warn\n")
+
+	manager, err := runtimeconfig.NewManager(zap.NewNop(), envvar.New(&envvartesting.FakeProvider{}), filename)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	level := zap.NewAtomicLevel()
+
+	manager.Subscribe(runtimeconfig.SetLevel(level))
+
+	if level.Level() != zapcore.WarnLevel {
+		t.Fatalf("expected warn level, got %s", level.Level())
+	}
+
+	_ = writeFile(t, filename, "LOG_LEVEL=debug\n")
+
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if level.Level() != zapcore.DebugLevel {
+		t.Fatalf("expected debug level, got %s", level.Level())
+	}
+}
+
+func TestSetPolicies(t *testing.T) {
+	t.Parallel()
+
+	filename := writeFile(t, "", "BREAKER_CONSECUTIVE_FAILURES=5\n")
+
+	manager, err := runtimeconfig.NewManager(zap.NewNop(), envvar.New(&envvartesting.FakeProvider{}), filename)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	setter := &policySetter{}
+
+	manager.Subscribe(runtimeconfig.SetPolicies(setter, service.DefaultPolicies()))
+
+	_ = writeFile(t, filename, "BREAKER_CONSECUTIVE_FAILURES=10\n")
+
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	var failures []int64
+
+	for _, policies := range setter.policies {
+		failures = append(failures, policies.Breakers[service.DependencyRepo].ConsecutiveFailures)
+	}
+
+	if diff := cmp.Diff([]int64{5, 10}, failures); diff != "" {
+		t.Fatalf("expected policies do not match: %s", diff)
+	}
+}
+
+type policySetter struct {
+	policies []service.Policies
+}
+
+func (s *policySetter) SetPolicies(policies service.Policies) {
+	s.policies = append(s.policies, policies)
+}
+
+type expirationSetter struct {
+	expirations []time.Duration
+}
+
+func (s *expirationSetter) SetExpiration(d time.Duration) {
+	s.expirations = append(s.expirations, d)
+}
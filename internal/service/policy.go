@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/mercari/go-circuitbreaker"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// Operations supported by Task, used as keys for configuring their policies.
+const (
+	OpBy      = "Task.By"
+	OpCreate  = "Task.Create"
+	OpDelete  = "Task.Delete"
+	OpPublish = "Task.Publish"
+	OpRestore = "Task.Restore"
+	OpTask    = "Task.Task"
+	OpTasks   = "Task.Tasks"
+	OpTrash   = "Task.Trash"
+	OpUpdate  = "Task.Update"
+)
+
+// Dependencies protected by a circuit breaker.
+const (
+	DependencyRepo   = "repo"
+	DependencySearch = "search"
+	DependencyBroker = "broker"
+)
+
+// OperationPolicy defines how calls made by an operation are protected.
+type OperationPolicy struct {
+	// Timeout is the deadline for the whole operation, including retries. Zero means no deadline.
+	Timeout time.Duration
+	// Retries is the number of times a call failing with a transient error is retried.
+	Retries int
+	// RetryBackoff is the base delay between retries, it is doubled on each attempt and jittered.
+	RetryBackoff time.Duration
+}
+
+// BreakerPolicy defines when the circuit breaker protecting a dependency opens.
+type BreakerPolicy struct {
+	// ConsecutiveFailures is the number of failures in a row that open the breaker.
+	ConsecutiveFailures int64
+	// OpenTimeout is how long the breaker stays open before trying again.
+	OpenTimeout time.Duration
+}
+
+// Policies configures the resilience of each one of the operations and dependencies used by Task.
+type Policies struct {
+	Operations map[string]OperationPolicy
+	Breakers   map[string]BreakerPolicy
+}
+
+// DefaultPolicies returns the policies used by default. Only reads are retried: a write failing with a
+// transient error, like a timeout, may still have been applied; retrying it is not safe, for example deleting
+// again would fail with ErrCodeNotFound.
+func DefaultPolicies() Policies {
+	read := OperationPolicy{
+		Timeout:      5 * time.Second,
+		Retries:      2,
+		RetryBackoff: 50 * time.Millisecond,
+	}
+
+	write := OperationPolicy{
+		Timeout: 5 * time.Second,
+	}
+
+	breaker := BreakerPolicy{
+		ConsecutiveFailures: 3,
+		OpenTimeout:         time.Minute,
+	}
+
+	return Policies{
+		Operations: map[string]OperationPolicy{
+			OpBy:      read,
+			OpCreate:  write,
+			OpDelete:  write,
+			OpPublish: {Timeout: 2 * time.Second},
+			OpRestore: write,
+			OpTask:    read,
+			OpTasks:   read,
+			OpTrash:   read,
+			OpUpdate:  write,
+		},
+		Breakers: map[string]BreakerPolicy{
+			DependencyRepo:   breaker,
+			DependencySearch: breaker,
+			DependencyBroker: breaker,
+		},
+	}
+}
+
+func (p Policies) operation(op string) OperationPolicy {
+	return p.Operations[op]
+}
+
+func (p Policies) breaker(dependency string) BreakerPolicy {
+	if res, ok := p.Breakers[dependency]; ok {
+		return res
+	}
+
+	return DefaultPolicies().Breakers[dependency]
+}
+
+func newBreaker(logger *zap.Logger, dependency string, policy BreakerPolicy) *circuitbreaker.CircuitBreaker {
+	return circuitbreaker.New(
+		circuitbreaker.WithOpenTimeout(policy.OpenTimeout),
+		circuitbreaker.WithTripFunc(circuitbreaker.NewTripFuncConsecutiveFailures(policy.ConsecutiveFailures)),
+		circuitbreaker.WithOnStateChangeHookFn(func(oldState, newState circuitbreaker.State) {
+			logger.Info("state changed",
+				zap.String("dependency", dependency),
+				zap.String("old", string(oldState)),
+				zap.String("new", string(newState)),
+			)
+		}),
+	)
+}
+
+// do calls fn following the policy defined for the operation, the dependency's breaker must be ready before
+// each attempt.
+func (t *Task) do(ctx context.Context, op, dependency string, fn func(ctx context.Context) error) error {
+	t.mu.RLock()
+	policy := t.policies.operation(op)
+	cb := t.breakers[dependency]
+	t.mu.RUnlock()
+
+	if policy.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		if !cb.Ready() {
+			return internal.NewErrorf(internal.ErrCodeUnavailable, "%s not ready", dependency)
+		}
+
+		err := cb.Done(ctx, markExpected(fn(ctx)))
+		if err == nil || attempt >= policy.Retries || !internal.Retryable(err) {
+			return err
+		}
+
+		t.logger.Info("retrying",
+			zap.String("operation", op),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff(policy.RetryBackoff, attempt)):
+		}
+	}
+}
+
+// SetPolicies replaces the policies in use, calls already in progress keep using the previous ones. Breakers
+// whose policy changed keep their state, an open breaker stays open using the new timeout.
+func (t *Task) SetPolicies(policies Policies) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for dependency, cb := range t.breakers {
+		policy := policies.breaker(dependency)
+		if policy == t.policies.breaker(dependency) {
+			continue
+		}
+
+		// XXX: go-circuitbreaker doesn't support changing the thresholds, the replacement starts in the same
+		// state instead; the failures counted so far are lost.
+		replacement := newBreaker(t.logger, dependency, policy)
+		if state := cb.State(); state != circuitbreaker.StateClosed {
+			replacement.SetState(state)
+		}
+
+		t.breakers[dependency] = replacement
+
+		t.logger.Info("breaker policy changed",
+			zap.String("dependency", dependency),
+			zap.String("state", string(cb.State())),
+			zap.Int64("consecutive_failures", policy.ConsecutiveFailures),
+			zap.Duration("open_timeout", policy.OpenTimeout),
+		)
+	}
+
+	t.policies = policies
+}
+
+// BreakerStates returns the current state of the circuit breaker for each dependency.
+func (t *Task) BreakerStates() map[string]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	res := make(map[string]string, len(t.breakers))
+
+	for dependency, cb := range t.breakers {
+		res[dependency] = string(cb.State())
+	}
+
+	return res
+}
+
+func (t *Task) registerBreakerMetrics() {
+	_, err := global.Meter("todo.service").NewInt64GaugeObserver("service.breaker.state",
+		func(ctx context.Context, result metric.Int64ObserverResult) {
+			t.mu.RLock()
+			defer t.mu.RUnlock()
+
+			for dependency, cb := range t.breakers {
+				result.Observe(breakerStateValue(cb.State()), attribute.String("dependency", dependency))
+			}
+		},
+		metric.WithDescription("Circuit breaker state per dependency: 0 closed, 1 half-open, 2 open"))
+	if err != nil {
+		t.logger.Warn("registering breaker metrics", zap.Error(err))
+	}
+}
+
+func breakerStateValue(state circuitbreaker.State) int64 {
+	switch state {
+	case circuitbreaker.StateClosed:
+		return 0
+	case circuitbreaker.StateHalfOpen:
+		return 1
+	case circuitbreaker.StateOpen:
+		return 2
+	}
+
+	return -1
+}
+
+// backoff returns the delay before retrying, using "full jitter": a random value between zero and the
+// exponential backoff.
+func backoff(base time.Duration, attempt int) time.Duration {
+	max := base << attempt
+	if max <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(max))) //nolint: gosec
+}
+
+// markExpected prevents errors caused by the request, like missing records or invalid arguments, from
+// opening the breaker.
+func markExpected(err error) error {
+	switch internal.Code(err) {
+	case internal.ErrCodeNotFound,
+		internal.ErrCodeInvalidArgument,
+		internal.ErrCodeAlreadyExists,
+		internal.ErrCodeCanceled,
+		internal.ErrCodeUnauthenticated,
+		internal.ErrCodePermissionDenied,
+		internal.ErrCodeFailedPrecondition:
+		return circuitbreaker.MarkAsSuccess(err)
+	}
+
+	return err
+}
+
+// errorCode returns the code used for wrapping errors returned by the dependencies, an open breaker means the
+// dependency is unavailable.
+func errorCode(err error) internal.ErrorCode {
+	if errors.Is(err, circuitbreaker.ErrOpen) {
+		return internal.ErrCodeUnavailable
+	}
+
+	return internal.Code(err)
+}
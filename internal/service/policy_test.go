@@ -0,0 +1,282 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/service"
+	"github.com/lrweck/todo/internal/service/servicetesting"
+)
+
+func TestTask_Policies(t *testing.T) {
+	t.Parallel()
+
+	unavailable := internal.NewErrorf(internal.ErrCodeUnavailable, "unavailable")
+
+	policies := service.Policies{
+		Operations: map[string]service.OperationPolicy{
+			service.OpTask: {
+				Timeout:      100 * time.Millisecond,
+				Retries:      2,
+				RetryBackoff: time.Millisecond,
+			},
+		},
+		Breakers: map[string]service.BreakerPolicy{
+			service.DependencyRepo: {
+				ConsecutiveFailures: 10,
+				OpenTimeout:         time.Minute,
+			},
+		},
+	}
+
+	type output struct {
+		calls int
+		code  internal.ErrorCode
+	}
+
+	tests := []struct {
+		name     string
+		setup    func(*servicetesting.FakeTaskRepo)
+		policies service.Policies
+		output   output
+	}{
+		{
+			"OK: retried transient error",
+			func(r *servicetesting.FakeTaskRepo) {
+				r.FindReturnsOnCall(0, internal.Task{}, unavailable)
+				r.FindReturnsOnCall(1, internal.Task{ID: "1"}, nil)
+			},
+			policies,
+			output{
+				calls: 2,
+			},
+		},
+		{
+			"ERR: retries exhausted",
+			func(r *servicetesting.FakeTaskRepo) {
+				r.FindReturns(internal.Task{}, unavailable)
+			},
+			policies,
+			output{
+				calls: 3,
+				code:  internal.ErrCodeUnavailable,
+			},
+		},
+		{
+			"ERR: not retried",
+			func(r *servicetesting.FakeTaskRepo) {
+				r.FindReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeNotFound, "not found"))
+			},
+			policies,
+			output{
+				calls: 1,
+				code:  internal.ErrCodeNotFound,
+			},
+		},
+		{
+			"ERR: timeout",
+			func(r *servicetesting.FakeTaskRepo) {
+				r.FindStub = func(ctx context.Context, _ string) (internal.Task, error) {
+					<-ctx.Done()
+
+					return internal.Task{}, internal.WrapErrorf(ctx.Err(), internal.ErrCodeDeadlineExceeded, "find")
+				}
+			},
+			policies,
+			output{
+				calls: 1, // The deadline covers the retries as well.
+				code:  internal.ErrCodeDeadlineExceeded,
+			},
+		},
+		{
+			"ERR: breaker open",
+			func(r *servicetesting.FakeTaskRepo) {
+				r.FindReturns(internal.Task{}, unavailable)
+			},
+			service.Policies{
+				Operations: policies.Operations,
+				Breakers: map[string]service.BreakerPolicy{
+					service.DependencyRepo: {
+						ConsecutiveFailures: 1,
+						OpenTimeout:         time.Minute,
+					},
+				},
+			},
+			output{
+				calls: 1,
+				code:  internal.ErrCodeUnavailable,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &servicetesting.FakeTaskRepo{}
+			tt.setup(repo)
+
+			svc := service.NewTask(zap.NewNop(), repo, &servicetesting.FakeTaskSearchRepo{},
+				&servicetesting.FakeTaskMessageBrokerRepo{}, tt.policies)
+
+			_, err := svc.Task(context.Background(), "1")
+			if internal.Code(err) != tt.output.code {
+				t.Fatalf("expected %s error, got %v", tt.output.code, err)
+			}
+
+			if calls := repo.FindCallCount(); calls != tt.output.calls {
+				t.Fatalf("expected %d calls, got %d", tt.output.calls, calls)
+			}
+		})
+	}
+}
+
+func TestTask_DefaultPolicies(t *testing.T) {
+	t.Parallel()
+
+	unavailable := internal.NewErrorf(internal.ErrCodeUnavailable, "unavailable")
+
+	tests := []struct {
+		name  string
+		setup func(*servicetesting.FakeTaskRepo)
+		call  func(*service.Task) error
+		calls func(*servicetesting.FakeTaskRepo) int
+	}{
+		{
+			"Create",
+			func(r *servicetesting.FakeTaskRepo) {
+				r.CreateReturns(internal.Task{}, unavailable)
+			},
+			func(s *service.Task) error {
+				_, err := s.Create(context.Background(), internal.CreateParams{Description: "new", Priority: internal.PriorityLow})
+
+				return err
+			},
+			(*servicetesting.FakeTaskRepo).CreateCallCount,
+		},
+		{
+			"Delete",
+			func(r *servicetesting.FakeTaskRepo) {
+				r.DeleteReturns(unavailable)
+			},
+			func(s *service.Task) error {
+				return s.Delete(context.Background(), "1")
+			},
+			(*servicetesting.FakeTaskRepo).DeleteCallCount,
+		},
+		{
+			"Restore",
+			func(r *servicetesting.FakeTaskRepo) {
+				r.RestoreReturns(unavailable)
+			},
+			func(s *service.Task) error {
+				return s.Restore(context.Background(), "1")
+			},
+			(*servicetesting.FakeTaskRepo).RestoreCallCount,
+		},
+		{
+			"Update",
+			func(r *servicetesting.FakeTaskRepo) {
+				r.UpdateReturns(unavailable)
+			},
+			func(s *service.Task) error {
+				return s.Update(context.Background(), "1", "", internal.PriorityLow, internal.Dates{}, false)
+			},
+			(*servicetesting.FakeTaskRepo).UpdateCallCount,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &servicetesting.FakeTaskRepo{}
+			tt.setup(repo)
+
+			svc := service.NewTask(zap.NewNop(), repo, &servicetesting.FakeTaskSearchRepo{},
+				&servicetesting.FakeTaskMessageBrokerRepo{}, service.DefaultPolicies())
+
+			// Writes are not retried, the first attempt may have been applied.
+
+			if err := tt.call(svc); internal.Code(err) != internal.ErrCodeUnavailable {
+				t.Fatalf("expected unavailable error, got %v", err)
+			}
+
+			if calls := tt.calls(repo); calls != 1 {
+				t.Fatalf("expected 1 call, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestTask_SetPolicies(t *testing.T) {
+	t.Parallel()
+
+	breaker := func(failures int64) service.Policies {
+		return service.Policies{
+			Breakers: map[string]service.BreakerPolicy{
+				service.DependencyRepo: {
+					ConsecutiveFailures: failures,
+					OpenTimeout:         time.Minute,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		policies service.Policies
+	}{
+		{
+			"OK: breaker unchanged",
+			service.Policies{
+				Operations: map[string]service.OperationPolicy{
+					service.OpTask: {Timeout: time.Second},
+				},
+				Breakers: breaker(1).Breakers,
+			},
+		},
+		{
+			"OK: breaker changed",
+			breaker(5),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &servicetesting.FakeTaskRepo{}
+			repo.FindReturns(internal.Task{}, internal.NewErrorf(internal.ErrCodeUnavailable, "unavailable"))
+
+			svc := service.NewTask(zap.NewNop(), repo, &servicetesting.FakeTaskSearchRepo{},
+				&servicetesting.FakeTaskMessageBrokerRepo{}, breaker(1))
+
+			_, _ = svc.Task(context.Background(), "1")
+
+			svc.SetPolicies(tt.policies)
+
+			if state := svc.BreakerStates()[service.DependencyRepo]; state != "open" {
+				t.Fatalf("expected breaker to stay open, got %s", state)
+			}
+
+			if _, err := svc.Task(context.Background(), "1"); internal.Code(err) != internal.ErrCodeUnavailable {
+				t.Fatalf("expected unavailable error, got %v", err)
+			}
+
+			if calls := repo.FindCallCount(); calls != 1 {
+				t.Fatalf("expected 1 call, got %d", calls)
+			}
+		})
+	}
+}
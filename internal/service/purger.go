@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/lrweck/todo/internal"
+)
+
+type TaskPurgeRepo interface {
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// TrashPurger permanently deletes Tasks that stayed in the trash longer than the configured retention.
+type TrashPurger struct {
+	repo      TaskPurgeRepo
+	retention time.Duration
+	interval  time.Duration
+	logger    *zap.Logger
+}
+
+// NewTrashPurger instantiates the TrashPurger, "interval" indicates how often the trash is checked.
+func NewTrashPurger(logger *zap.Logger, repo TaskPurgeRepo, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		repo:      repo,
+		retention: retention,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// Run purges the trash periodically, it blocks until the context is canceled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := p.Purge(ctx)
+			if err != nil {
+				p.logger.Error("purging trash", zap.Error(err))
+
+				continue
+			}
+
+			p.logger.Info("trash purged", zap.Int64("count", count))
+		}
+	}
+}
+
+// Purge permanently deletes the Tasks trashed before the retention window.
+func (p *TrashPurger) Purge(ctx context.Context) (int64, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("todo.service").Start(ctx, "TrashPurger.Purge")
+	defer span.End()
+
+	count, err := p.repo.Purge(ctx, time.Now().UTC().Add(-p.retention))
+	if err != nil {
+		return 0, internal.WrapErrorf(err, errorCode(err), "repo.Purge")
+	}
+
+	return count, nil
+}
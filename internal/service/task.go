@@ -2,7 +2,7 @@ package service
 
 import (
 	"context"
-	"time"
+	"sync"
 
 	"github.com/lrweck/todo/internal"
 	"github.com/mercari/go-circuitbreaker"
@@ -10,20 +10,35 @@ import (
 	"go.uber.org/zap"
 )
 
+//go:generate counterfeiter -generate
+
+//counterfeiter:generate -o servicetesting/task_repo.gen.go . TaskRepo
+
+// TaskRepo defines the datastore handling persisting Task records.
 type TaskRepo interface {
 	Create(ctx context.Context, dates internal.CreateParams) (internal.Task, error)
 	Delete(ctx context.Context, id string) error
 	Find(ctx context.Context, id string) (internal.Task, error)
+	FindMany(ctx context.Context, ids []string) ([]internal.Task, error)
+	Restore(ctx context.Context, id string) error
+	Trash(ctx context.Context, args internal.TrashParams) (internal.TrashResults, error)
 	Update(ctx context.Context, id, description string, priority internal.Priority, dates internal.Dates, isDone bool) error
 }
 
+//counterfeiter:generate -o servicetesting/task_search_repo.gen.go . TaskSearchRepo
+
+// TaskSearchRepo defines the datastore handling searching Task records.
 type TaskSearchRepo interface {
 	Search(ctx context.Context, args internal.SearchParams) (internal.SearchResults, error)
 }
 
+//counterfeiter:generate -o servicetesting/task_message_broker_repo.gen.go . TaskMessageBrokerRepo
+
+// TaskMessageBrokerRepo defines the messaging-broker used for publishing Task events.
 type TaskMessageBrokerRepo interface {
 	Created(ctx context.Context, task internal.Task) error
 	Deleted(ctx context.Context, id string) error
+	Restored(ctx context.Context, task internal.Task) error
 	Updated(ctx context.Context, task internal.Task) error
 }
 
@@ -31,52 +46,54 @@ type Task struct {
 	repo          TaskRepo
 	search        TaskSearchRepo
 	messageBroker TaskMessageBrokerRepo
-	cb            *circuitbreaker.CircuitBreaker
 	logger        *zap.Logger
+
+	mu       sync.RWMutex
+	policies Policies
+	breakers map[string]*circuitbreaker.CircuitBreaker
 }
 
+// NewTask instantiates the Task service, each dependency is protected by its own circuit breaker configured
+// using the received policies.
 func NewTask(logger *zap.Logger,
 	repo TaskRepo,
 	search TaskSearchRepo,
 	messageBroker TaskMessageBrokerRepo,
+	policies Policies,
 ) *Task {
-	return &Task{
+	breakers := make(map[string]*circuitbreaker.CircuitBreaker)
+
+	for _, dependency := range []string{DependencyRepo, DependencySearch, DependencyBroker} {
+		breakers[dependency] = newBreaker(logger, dependency, policies.breaker(dependency))
+	}
+
+	t := Task{
 		repo:          repo,
 		search:        search,
 		messageBroker: messageBroker,
+		policies:      policies,
+		breakers:      breakers,
 		logger:        logger,
-		cb: circuitbreaker.New(
-			circuitbreaker.WithOpenTimeout(time.Minute),
-			circuitbreaker.WithTripFunc(circuitbreaker.NewTripFuncConsecutiveFailures(3)),
-			circuitbreaker.WithOnStateChangeHookFn(func(oldState, newState circuitbreaker.State) {
-				logger.Info("state changed",
-					zap.String("old", string(oldState)),
-					zap.String("new", string(newState)),
-				)
-			}),
-		),
 	}
-}
 
-func (t *Task) By(ctx context.Context, args internal.SearchParams) (_ internal.SearchResults, err error) {
+	t.registerBreakerMetrics()
+
+	return &t
+}
 
+func (t *Task) By(ctx context.Context, args internal.SearchParams) (internal.SearchResults, error) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("todo.service").Start(ctx, "Task.By")
 	defer span.End()
 
-	if !t.cb.Ready() {
-		return internal.SearchResults{}, internal.NewErrorf(internal.ErrCodeUnknown, "service not ready")
-	}
+	var res internal.SearchResults
 
-	defer func() {
-		err = t.cb.Done(ctx, err)
-		if err != nil {
-			t.logger.Info("circuit breaker tripped", zap.Error(err))
-		}
-	}()
+	err := t.do(ctx, OpBy, DependencySearch, func(ctx context.Context) (err error) {
+		res, err = t.search.Search(ctx, args)
 
-	res, err := t.search.Search(ctx, args)
+		return err
+	})
 	if err != nil {
-		return internal.SearchResults{}, internal.WrapErrorf(err, internal.ErrCodeUnknown, "search")
+		return internal.SearchResults{}, internal.WrapErrorf(err, errorCode(err), "search")
 	}
 
 	return res, nil
@@ -90,47 +107,123 @@ func (t *Task) Create(ctx context.Context, params internal.CreateParams) (intern
 		return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "params.Validate")
 	}
 
-	task, err := t.repo.Create(ctx, params)
+	var task internal.Task
+
+	err := t.do(ctx, OpCreate, DependencyRepo, func(ctx context.Context) (err error) {
+		task, err = t.repo.Create(ctx, params)
+
+		return err
+	})
 	if err != nil {
-		return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeUnknown, "repo.Create")
+		return internal.Task{}, internal.WrapErrorf(err, errorCode(err), "repo.Create")
 	}
 
 	// XXX: Transactions will be revisited in future episodes.
-	_ = t.messageBroker.Created(ctx, task) // XXX: Ignoring errors on purpose
+	t.publish(ctx, func(ctx context.Context) error {
+		return t.messageBroker.Created(ctx, task)
+	})
 
 	return task, nil
 }
 
-// Delete removes an existing Task from the datastore.
+// Delete moves an existing Task to the trash.
 func (t *Task) Delete(ctx context.Context, id string) error {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("todo.service").Start(ctx, "Task.Delete")
 	defer span.End()
 
 	// XXX: We will revisit the number of received arguments in future episodes.
-	if err := t.repo.Delete(ctx, id); err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "Delete")
+	err := t.do(ctx, OpDelete, DependencyRepo, func(ctx context.Context) error {
+		return t.repo.Delete(ctx, id)
+	})
+	if err != nil {
+		return internal.WrapErrorf(err, errorCode(err), "Delete")
 	}
 
 	// XXX: Transactions will be revisited in future episodes.
-	_ = t.messageBroker.Deleted(ctx, id) // XXX: Ignoring errors on purpose
+	t.publish(ctx, func(ctx context.Context) error {
+		return t.messageBroker.Deleted(ctx, id)
+	})
+
+	return nil
+}
+
+// Restore moves a Task out of the trash.
+func (t *Task) Restore(ctx context.Context, id string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("todo.service").Start(ctx, "Task.Restore")
+	defer span.End()
+
+	err := t.do(ctx, OpRestore, DependencyRepo, func(ctx context.Context) error {
+		return t.repo.Restore(ctx, id)
+	})
+	if err != nil {
+		return internal.WrapErrorf(err, errorCode(err), "repo.Restore")
+	}
+
+	{
+		task, err := t.find(ctx, OpRestore, id)
+		if err == nil {
+			// XXX: Transactions will be revisited in future episodes.
+			t.publish(ctx, func(ctx context.Context) error {
+				return t.messageBroker.Restored(ctx, task)
+			})
+		}
+	}
 
 	return nil
 }
 
+// Trash returns the Tasks currently in the trash.
+func (t *Task) Trash(ctx context.Context, args internal.TrashParams) (internal.TrashResults, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("todo.service").Start(ctx, "Task.Trash")
+	defer span.End()
+
+	var res internal.TrashResults
+
+	err := t.do(ctx, OpTrash, DependencyRepo, func(ctx context.Context) (err error) {
+		res, err = t.repo.Trash(ctx, args)
+
+		return err
+	})
+	if err != nil {
+		return internal.TrashResults{}, internal.WrapErrorf(err, errorCode(err), "repo.Trash")
+	}
+
+	return res, nil
+}
+
 // Task gets an existing Task from the datastore.
 func (t *Task) Task(ctx context.Context, id string) (internal.Task, error) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("todo.service").Start(ctx, "Task.Task")
 	defer span.End()
 
 	// XXX: We will revisit the number of received arguments in future episodes.
-	task, err := t.repo.Find(ctx, id)
+	task, err := t.find(ctx, OpTask, id)
 	if err != nil {
-		return internal.Task{}, internal.WrapErrorf(err, internal.ErrCodeUnknown, "Find")
+		return internal.Task{}, internal.WrapErrorf(err, errorCode(err), "Find")
 	}
 
 	return task, nil
 }
 
+// Tasks gets the existing Tasks from the datastore using one query, the ones not found are not included.
+func (t *Task) Tasks(ctx context.Context, ids []string) ([]internal.Task, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("todo.service").Start(ctx, "Task.Tasks")
+	defer span.End()
+
+	var tasks []internal.Task
+
+	err := t.do(ctx, OpTasks, DependencyRepo, func(ctx context.Context) (err error) {
+		tasks, err = t.repo.FindMany(ctx, ids)
+
+		return err
+	})
+	if err != nil {
+		return nil, internal.WrapErrorf(err, errorCode(err), "FindMany")
+	}
+
+	return tasks, nil
+}
+
 // Update updates an existing Task in the datastore.
 func (t *Task) Update(ctx context.Context,
 	id string,
@@ -143,18 +236,42 @@ func (t *Task) Update(ctx context.Context,
 	defer span.End()
 
 	// XXX: We will revisit the number of received arguments in future episodes.
-	if err := t.repo.Update(ctx, id, description, priority, dates, isDone); err != nil {
-		return internal.WrapErrorf(err, internal.ErrCodeUnknown, "repo.Update")
+	err := t.do(ctx, OpUpdate, DependencyRepo, func(ctx context.Context) error {
+		return t.repo.Update(ctx, id, description, priority, dates, isDone)
+	})
+	if err != nil {
+		return internal.WrapErrorf(err, errorCode(err), "repo.Update")
 	}
 
 	{
 		// XXX: This will be improved when Kafka events are introduced in future episodes
-		task, err := t.repo.Find(ctx, id)
+		task, err := t.find(ctx, OpUpdate, id)
 		if err == nil {
 			// XXX: Transactions will be revisited in future episodes.
-			_ = t.messageBroker.Updated(ctx, task) // XXX: Ignoring errors on purpose
+			t.publish(ctx, func(ctx context.Context) error {
+				return t.messageBroker.Updated(ctx, task)
+			})
 		}
 	}
 
 	return nil
 }
+
+func (t *Task) find(ctx context.Context, op, id string) (internal.Task, error) {
+	var task internal.Task
+
+	err := t.do(ctx, op, DependencyRepo, func(ctx context.Context) (err error) {
+		task, err = t.repo.Find(ctx, id)
+
+		return err
+	})
+
+	return task, err
+}
+
+// publish sends the event using the broker policy, failures are logged and ignored on purpose.
+func (t *Task) publish(ctx context.Context, fn func(ctx context.Context) error) {
+	if err := t.do(ctx, OpPublish, DependencyBroker, fn); err != nil {
+		t.logger.Info("publishing failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,43 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric/global"
+	export "go.opentelemetry.io/otel/sdk/export/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	selector "go.opentelemetry.io/otel/sdk/metric/selector/simple"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// durationBoundaries defines the histogram buckets, in milliseconds, used for measuring latencies.
+var durationBoundaries = []float64{1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// NewPrometheusExporter instantiates a Prometheus exporter and installs it as the global MeterProvider, the
+// returned value is the http.Handler that should be used for serving the metrics.
+func NewPrometheusExporter() (*prometheus.Exporter, error) {
+	config := prometheus.Config{
+		DefaultHistogramBoundaries: durationBoundaries,
+	}
+
+	c := controller.New(
+		processor.NewFactory(
+			selector.NewWithHistogramDistribution(
+				histogram.WithExplicitBoundaries(config.DefaultHistogramBoundaries),
+			),
+			export.CumulativeExportKindSelector(),
+			processor.WithMemory(true),
+		),
+	)
+
+	exporter, err := prometheus.New(config, c)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "prometheus.New")
+	}
+
+	global.SetMeterProvider(exporter.MeterProvider())
+
+	return exporter, nil
+}
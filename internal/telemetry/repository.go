@@ -0,0 +1,47 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+
+	"github.com/lrweck/todo/internal"
+)
+
+// RepositoryMetrics measures the calls made to a Task repository, like postgresql.Task and sqlite.Task.
+type RepositoryMetrics struct {
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+// NewRepositoryMetrics instantiates the RepositoryMetrics, system is the database used by the repository and
+// it is used for naming the meter and instruments, for example "postgresql.task.duration".
+func NewRepositoryMetrics(system string) RepositoryMetrics {
+	meter := metric.Must(global.Meter(system))
+
+	return RepositoryMetrics{
+		duration: meter.NewFloat64Histogram(system+".task.duration",
+			metric.WithDescription("Duration of the Task repository calls"),
+			metric.WithUnit("ms")),
+		errors: meter.NewInt64Counter(system+".task.errors",
+			metric.WithDescription("Failed Task repository calls, not found records and invalid arguments are excluded")),
+	}
+}
+
+// Record measures the call started at "start", it is meant to be deferred using the named error result.
+func (m RepositoryMetrics) Record(ctx context.Context, op string, start time.Time, err *error) {
+	attr := attribute.String("operation", op)
+
+	m.duration.Record(ctx, float64(time.Since(start))/float64(time.Millisecond), attr)
+
+	if *err == nil {
+		return
+	}
+
+	if code := internal.Code(*err); code != internal.ErrCodeNotFound && code != internal.ErrCodeInvalidArgument {
+		m.errors.Add(ctx, 1, attr)
+	}
+}
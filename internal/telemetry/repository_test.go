@@ -0,0 +1,63 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/telemetry"
+)
+
+//nolint:paralleltest // The Prometheus exporter is installed globally.
+func TestRepositoryMetrics(t *testing.T) {
+	exporter, err := telemetry.NewPrometheusExporter()
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	metrics := telemetry.NewRepositoryMetrics("test")
+
+	for op, err := range map[string]error{
+		"Task.OK":              nil,
+		"Task.NotFound":        internal.NewErrorf(internal.ErrCodeNotFound, "not found"),
+		"Task.InvalidArgument": internal.NewErrorf(internal.ErrCodeInvalidArgument, "invalid"),
+		"Task.Unknown":         errors.New("unknown"),
+		"Task.Unavailable":     internal.NewErrorf(internal.ErrCodeUnavailable, "unavailable"),
+	} {
+		err := err
+
+		metrics.Record(context.Background(), op, time.Now(), &err)
+	}
+
+	rec := httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	b, err := io.ReadAll(rec.Result().Body)
+	if err != nil {
+		t.Fatalf("couldn't read body %s", err)
+	}
+
+	body := string(b)
+
+	for _, expected := range []string{
+		`test_task_duration_count{operation="Task.OK",`,
+		`test_task_errors{operation="Task.Unknown",`,
+		`test_task_errors{operation="Task.Unavailable",`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf("expected metric %s, got %s", expected, body)
+		}
+	}
+
+	for _, op := range []string{"Task.OK", "Task.NotFound", "Task.InvalidArgument"} {
+		if unexpected := `test_task_errors{operation="` + op + `",`; strings.Contains(body, unexpected) {
+			t.Fatalf("expected no metric %s, got %s", unexpected, body)
+		}
+	}
+}
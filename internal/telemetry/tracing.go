@@ -0,0 +1,242 @@
+package telemetry
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"github.com/lrweck/todo/internal"
+	"github.com/lrweck/todo/internal/envvar"
+)
+
+// TracingConfig defines the configuration used by NewTracerProvider.
+type TracingConfig struct {
+	Exporter       string  `env:"OTEL_TRACES_EXPORTER" default:"none"`
+	Protocol       string  `env:"OTEL_EXPORTER_OTLP_PROTOCOL" default:"http/protobuf"`
+	Endpoint       string  `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	TracesEndpoint string  `env:"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"`
+	Insecure       bool    `env:"OTEL_EXPORTER_OTLP_INSECURE"`
+	Sampler        string  `env:"OTEL_TRACES_SAMPLER" default:"parentbased_always_on"`
+	SamplerArg     float64 `env:"OTEL_TRACES_SAMPLER_ARG" default:"1"`
+}
+
+// NewTracerProvider instantiates the TracerProvider and installs it globally, together with the W3C Trace
+// Context and Baggage propagators. It's configured using the following environment variables, as defined by
+// the OpenTelemetry specification:
+//
+//	OTEL_TRACES_EXPORTER: "otlp", "stdout" or "none" (default)
+//	OTEL_EXPORTER_OTLP_PROTOCOL: "grpc" or "http/protobuf" (default)
+//	OTEL_EXPORTER_OTLP_ENDPOINT: base URL of the OTLP collector, "/v1/traces" is appended when using HTTP
+//	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT: URL of the OTLP collector used as is, it takes precedence
+//	OTEL_EXPORTER_OTLP_INSECURE: "true" to disable TLS when using gRPC and an endpoint without scheme
+//	OTEL_TRACES_SAMPLER: "always_on", "always_off", "traceidratio" or their "parentbased_" versions (default)
+//	OTEL_TRACES_SAMPLER_ARG: sampling ratio used by "traceidratio", defaults to 1
+//	OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES: override the resource attributes
+//
+// The provider must be shut down before exiting to flush the pending spans.
+func NewTracerProvider(ctx context.Context, conf *envvar.Configuration, serviceName string) (*sdktrace.TracerProvider, error) {
+	var cfg TracingConfig
+
+	if err := conf.Decode(&cfg); err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "conf.Decode")
+	}
+
+	exporter, err := newSpanExporter(ctx, cfg)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "newSpanExporter")
+	}
+
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "newSampler")
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(semconv.ServiceNameKey.String(serviceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "resource.New")
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(res),
+	}
+
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider, nil
+}
+
+// NewHTTPMiddleware returns the middleware starting a span for each request, continuing the trace received in
+// the request headers if any.
+func NewHTTPMiddleware(serviceName string) mux.MiddlewareFunc {
+	return otelmux.Middleware(serviceName,
+		otelmux.WithTracerProvider(otel.GetTracerProvider()),
+		otelmux.WithPropagators(otel.GetTextMapPropagator()),
+	)
+}
+
+func newSpanExporter(ctx context.Context, cfg TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "none":
+		return nil, nil //nolint: nilnil
+	case "stdout":
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "stdouttrace.New")
+		}
+
+		return exporter, nil
+	case "otlp":
+		return newOTLPExporter(ctx, cfg)
+	}
+
+	return nil, internal.NewErrorf(internal.ErrCodeInvalidArgument, "unknown traces exporter: %s", cfg.Exporter)
+}
+
+// otlpEndpoint is the OTLP collector, the zero value means the exporter's default.
+type otlpEndpoint struct {
+	host     string
+	path     string
+	insecure bool
+}
+
+func newOTLPExporter(ctx context.Context, cfg TracingConfig) (sdktrace.SpanExporter, error) {
+	endpoint, err := newOTLPEndpoint(cfg)
+	if err != nil {
+		return nil, internal.WrapErrorf(err, internal.ErrCodeInvalidArgument, "newOTLPEndpoint")
+	}
+
+	switch cfg.Protocol {
+	case "grpc":
+		var opts []otlptracegrpc.Option
+
+		if endpoint.host != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint.host))
+		}
+
+		if endpoint.insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "otlptracegrpc.New")
+		}
+
+		return exporter, nil
+	case "http/protobuf":
+		var opts []otlptracehttp.Option
+
+		if endpoint.host != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint.host), otlptracehttp.WithURLPath(endpoint.path))
+		}
+
+		if endpoint.insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, internal.WrapErrorf(err, internal.ErrCodeUnknown, "otlptracehttp.New")
+		}
+
+		return exporter, nil
+	}
+
+	return nil, internal.NewErrorf(internal.ErrCodeInvalidArgument, "unsupported OTLP protocol: %s", cfg.Protocol)
+}
+
+// newOTLPEndpoint parses the configured endpoint, it must be a URL using "http" or "https", the latter enables
+// TLS. For backwards compatibility gRPC endpoints can also be "host:port".
+func newOTLPEndpoint(cfg TracingConfig) (otlpEndpoint, error) {
+	raw, path := cfg.TracesEndpoint, ""
+	if raw == "" {
+		if cfg.Endpoint == "" {
+			return otlpEndpoint{}, nil
+		}
+
+		raw, path = cfg.Endpoint, "/v1/traces"
+	}
+
+	if !strings.HasPrefix(raw, "http://") && !strings.HasPrefix(raw, "https://") {
+		if cfg.Protocol != "grpc" {
+			return otlpEndpoint{}, internal.NewErrorf(internal.ErrCodeInvalidArgument, "endpoint must be a URL: %s", raw)
+		}
+
+		return otlpEndpoint{host: raw, insecure: cfg.Insecure}, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return otlpEndpoint{}, internal.NewErrorf(internal.ErrCodeInvalidArgument, "invalid endpoint: %s", raw)
+	}
+
+	// The signal specific endpoint is used as is, the generic one is the base URL for all signals.
+
+	if path == "" {
+		path = u.Path
+	} else {
+		path = strings.TrimSuffix(u.Path, "/") + path
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	return otlpEndpoint{
+		host:     u.Host,
+		path:     path,
+		insecure: u.Scheme == "http",
+	}, nil
+}
+
+func newSampler(cfg TracingConfig) (sdktrace.Sampler, error) {
+	ratio := cfg.SamplerArg
+	if ratio < 0 || ratio > 1 {
+		return nil, internal.NewErrorf(internal.ErrCodeInvalidArgument, "invalid sampler ratio: %v", ratio)
+	}
+
+	switch cfg.Sampler {
+	case "always_on":
+		return sdktrace.AlwaysSample(), nil
+	case "always_off":
+		return sdktrace.NeverSample(), nil
+	case "traceidratio":
+		return sdktrace.TraceIDRatioBased(ratio), nil
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case "parentbased_traceidratio":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), nil
+	}
+
+	return nil, internal.NewErrorf(internal.ErrCodeInvalidArgument, "unknown sampler: %s", cfg.Sampler)
+}
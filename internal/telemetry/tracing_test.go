@@ -0,0 +1,136 @@
+package telemetry_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/lrweck/todo/internal/envvar"
+	"github.com/lrweck/todo/internal/envvar/envvartesting"
+	"github.com/lrweck/todo/internal/telemetry"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	// XXX: Not parallel because it modifies environment variables and the global TracerProvider.
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		withErr bool
+	}{
+		{
+			"OK: defaults",
+			map[string]string{},
+			false,
+		},
+		{
+			"OK: stdout exporter and ratio sampler",
+			map[string]string{
+				"OTEL_TRACES_EXPORTER":    "stdout",
+				"OTEL_TRACES_SAMPLER":     "parentbased_traceidratio",
+				"OTEL_TRACES_SAMPLER_ARG": "0.25",
+			},
+			false,
+		},
+		{
+			"ERR: unknown exporter",
+			map[string]string{
+				"OTEL_TRACES_EXPORTER": "zipkin",
+			},
+			true,
+		},
+		{
+			"ERR: unknown sampler",
+			map[string]string{
+				"OTEL_TRACES_SAMPLER": "sometimes",
+			},
+			true,
+		},
+		{
+			"ERR: invalid sampler ratio",
+			map[string]string{
+				"OTEL_TRACES_SAMPLER":     "traceidratio",
+				"OTEL_TRACES_SAMPLER_ARG": "2",
+			},
+			true,
+		},
+		{
+			"OK: otlp exporter using HTTP",
+			map[string]string{
+				"OTEL_TRACES_EXPORTER":        "otlp",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "https://collector:4318/otlp",
+			},
+			false,
+		},
+		{
+			"OK: otlp exporter using gRPC",
+			map[string]string{
+				"OTEL_TRACES_EXPORTER":        "otlp",
+				"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4317",
+			},
+			false,
+		},
+		{
+			"OK: otlp exporter using gRPC and host:port",
+			map[string]string{
+				"OTEL_TRACES_EXPORTER":        "otlp",
+				"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "collector:4317",
+				"OTEL_EXPORTER_OTLP_INSECURE": "true",
+			},
+			false,
+		},
+		{
+			"ERR: unsupported protocol",
+			map[string]string{
+				"OTEL_TRACES_EXPORTER":        "otlp",
+				"OTEL_EXPORTER_OTLP_PROTOCOL": "http/json",
+			},
+			true,
+		},
+		{
+			"ERR: HTTP endpoint without scheme",
+			map[string]string{
+				"OTEL_TRACES_EXPORTER":               "otlp",
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "collector:4318",
+			},
+			true,
+		},
+		{
+			"ERR: invalid insecure value",
+			map[string]string{
+				"OTEL_TRACES_EXPORTER":        "otlp",
+				"OTEL_EXPORTER_OTLP_INSECURE": "maybe",
+			},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+
+			defer func() {
+				for k := range tt.env {
+					os.Unsetenv(k)
+				}
+			}()
+
+			conf := envvar.New(&envvartesting.FakeProvider{})
+
+			provider, err := telemetry.NewTracerProvider(context.Background(), conf, "todo")
+			if (err != nil) != tt.withErr {
+				t.Fatalf("expected error %t, got %s", tt.withErr, err)
+			}
+
+			if provider != nil {
+				_ = provider.Shutdown(context.Background())
+			}
+		})
+	}
+}
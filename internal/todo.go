@@ -60,3 +60,9 @@ func (t Task) Validate() error {
 
 	return nil
 }
+
+// TrashedTask is a Task that was deleted but can still be restored.
+type TrashedTask struct {
+	Task      Task
+	DeletedAt time.Time
+}
@@ -96,7 +96,7 @@ type ClientInterface interface {
 	SearchTask(ctx context.Context, body SearchTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// DeleteTask request
-	DeleteTask(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+	DeleteTask(ctx context.Context, taskId string, params *DeleteTaskParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// ReadTask request
 	ReadTask(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error)
@@ -106,10 +106,16 @@ type ClientInterface interface {
 
 	UpdateTask(ctx context.Context, taskId string, body UpdateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// RestoreTask request
+	RestoreTask(ctx context.Context, taskId string, params *RestoreTaskParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// CreateTask request with any body
-	CreateTaskWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+	CreateTaskWithBody(ctx context.Context, params *CreateTaskParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateTask(ctx context.Context, params *CreateTaskParams, body CreateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
-	CreateTask(ctx context.Context, body CreateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+	// ListTrashedTasks request
+	ListTrashedTasks(ctx context.Context, params *ListTrashedTasksParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
 func (c *Client) SearchTaskWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
@@ -136,8 +142,8 @@ func (c *Client) SearchTask(ctx context.Context, body SearchTaskJSONRequestBody,
 	return c.Client.Do(req)
 }
 
-func (c *Client) DeleteTask(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewDeleteTaskRequest(c.Server, taskId)
+func (c *Client) DeleteTask(ctx context.Context, taskId string, params *DeleteTaskParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteTaskRequest(c.Server, taskId, params)
 	if err != nil {
 		return nil, err
 	}
@@ -184,8 +190,20 @@ func (c *Client) UpdateTask(ctx context.Context, taskId string, body UpdateTaskJ
 	return c.Client.Do(req)
 }
 
-func (c *Client) CreateTaskWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewCreateTaskRequestWithBody(c.Server, contentType, body)
+func (c *Client) RestoreTask(ctx context.Context, taskId string, params *RestoreTaskParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRestoreTaskRequest(c.Server, taskId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateTaskWithBody(ctx context.Context, params *CreateTaskParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateTaskRequestWithBody(c.Server, params, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -196,8 +214,20 @@ func (c *Client) CreateTaskWithBody(ctx context.Context, contentType string, bod
 	return c.Client.Do(req)
 }
 
-func (c *Client) CreateTask(ctx context.Context, body CreateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewCreateTaskRequest(c.Server, body)
+func (c *Client) CreateTask(ctx context.Context, params *CreateTaskParams, body CreateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateTaskRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListTrashedTasks(ctx context.Context, params *ListTrashedTasksParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListTrashedTasksRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +258,7 @@ func NewSearchTaskRequestWithBody(server string, contentType string, body io.Rea
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/search/tasks")
+	operationPath := fmt.Sprintf("/v1/search/tasks")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -249,7 +279,7 @@ func NewSearchTaskRequestWithBody(server string, contentType string, body io.Rea
 }
 
 // NewDeleteTaskRequest generates requests for DeleteTask
-func NewDeleteTaskRequest(server string, taskId string) (*http.Request, error) {
+func NewDeleteTaskRequest(server string, taskId string, params *DeleteTaskParams) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
@@ -264,7 +294,7 @@ func NewDeleteTaskRequest(server string, taskId string) (*http.Request, error) {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/task/%s", pathParam0)
+	operationPath := fmt.Sprintf("/v1/task/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -279,6 +309,17 @@ func NewDeleteTaskRequest(server string, taskId string) (*http.Request, error) {
 		return nil, err
 	}
 
+	if params.IdempotencyKey != nil {
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Idempotency-Key", headerParam0)
+	}
+
 	return req, nil
 }
 
@@ -298,7 +339,7 @@ func NewReadTaskRequest(server string, taskId string) (*http.Request, error) {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/task/%s", pathParam0)
+	operationPath := fmt.Sprintf("/v1/task/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -343,7 +384,7 @@ func NewUpdateTaskRequestWithBody(server string, taskId string, contentType stri
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/task/%s", pathParam0)
+	operationPath := fmt.Sprintf("/v1/task/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -363,19 +404,64 @@ func NewUpdateTaskRequestWithBody(server string, taskId string, contentType stri
 	return req, nil
 }
 
+// NewRestoreTaskRequest generates requests for RestoreTask
+func NewRestoreTaskRequest(server string, taskId string, params *RestoreTaskParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "taskId", runtime.ParamLocationPath, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/task/%s/restore", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.IdempotencyKey != nil {
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Idempotency-Key", headerParam0)
+	}
+
+	return req, nil
+}
+
 // NewCreateTaskRequest calls the generic CreateTask builder with application/json body
-func NewCreateTaskRequest(server string, body CreateTaskJSONRequestBody) (*http.Request, error) {
+func NewCreateTaskRequest(server string, params *CreateTaskParams, body CreateTaskJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
 	buf, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 	bodyReader = bytes.NewReader(buf)
-	return NewCreateTaskRequestWithBody(server, "application/json", bodyReader)
+	return NewCreateTaskRequestWithBody(server, params, "application/json", bodyReader)
 }
 
 // NewCreateTaskRequestWithBody generates requests for CreateTask with any type of body
-func NewCreateTaskRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+func NewCreateTaskRequestWithBody(server string, params *CreateTaskParams, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -383,7 +469,7 @@ func NewCreateTaskRequestWithBody(server string, contentType string, body io.Rea
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/tasks")
+	operationPath := fmt.Sprintf("/v1/tasks")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -400,6 +486,80 @@ func NewCreateTaskRequestWithBody(server string, contentType string, body io.Rea
 
 	req.Header.Add("Content-Type", contentType)
 
+	if params.IdempotencyKey != nil {
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Idempotency-Key", headerParam0)
+	}
+
+	return req, nil
+}
+
+// NewListTrashedTasksRequest generates requests for ListTrashedTasks
+func NewListTrashedTasksRequest(server string, params *ListTrashedTasksParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/trash")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.From != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "from", runtime.ParamLocationQuery, *params.From); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	if params.Size != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "size", runtime.ParamLocationQuery, *params.Size); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
@@ -452,7 +612,7 @@ type ClientWithResponsesInterface interface {
 	SearchTaskWithResponse(ctx context.Context, body SearchTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*SearchTaskResponse, error)
 
 	// DeleteTask request
-	DeleteTaskWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*DeleteTaskResponse, error)
+	DeleteTaskWithResponse(ctx context.Context, taskId string, params *DeleteTaskParams, reqEditors ...RequestEditorFn) (*DeleteTaskResponse, error)
 
 	// ReadTask request
 	ReadTaskWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*ReadTaskResponse, error)
@@ -462,10 +622,16 @@ type ClientWithResponsesInterface interface {
 
 	UpdateTaskWithResponse(ctx context.Context, taskId string, body UpdateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateTaskResponse, error)
 
+	// RestoreTask request
+	RestoreTaskWithResponse(ctx context.Context, taskId string, params *RestoreTaskParams, reqEditors ...RequestEditorFn) (*RestoreTaskResponse, error)
+
 	// CreateTask request with any body
-	CreateTaskWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateTaskResponse, error)
+	CreateTaskWithBodyWithResponse(ctx context.Context, params *CreateTaskParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateTaskResponse, error)
+
+	CreateTaskWithResponse(ctx context.Context, params *CreateTaskParams, body CreateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateTaskResponse, error)
 
-	CreateTaskWithResponse(ctx context.Context, body CreateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateTaskResponse, error)
+	// ListTrashedTasks request
+	ListTrashedTasksWithResponse(ctx context.Context, params *ListTrashedTasksParams, reqEditors ...RequestEditorFn) (*ListTrashedTasksResponse, error)
 }
 
 type SearchTaskResponse struct {
@@ -502,7 +668,13 @@ func (r SearchTaskResponse) StatusCode() int {
 type DeleteTaskResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON500      *struct {
+	JSON409      *struct {
+		Error *string `json:"error,omitempty"`
+	}
+	JSON422 *struct {
+		Error *string `json:"error,omitempty"`
+	}
+	JSON500 *struct {
 		Error *string `json:"error,omitempty"`
 	}
 }
@@ -577,6 +749,36 @@ func (r UpdateTaskResponse) StatusCode() int {
 	return 0
 }
 
+type RestoreTaskResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON409      *struct {
+		Error *string `json:"error,omitempty"`
+	}
+	JSON422 *struct {
+		Error *string `json:"error,omitempty"`
+	}
+	JSON500 *struct {
+		Error *string `json:"error,omitempty"`
+	}
+}
+
+// Status returns HTTPResponse.Status
+func (r RestoreTaskResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RestoreTaskResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type CreateTaskResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -586,6 +788,12 @@ type CreateTaskResponse struct {
 	JSON400 *struct {
 		Error *string `json:"error,omitempty"`
 	}
+	JSON409 *struct {
+		Error *string `json:"error,omitempty"`
+	}
+	JSON422 *struct {
+		Error *string `json:"error,omitempty"`
+	}
 	JSON500 *struct {
 		Error *string `json:"error,omitempty"`
 	}
@@ -607,6 +815,37 @@ func (r CreateTaskResponse) StatusCode() int {
 	return 0
 }
 
+type ListTrashedTasksResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Tasks *[]TrashedTask `json:"tasks,omitempty"`
+		Total *int64         `json:"total,omitempty"`
+	}
+	JSON400 *struct {
+		Error *string `json:"error,omitempty"`
+	}
+	JSON500 *struct {
+		Error *string `json:"error,omitempty"`
+	}
+}
+
+// Status returns HTTPResponse.Status
+func (r ListTrashedTasksResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListTrashedTasksResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 // SearchTaskWithBodyWithResponse request with arbitrary body returning *SearchTaskResponse
 func (c *ClientWithResponses) SearchTaskWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SearchTaskResponse, error) {
 	rsp, err := c.SearchTaskWithBody(ctx, contentType, body, reqEditors...)
@@ -625,8 +864,8 @@ func (c *ClientWithResponses) SearchTaskWithResponse(ctx context.Context, body S
 }
 
 // DeleteTaskWithResponse request returning *DeleteTaskResponse
-func (c *ClientWithResponses) DeleteTaskWithResponse(ctx context.Context, taskId string, reqEditors ...RequestEditorFn) (*DeleteTaskResponse, error) {
-	rsp, err := c.DeleteTask(ctx, taskId, reqEditors...)
+func (c *ClientWithResponses) DeleteTaskWithResponse(ctx context.Context, taskId string, params *DeleteTaskParams, reqEditors ...RequestEditorFn) (*DeleteTaskResponse, error) {
+	rsp, err := c.DeleteTask(ctx, taskId, params, reqEditors...)
 	if err != nil {
 		return nil, err
 	}
@@ -659,23 +898,41 @@ func (c *ClientWithResponses) UpdateTaskWithResponse(ctx context.Context, taskId
 	return ParseUpdateTaskResponse(rsp)
 }
 
+// RestoreTaskWithResponse request returning *RestoreTaskResponse
+func (c *ClientWithResponses) RestoreTaskWithResponse(ctx context.Context, taskId string, params *RestoreTaskParams, reqEditors ...RequestEditorFn) (*RestoreTaskResponse, error) {
+	rsp, err := c.RestoreTask(ctx, taskId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRestoreTaskResponse(rsp)
+}
+
 // CreateTaskWithBodyWithResponse request with arbitrary body returning *CreateTaskResponse
-func (c *ClientWithResponses) CreateTaskWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateTaskResponse, error) {
-	rsp, err := c.CreateTaskWithBody(ctx, contentType, body, reqEditors...)
+func (c *ClientWithResponses) CreateTaskWithBodyWithResponse(ctx context.Context, params *CreateTaskParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateTaskResponse, error) {
+	rsp, err := c.CreateTaskWithBody(ctx, params, contentType, body, reqEditors...)
 	if err != nil {
 		return nil, err
 	}
 	return ParseCreateTaskResponse(rsp)
 }
 
-func (c *ClientWithResponses) CreateTaskWithResponse(ctx context.Context, body CreateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateTaskResponse, error) {
-	rsp, err := c.CreateTask(ctx, body, reqEditors...)
+func (c *ClientWithResponses) CreateTaskWithResponse(ctx context.Context, params *CreateTaskParams, body CreateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateTaskResponse, error) {
+	rsp, err := c.CreateTask(ctx, params, body, reqEditors...)
 	if err != nil {
 		return nil, err
 	}
 	return ParseCreateTaskResponse(rsp)
 }
 
+// ListTrashedTasksWithResponse request returning *ListTrashedTasksResponse
+func (c *ClientWithResponses) ListTrashedTasksWithResponse(ctx context.Context, params *ListTrashedTasksParams, reqEditors ...RequestEditorFn) (*ListTrashedTasksResponse, error) {
+	rsp, err := c.ListTrashedTasks(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListTrashedTasksResponse(rsp)
+}
+
 // ParseSearchTaskResponse parses an HTTP response from a SearchTaskWithResponse call
 func ParseSearchTaskResponse(rsp *http.Response) (*SearchTaskResponse, error) {
 	bodyBytes, err := ioutil.ReadAll(rsp.Body)
@@ -718,6 +975,12 @@ func ParseSearchTaskResponse(rsp *http.Response) (*SearchTaskResponse, error) {
 		}
 		response.JSON500 = &dest
 
+	case rsp.StatusCode == 400:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 500:
+		// Content-type (application/problem+json) unsupported
+
 	}
 
 	return response, nil
@@ -737,6 +1000,24 @@ func ParseDeleteTaskResponse(rsp *http.Response) (*DeleteTaskResponse, error) {
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest struct {
+			Error *string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest struct {
+			Error *string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest struct {
 			Error *string `json:"error,omitempty"`
@@ -746,6 +1027,15 @@ func ParseDeleteTaskResponse(rsp *http.Response) (*DeleteTaskResponse, error) {
 		}
 		response.JSON500 = &dest
 
+	case rsp.StatusCode == 409:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 422:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 500:
+		// Content-type (application/problem+json) unsupported
+
 	}
 
 	return response, nil
@@ -783,6 +1073,9 @@ func ParseReadTaskResponse(rsp *http.Response) (*ReadTaskResponse, error) {
 		}
 		response.JSON500 = &dest
 
+	case rsp.StatusCode == 500:
+		// Content-type (application/problem+json) unsupported
+
 	}
 
 	return response, nil
@@ -820,6 +1113,67 @@ func ParseUpdateTaskResponse(rsp *http.Response) (*UpdateTaskResponse, error) {
 		}
 		response.JSON500 = &dest
 
+	case rsp.StatusCode == 400:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 500:
+		// Content-type (application/problem+json) unsupported
+
+	}
+
+	return response, nil
+}
+
+// ParseRestoreTaskResponse parses an HTTP response from a RestoreTaskWithResponse call
+func ParseRestoreTaskResponse(rsp *http.Response) (*RestoreTaskResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RestoreTaskResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest struct {
+			Error *string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest struct {
+			Error *string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest struct {
+			Error *string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	case rsp.StatusCode == 409:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 422:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 500:
+		// Content-type (application/problem+json) unsupported
+
 	}
 
 	return response, nil
@@ -857,6 +1211,24 @@ func ParseCreateTaskResponse(rsp *http.Response) (*CreateTaskResponse, error) {
 		}
 		response.JSON400 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest struct {
+			Error *string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest struct {
+			Error *string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest struct {
 			Error *string `json:"error,omitempty"`
@@ -866,6 +1238,71 @@ func ParseCreateTaskResponse(rsp *http.Response) (*CreateTaskResponse, error) {
 		}
 		response.JSON500 = &dest
 
+	case rsp.StatusCode == 400:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 409:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 422:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 500:
+		// Content-type (application/problem+json) unsupported
+
+	}
+
+	return response, nil
+}
+
+// ParseListTrashedTasksResponse parses an HTTP response from a ListTrashedTasksWithResponse call
+func ParseListTrashedTasksResponse(rsp *http.Response) (*ListTrashedTasksResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListTrashedTasksResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Tasks *[]TrashedTask `json:"tasks,omitempty"`
+			Total *int64         `json:"total,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest struct {
+			Error *string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest struct {
+			Error *string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	case rsp.StatusCode == 400:
+	// Content-type (application/problem+json) unsupported
+
+	case rsp.StatusCode == 500:
+		// Content-type (application/problem+json) unsupported
+
 	}
 
 	return response, nil
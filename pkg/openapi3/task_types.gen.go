@@ -4,6 +4,8 @@
 package openapi3
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -27,6 +29,24 @@ type Dates struct {
 // Priority defines model for Priority.
 type Priority string
 
+// ProblemDetails defines model for ProblemDetails.
+type ProblemDetails struct {
+	Code      *string                `json:"code,omitempty"`
+	Detail    *string                `json:"detail,omitempty"`
+	Errors    *ProblemDetails_Errors `json:"errors,omitempty"`
+	Instance  *string                `json:"instance,omitempty"`
+	RequestId *string                `json:"request_id,omitempty"`
+	Status    *int                   `json:"status,omitempty"`
+	Title     *string                `json:"title,omitempty"`
+	TraceId   *string                `json:"trace_id,omitempty"`
+	Type      *string                `json:"type,omitempty"`
+}
+
+// ProblemDetails_Errors defines model for ProblemDetails.Errors.
+type ProblemDetails_Errors struct {
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
 // Task defines model for Task.
 type Task struct {
 	Dates       *Dates    `json:"dates,omitempty"`
@@ -36,6 +56,19 @@ type Task struct {
 	Priority    *Priority `json:"priority,omitempty"`
 }
 
+// TrashedTask defines model for TrashedTask.
+type TrashedTask struct {
+	Dates       *Dates     `json:"dates,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Id          *string    `json:"id,omitempty"`
+	IsDone      *bool      `json:"is_done,omitempty"`
+	Priority    *Priority  `json:"priority,omitempty"`
+}
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey string
+
 // CreateTasksResponse defines model for CreateTasksResponse.
 type CreateTasksResponse struct {
 	Task *Task `json:"task,omitempty"`
@@ -46,6 +79,12 @@ type ErrorResponse struct {
 	Error *string `json:"error,omitempty"`
 }
 
+// ListTrashResponse defines model for ListTrashResponse.
+type ListTrashResponse struct {
+	Tasks *[]TrashedTask `json:"tasks,omitempty"`
+	Total *int64         `json:"total,omitempty"`
+}
+
 // ReadTasksResponse defines model for ReadTasksResponse.
 type ReadTasksResponse struct {
 	Task *Task `json:"task,omitempty"`
@@ -81,6 +120,30 @@ type UpdateTasksRequest struct {
 	Priority    *Priority `json:"priority,omitempty"`
 }
 
+// DeleteTaskParams defines parameters for DeleteTask.
+type DeleteTaskParams struct {
+	// Retrying the request with the same key replays the original response.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// RestoreTaskParams defines parameters for RestoreTask.
+type RestoreTaskParams struct {
+	// Retrying the request with the same key replays the original response.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// CreateTaskParams defines parameters for CreateTask.
+type CreateTaskParams struct {
+	// Retrying the request with the same key replays the original response.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// ListTrashedTasksParams defines parameters for ListTrashedTasks.
+type ListTrashedTasksParams struct {
+	From *int64 `json:"from,omitempty"`
+	Size *int64 `json:"size,omitempty"`
+}
+
 // SearchTaskJSONRequestBody defines body for SearchTask for application/json ContentType.
 type SearchTaskJSONRequestBody SearchTasksRequest
 
@@ -89,3 +152,56 @@ type UpdateTaskJSONRequestBody UpdateTasksRequest
 
 // CreateTaskJSONRequestBody defines body for CreateTask for application/json ContentType.
 type CreateTaskJSONRequestBody CreateTasksRequest
+
+// Getter for additional properties for ProblemDetails_Errors. Returns the specified
+// element and whether it was found
+func (a ProblemDetails_Errors) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ProblemDetails_Errors
+func (a *ProblemDetails_Errors) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ProblemDetails_Errors to handle AdditionalProperties
+func (a *ProblemDetails_Errors) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ProblemDetails_Errors to handle AdditionalProperties
+func (a ProblemDetails_Errors) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
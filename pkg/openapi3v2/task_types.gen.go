@@ -0,0 +1,213 @@
+// Package openapi3v2 provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.8.3 DO NOT EDIT.
+package openapi3v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Defines values for Priority.
+const (
+	PriorityHigh Priority = "high"
+
+	PriorityLow Priority = "low"
+
+	PriorityMedium Priority = "medium"
+
+	PriorityNone Priority = "none"
+)
+
+// Dates defines model for Dates.
+type Dates struct {
+	Due   *time.Time `json:"due"`
+	Start *time.Time `json:"start"`
+}
+
+// Priority defines model for Priority.
+type Priority string
+
+// ProblemDetails defines model for ProblemDetails.
+type ProblemDetails struct {
+	Code      *string                `json:"code,omitempty"`
+	Detail    *string                `json:"detail,omitempty"`
+	Errors    *ProblemDetails_Errors `json:"errors,omitempty"`
+	Instance  *string                `json:"instance,omitempty"`
+	RequestId *string                `json:"request_id,omitempty"`
+	Status    *int                   `json:"status,omitempty"`
+	Title     *string                `json:"title,omitempty"`
+	TraceId   *string                `json:"trace_id,omitempty"`
+	Type      *string                `json:"type,omitempty"`
+}
+
+// ProblemDetails_Errors defines model for ProblemDetails.Errors.
+type ProblemDetails_Errors struct {
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// Task defines model for Task.
+type Task struct {
+	Dates       *Dates    `json:"dates,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Id          *string   `json:"id,omitempty"`
+	IsDone      *bool     `json:"is_done,omitempty"`
+	Priority    *Priority `json:"priority,omitempty"`
+}
+
+// TrashedTask defines model for TrashedTask.
+type TrashedTask struct {
+	Dates       *Dates     `json:"dates,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Id          *string    `json:"id,omitempty"`
+	IsDone      *bool      `json:"is_done,omitempty"`
+	Priority    *Priority  `json:"priority,omitempty"`
+}
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey string
+
+// CreateTasksResponse defines model for CreateTasksResponse.
+type CreateTasksResponse struct {
+	Task *Task `json:"task,omitempty"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Error *string `json:"error,omitempty"`
+}
+
+// ListTrashResponse defines model for ListTrashResponse.
+type ListTrashResponse struct {
+	Tasks *[]TrashedTask `json:"tasks,omitempty"`
+	Total *int64         `json:"total,omitempty"`
+}
+
+// ReadTasksResponse defines model for ReadTasksResponse.
+type ReadTasksResponse struct {
+	Task *Task `json:"task,omitempty"`
+}
+
+// SearchTasksResponse defines model for SearchTasksResponse.
+type SearchTasksResponse struct {
+	Tasks *[]Task `json:"tasks,omitempty"`
+	Total *int64  `json:"total,omitempty"`
+}
+
+// CreateTasksRequest defines model for CreateTasksRequest.
+type CreateTasksRequest struct {
+	Dates       *Dates    `json:"dates,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Priority    *Priority `json:"priority,omitempty"`
+}
+
+// PatchTasksRequest defines model for PatchTasksRequest.
+type PatchTasksRequest struct {
+	Dates       *Dates    `json:"dates,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	IsDone      *bool     `json:"is_done,omitempty"`
+	Priority    *Priority `json:"priority,omitempty"`
+}
+
+// SearchTasksRequest defines model for SearchTasksRequest.
+type SearchTasksRequest struct {
+	Description *string   `json:"description"`
+	From        *int64    `json:"from,omitempty"`
+	IsDone      *bool     `json:"is_done"`
+	Priority    *Priority `json:"priority,omitempty"`
+	Size        *int64    `json:"size,omitempty"`
+}
+
+// CreateTaskParams defines parameters for CreateTask.
+type CreateTaskParams struct {
+	// Retrying the request with the same key replays the original response.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// DeleteTaskParams defines parameters for DeleteTask.
+type DeleteTaskParams struct {
+	// Retrying the request with the same key replays the original response.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PatchTaskParams defines parameters for PatchTask.
+type PatchTaskParams struct {
+	// Retrying the request with the same key replays the original response.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// RestoreTaskParams defines parameters for RestoreTask.
+type RestoreTaskParams struct {
+	// Retrying the request with the same key replays the original response.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// ListTrashedTasksParams defines parameters for ListTrashedTasks.
+type ListTrashedTasksParams struct {
+	From *int64 `json:"from,omitempty"`
+	Size *int64 `json:"size,omitempty"`
+}
+
+// SearchTaskJSONRequestBody defines body for SearchTask for application/json ContentType.
+type SearchTaskJSONRequestBody SearchTasksRequest
+
+// CreateTaskJSONRequestBody defines body for CreateTask for application/json ContentType.
+type CreateTaskJSONRequestBody CreateTasksRequest
+
+// PatchTaskJSONRequestBody defines body for PatchTask for application/json ContentType.
+type PatchTaskJSONRequestBody PatchTasksRequest
+
+// Getter for additional properties for ProblemDetails_Errors. Returns the specified
+// element and whether it was found
+func (a ProblemDetails_Errors) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ProblemDetails_Errors
+func (a *ProblemDetails_Errors) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ProblemDetails_Errors to handle AdditionalProperties
+func (a *ProblemDetails_Errors) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ProblemDetails_Errors to handle AdditionalProperties
+func (a ProblemDetails_Errors) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}